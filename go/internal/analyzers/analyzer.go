@@ -0,0 +1,92 @@
+package analyzers
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+)
+
+// AnalysisResult holds the metrics of an analyzed source file
+type AnalysisResult struct {
+	Complexity    int      `json:"complexity"`
+	FunctionCount int      `json:"functionCount"`
+	LineCount     int      `json:"lineCount"`
+	Imports       []string `json:"imports"`
+}
+
+// CodeAnalyzer computes code metrics of source files. Only Go files are supported.
+type CodeAnalyzer struct {
+	analyzer *GoAnalyzer
+}
+
+// NewCodeAnalyzer creates a new code analyzer
+func NewCodeAnalyzer() *CodeAnalyzer {
+	return &CodeAnalyzer{
+		analyzer: NewGoAnalyzer(),
+	}
+}
+
+// AnalyzeFile parses a file and computes its metrics. The complexity of a file is
+// the sum of the cyclomatic complexity of its functions, each being one plus the
+// number of branches in the function. Files that do not parse return an error.
+func (ca *CodeAnalyzer) AnalyzeFile(path string) (*AnalysisResult, error) {
+	if language := GetFileLanguage(path); language != "go" {
+		return nil, fmt.Errorf("unsupported language for %s: %s", path, language)
+	}
+
+	node, err := ca.analyzer.ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := node.(*ast.File)
+
+	result := &AnalysisResult{
+		Imports: []string{},
+	}
+	if tokenFile := ca.analyzer.fset.File(file.Pos()); tokenFile != nil {
+		result.LineCount = tokenFile.LineCount()
+	}
+	for _, imp := range ca.analyzer.ExtractImports(file) {
+		if unquoted, err := strconv.Unquote(imp); err == nil {
+			imp = unquoted
+		}
+		result.Imports = append(result.Imports, imp)
+	}
+	for _, fn := range ca.analyzer.ExtractFunctions(file) {
+		result.FunctionCount++
+		result.Complexity += 1 + countBranches(fn.(*ast.FuncDecl).Body)
+	}
+
+	return result, nil
+}
+
+// countBranches counts the decision points below a node: conditionals, loops,
+// non-default cases and short-circuit operators
+func countBranches(node ast.Node) int {
+	if node == nil {
+		return 0
+	}
+
+	branches := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			branches++
+		case *ast.CaseClause:
+			if n.List != nil {
+				branches++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				branches++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				branches++
+			}
+		}
+		return true
+	})
+	return branches
+}
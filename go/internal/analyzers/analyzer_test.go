@@ -0,0 +1,63 @@
+package analyzers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试计算圈复杂度、函数数、行数和导入
+func TestCodeAnalyzer(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "analyzer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	code := `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Println(classify(len(os.Args)))
+}
+
+func classify(n int) string {
+	if n > 10 && n < 100 {
+		return "medium"
+	}
+	for i := 0; i < n; i++ {
+		switch {
+		case i%2 == 0:
+			continue
+		default:
+			return "odd"
+		}
+	}
+	return "small"
+}
+`
+	path := filepath.Join(tmpdir, "main.go")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(code), 0644))
+
+	result, err := NewCodeAnalyzer().AnalyzeFile(path)
+	assert.NoError(t, err)
+	// main: 1; classify: 1 + if + && + for + case
+	assert.Equal(t, 6, result.Complexity)
+	assert.Equal(t, 2, result.FunctionCount)
+	assert.Equal(t, 25, result.LineCount)
+	assert.Equal(t, []string{"fmt", "os"}, result.Imports)
+
+	// 无法解析的文件返回错误
+	invalid := filepath.Join(tmpdir, "invalid.go")
+	assert.NoError(t, ioutil.WriteFile(invalid, []byte("invalid go code"), 0644))
+	_, err = NewCodeAnalyzer().AnalyzeFile(invalid)
+	assert.Error(t, err)
+
+	_, err = NewCodeAnalyzer().AnalyzeFile(filepath.Join(tmpdir, "missing.go"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package analyzers
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// sniffSize is the number of bytes at the start of a file that content detection inspects
+const sniffSize = 4096
+
+// minContentEvidence is the number of lines characteristic of a language that a file
+// without a shebang must contain for its language to be inferred
+const minContentEvidence = 2
+
+// shebangLanguages maps the interpreters of shebang lines, without version numbers,
+// to languages
+var shebangLanguages = map[string]string{
+	"python": "python",
+	"node":   "javascript",
+	"nodejs": "javascript",
+	"deno":   "javascript",
+	"bun":    "javascript",
+	"sh":     "shell",
+	"bash":   "shell",
+	"dash":   "shell",
+	"ksh":    "shell",
+	"zsh":    "shell",
+	"ruby":   "ruby",
+	"perl":   "perl",
+	"php":    "php",
+}
+
+// contentPatterns match lines that are characteristic of a language
+var contentPatterns = map[string]*regexp.Regexp{
+	"python":     regexp.MustCompile(`^(def \w+\(.*\)\s*(->.*)?:|class \w+(\(.*\))?:|import [\w.]+(\s+as\s+\w+)?(\s*,\s*[\w.]+)*$|from [\w.]+ import |if __name__ == ['"]__main__['"]:|print\(.*\)$)`),
+	"javascript": regexp.MustCompile(`^(function\*?\s*\w*\s*\(|(const|let|var)\s+[\w{}\[\], ]+\s*=|module\.exports\b|exports\.\w+\s*=|(const|let|var)\s+\w+\s*=\s*require\(|import .* from ['"]|export (default|const|function|class)\b|console\.log\()`),
+	"go":         regexp.MustCompile(`^(package \w+$|func (\(\w+ \*?\w+\) )?\w+\(|import \($)`),
+}
+
+// versionSuffixRe matches the version number of an interpreter name, e.g. the 3.11 of python3.11
+var versionSuffixRe = regexp.MustCompile(`[\d.]+$`)
+
+// GetFileLanguageFromContent infers the programming language of a file from its
+// content, for files whose extension does not tell it. A shebang line decides the
+// language; otherwise the language with the most characteristic lines at the start of
+// the file is chosen, if there are at least two. Binary and unreadable files, and files
+// without a clear language, return "unknown".
+func GetFileLanguageFromContent(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return "unknown"
+	}
+	defer file.Close()
+
+	content := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, content)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "unknown"
+	}
+	content = content[:n]
+	if bytes.IndexByte(content, 0) >= 0 {
+		return "unknown"
+	}
+
+	text := string(content)
+	if strings.HasPrefix(text, "#!") {
+		firstLine := strings.SplitN(text, "\n", 2)[0]
+		return shebangLanguage(firstLine)
+	}
+	if strings.HasPrefix(strings.TrimSpace(text), "<?php") {
+		return "php"
+	}
+
+	scores := make(map[string]int)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		for language, pattern := range contentPatterns {
+			if pattern.MatchString(line) {
+				scores[language]++
+			}
+		}
+	}
+
+	// The best language must be ahead of all others
+	best, bestScore, tied := "unknown", 0, false
+	for language, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = language, score, false
+		case score == bestScore:
+			tied = true
+		}
+	}
+	if tied || bestScore < minContentEvidence {
+		return "unknown"
+	}
+	return best
+}
+
+// shebangLanguage returns the language of the interpreter of a shebang line, e.g.
+// #!/usr/bin/python3 or #!/usr/bin/env -S node --harmony
+func shebangLanguage(line string) string {
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				interpreter = filepath.Base(field)
+				break
+			}
+		}
+	}
+
+	if language, ok := shebangLanguages[versionSuffixRe.ReplaceAllString(interpreter, "")]; ok {
+		return language
+	}
+	return "unknown"
+}
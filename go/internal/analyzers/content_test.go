@@ -0,0 +1,46 @@
+package analyzers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试根据shebang和内容特征推断语言
+func TestGetFileLanguageFromContent(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "content-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"#!/usr/bin/env python3\nprint('hi')\n", "python"},
+		{"#!/usr/bin/python3.11\n", "python"},
+		{"#!/usr/bin/env -S node --no-warnings\nconsole.log(1)\n", "javascript"},
+		{"#!/bin/bash\necho hi\n", "shell"},
+		{"#!/usr/bin/env ruby\n", "ruby"},
+		{"#!/usr/local/bin/unknown-interpreter\n", "unknown"},
+		{"<?php\necho $_GET['x'];\n", "php"},
+		{"import os\nimport sys\n\ndef main():\n    os.system(sys.argv[1])\n", "python"},
+		{"const express = require('express');\nconst app = express();\nmodule.exports = app;\n", "javascript"},
+		{"package main\n\nfunc main() {\n}\n", "go"},
+		// 证据不足或没有明确语言时返回unknown
+		{"eval(x)\n", "unknown"},
+		{"This is a plain text file.\nIt has two lines.\n", "unknown"},
+		{"import os\nconst x = require('x');\n", "unknown"},
+		{"\x00\x01\x02#!/bin/sh\n", "unknown"},
+	}
+
+	for i, test := range tests {
+		path := filepath.Join(tmpdir, "file"+string(rune('a'+i)))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(test.content), 0644))
+		assert.Equal(t, test.expected, GetFileLanguageFromContent(path), test.content)
+	}
+
+	assert.Equal(t, "unknown", GetFileLanguageFromContent(filepath.Join(tmpdir, "missing")))
+}
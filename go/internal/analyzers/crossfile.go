@@ -0,0 +1,128 @@
+package analyzers
+
+import (
+	"go/ast"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Definition is the location of a Go function declaration
+type Definition struct {
+	Name string
+	File string
+	Line int
+}
+
+// CallSite is a call of an indexed function
+type CallSite struct {
+	File       string
+	Line       int
+	Definition Definition
+}
+
+// CallIndex resolves the calls between a set of Go files to the functions declared in
+// them. Calls are resolved by name: unqualified calls to the functions of the same
+// package directory, and pkg.Func calls to the functions of the package with that
+// name. Methods and ambiguous names are not resolved.
+type CallIndex struct {
+	analyzer *GoAnalyzer
+	byDir    map[string][]Definition
+	byPkg    map[string][]Definition
+	calls    []CallSite
+}
+
+// NewCallIndex parses the given Go files and indexes their function declarations and
+// the calls between them. Files that do not parse are skipped.
+func NewCallIndex(files []string) *CallIndex {
+	index := &CallIndex{
+		analyzer: NewGoAnalyzer(),
+		byDir:    make(map[string][]Definition),
+		byPkg:    make(map[string][]Definition),
+	}
+
+	// Index declarations first so that calls can be resolved regardless of file order
+	parsed := make(map[string]*ast.File)
+	for _, file := range files {
+		node, err := index.analyzer.ParseFile(file)
+		if err != nil {
+			continue
+		}
+		f := node.(*ast.File)
+		parsed[file] = f
+
+		for _, fn := range index.analyzer.ExtractFunctions(f) {
+			decl := fn.(*ast.FuncDecl)
+			if decl.Recv != nil {
+				continue
+			}
+			def := Definition{
+				Name: decl.Name.Name,
+				File: file,
+				Line: index.analyzer.fset.Position(decl.Pos()).Line,
+			}
+			dirKey := filepath.Dir(file) + "." + def.Name
+			pkgKey := f.Name.Name + "." + def.Name
+			index.byDir[dirKey] = append(index.byDir[dirKey], def)
+			index.byPkg[pkgKey] = append(index.byPkg[pkgKey], def)
+		}
+	}
+
+	for _, file := range files {
+		f, ok := parsed[file]
+		if !ok {
+			continue
+		}
+		imported := importNames(index.analyzer.ExtractImports(f))
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			var candidates []Definition
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				candidates = index.byDir[filepath.Dir(file)+"."+fun.Name]
+			case *ast.SelectorExpr:
+				if x, ok := fun.X.(*ast.Ident); ok && imported[x.Name] {
+					candidates = index.byPkg[x.Name+"."+fun.Sel.Name]
+				}
+			}
+			if len(candidates) == 1 {
+				index.calls = append(index.calls, CallSite{
+					File:       file,
+					Line:       index.analyzer.fset.Position(call.Pos()).Line,
+					Definition: candidates[0],
+				})
+			}
+			return true
+		})
+	}
+
+	return index
+}
+
+// CallsAt returns the definitions of the functions called on a line of a file
+func (ci *CallIndex) CallsAt(file string, line int) []Definition {
+	var defs []Definition
+	for _, call := range ci.calls {
+		if call.File == file && call.Line == line {
+			defs = append(defs, call.Definition)
+		}
+	}
+	return defs
+}
+
+// importNames returns the names under which quoted import paths are referenced
+func importNames(paths []string) map[string]bool {
+	names := make(map[string]bool)
+	for _, path := range paths {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			path = unquoted
+		}
+		names[path[strings.LastIndex(path, "/")+1:]] = true
+	}
+	return names
+}
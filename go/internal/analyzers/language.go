@@ -81,7 +81,8 @@ func (ga *GoAnalyzer) ExtractVariables(node ast.Node) []ast.Node {
     return variables
 }
 
-// GetFileLanguage determines the programming language of a file
+// GetFileLanguage determines the programming language of a file by its extension.
+// See GetFileLanguageFromContent for files whose extension does not tell it.
 func GetFileLanguage(filename string) string {
     ext := filepath.Ext(filename)
     switch ext {
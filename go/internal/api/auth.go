@@ -0,0 +1,84 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokensEnvVar is the environment variable holding comma-separated API tokens
+const TokensEnvVar = "REMOVERY_API_TOKENS"
+
+// SetAuth enables or disables bearer token authentication. When required, every
+// request except the health check must carry "Authorization: Bearer <token>" with
+// one of the given tokens.
+func (s *Server) SetAuth(required bool, tokens []string) {
+	s.requireAuth = required
+	s.tokens = nil
+	for _, token := range tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			s.tokens = append(s.tokens, token)
+		}
+	}
+}
+
+// TokensFromEnv returns the API tokens set in the TokensEnvVar environment variable,
+// skipping empty ones
+func TokensFromEnv() []string {
+	var tokens []string
+	for _, token := range strings.Split(os.Getenv(TokensEnvVar), ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// authMiddleware rejects requests without a valid bearer token when authentication is required
+func (s *Server) authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.requireAuth || c.Request.URL.Path == "/health" {
+			c.Next()
+			return
+		}
+
+		if !s.validToken(c.GetHeader("Authorization")) {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Unauthorized",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// validToken reports whether an Authorization header carries one of the configured tokens
+func (s *Server) validToken(header string) bool {
+	token, ok := bearerToken(header)
+	if !ok {
+		return false
+	}
+
+	// Compare every token in constant time so the response time does not leak which one matched
+	valid := false
+	for _, expected := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// bearerToken returns the token of an "Authorization: Bearer <token>" header
+func bearerToken(header string) (string, bool) {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(prefix):]), true
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// redactedToken replaces the API tokens in the reported configuration
+const redactedToken = "[REDACTED]"
+
+// SecuritySettings are the authentication and rate limit settings of the /api routes
+type SecuritySettings struct {
+	RequireAuth      bool     `json:"requireAuth" yaml:"requireAuth"`
+	APITokens        []string `json:"apiTokens" yaml:"apiTokens"`
+	RateLimitPerHour int      `json:"rateLimitPerHour" yaml:"rateLimitPerHour"`
+}
+
+// EffectiveConfig is the configuration a server runs with: the settings, the
+// security settings with the API tokens redacted, and the registered detectors
+type EffectiveConfig struct {
+	Config    *core.Config        `json:"config" yaml:"config"`
+	Security  SecuritySettings    `json:"security" yaml:"security"`
+	Detectors []core.DetectorInfo `json:"detectors" yaml:"detectors"`
+}
+
+// RedactTokens returns a placeholder for each token, so that the number of tokens
+// can be checked without revealing them
+func RedactTokens(tokens []string) []string {
+	redacted := make([]string, len(tokens))
+	for i := range tokens {
+		redacted[i] = redactedToken
+	}
+	return redacted
+}
+
+// SetConfig applies the scanner settings of a configuration to the server's scanner
+// and reports the configuration at GET /api/config
+func (s *Server) SetConfig(config *core.Config) error {
+	if err := config.ApplyToScanner(s.scanner); err != nil {
+		return err
+	}
+	s.config = config
+	return nil
+}
+
+// EffectiveConfig returns the configuration the server runs with
+func (s *Server) EffectiveConfig() EffectiveConfig {
+	rateLimit := 0
+	if s.limiter != nil {
+		rateLimit = s.limiter.limit
+	}
+	return EffectiveConfig{
+		Config: s.config,
+		Security: SecuritySettings{
+			RequireAuth:      s.requireAuth,
+			APITokens:        RedactTokens(s.tokens),
+			RateLimitPerHour: rateLimit,
+		},
+		Detectors: s.scanner.Detectors(),
+	}
+}
+
+// configHandler handles the effective configuration request
+func (s *Server) configHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, s.EffectiveConfig())
+}
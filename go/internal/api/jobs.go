@@ -0,0 +1,395 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+const (
+	// jobWorkers is the number of directory scan jobs run at the same time
+	jobWorkers = 2
+
+	// jobQueueSize is the number of jobs that can wait for a worker
+	jobQueueSize = 100
+
+	// finishedJobTTL is how long the results of a finished job are kept
+	finishedJobTTL = time.Hour
+
+	// maxFinishedJobs is the number of finished jobs kept; beyond it the jobs that
+	// finished first are dropped, even before finishedJobTTL
+	maxFinishedJobs = 100
+
+	// defaultPageSize and maxPageSize bound the number of matches in a page of job results
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// JobStatus is the state of an asynchronous scan job
+type JobStatus string
+
+// Job statuses reported by GET /api/jobs/:id. Cancelled jobs are failed with the error "cancelled".
+const (
+	JobPending JobStatus = "pending"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+// scanJob is a directory scan run by the worker pool
+type scanJob struct {
+	id              string
+	directory       string
+	excludePatterns []string
+	parallel        bool
+	incremental     bool
+
+	server *Server
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Guarded by the server's jobsMutex
+	status     JobStatus
+	err        string
+	results    map[string][]core.Match
+	scanErrors []core.ScanError
+	summary    core.Summary
+	createdAt  time.Time
+	finishedAt time.Time
+}
+
+// Execute runs the scan unless the job was cancelled while it was queued
+func (j *scanJob) Execute() error {
+	s := j.server
+
+	s.jobsMutex.Lock()
+	if j.status != JobPending {
+		s.jobsMutex.Unlock()
+		return nil
+	}
+	j.status = JobRunning
+	s.jobsMutex.Unlock()
+
+	// Set scanner options on a scanner of the job's own, as other jobs scan at the same time
+	scanner := s.scanner.Clone()
+	scanner.SetParallel(j.parallel)
+	scanner.SetIncremental(j.incremental)
+
+	results, scanErrors, err := scanner.ScanDirectoryErrors(j.ctx, j.directory, j.excludePatterns)
+
+	s.jobsMutex.Lock()
+	j.finishedAt = time.Now()
+	s.evictJobs(j.finishedAt)
+	if err != nil {
+		j.status = JobFailed
+		j.err = err.Error()
+		if j.ctx.Err() != nil {
+			j.err = "cancelled"
+		}
+	} else {
+		j.status = JobDone
+		j.results = results
+		j.scanErrors = scanErrors
+		j.summary = core.GenerateSummary(results)
+	}
+	s.jobsMutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	// Notify new findings
+	s.notifyFindings(results)
+	return nil
+}
+
+// evictJobs drops the finished jobs that are older than finishedJobTTL or beyond
+// maxFinishedJobs, so that their results are not kept forever.
+// The caller must hold the server's jobsMutex.
+func (s *Server) evictJobs(now time.Time) {
+	var finished []*scanJob
+	for id, job := range s.jobs {
+		if job.finishedAt.IsZero() {
+			continue
+		}
+		if now.Sub(job.finishedAt) > finishedJobTTL {
+			delete(s.jobs, id)
+			continue
+		}
+		finished = append(finished, job)
+	}
+
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].finishedAt.Before(finished[j].finishedAt)
+	})
+	for _, job := range finished[:len(finished)-maxFinishedJobs] {
+		delete(s.jobs, job.id)
+	}
+}
+
+// snapshot returns the JSON representation of the job.
+// The caller must hold the server's jobsMutex.
+func (j *scanJob) snapshot() gin.H {
+	response := gin.H{
+		"jobId":     j.id,
+		"status":    j.status,
+		"directory": j.directory,
+		"createdAt": j.createdAt.Format(time.RFC3339),
+	}
+	if !j.finishedAt.IsZero() {
+		response["finishedAt"] = j.finishedAt.Format(time.RFC3339)
+	}
+	if j.err != "" {
+		response["error"] = j.err
+	}
+	if j.status == JobDone {
+		response["results"] = j.results
+		response["errors"] = j.scanErrors
+		response["summary"] = j.summary
+	}
+	return response
+}
+
+// resultsQuery filters and paginates the matches of a job, given by the severity, file,
+// page and pageSize query parameters of GET /api/jobs/:id
+type resultsQuery struct {
+	severity string
+	file     string
+	page     int
+	pageSize int
+}
+
+// parseResultsQuery parses the query parameters of a job request. It returns false if
+// none are given, in which case the results are returned in full.
+func parseResultsQuery(c *gin.Context) (resultsQuery, bool, error) {
+	query := resultsQuery{
+		severity: strings.ToLower(c.Query("severity")),
+		file:     c.Query("file"),
+		page:     1,
+		pageSize: defaultPageSize,
+	}
+	given := query.severity != "" || query.file != ""
+
+	switch query.severity {
+	case "", "high", "medium", "low":
+	default:
+		return query, false, fmt.Errorf("invalid severity: %s (expected high, medium or low)", query.severity)
+	}
+
+	if value, ok := c.GetQuery("page"); ok {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return query, false, fmt.Errorf("invalid page: %s (expected a number from 1)", value)
+		}
+		query.page = page
+		given = true
+	}
+	if value, ok := c.GetQuery("pageSize"); ok {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			return query, false, fmt.Errorf("invalid pageSize: %s (expected 1-%d)", value, maxPageSize)
+		}
+		query.pageSize = pageSize
+		given = true
+	}
+
+	return query, given, nil
+}
+
+// apply returns the page of matches that pass the filters, ordered by file path and
+// line number, and the number of matches that pass the filters
+func (q resultsQuery) apply(results map[string][]core.Match) ([]core.Match, int) {
+	filePaths := make([]string, 0, len(results))
+	for filePath := range results {
+		if strings.Contains(filePath, q.file) {
+			filePaths = append(filePaths, filePath)
+		}
+	}
+	sort.Strings(filePaths)
+
+	filtered := []core.Match{}
+	for _, filePath := range filePaths {
+		matches := []core.Match{}
+		for _, match := range results[filePath] {
+			if q.severity == "" || core.NormalizeSeverity(match.Signature.Severity) == q.severity {
+				matches = append(matches, match)
+			}
+		}
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].LineNumber < matches[j].LineNumber
+		})
+		filtered = append(filtered, matches...)
+	}
+
+	// Pages past the end are checked before multiplying so that large pages cannot overflow
+	if q.page-1 >= (len(filtered)+q.pageSize-1)/q.pageSize {
+		return []core.Match{}, len(filtered)
+	}
+	start := (q.page - 1) * q.pageSize
+	end := start + q.pageSize
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+	return filtered[start:end], len(filtered)
+}
+
+// newJobID returns a random job identifier
+func newJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// createJobHandler enqueues a directory scan and returns its job ID without waiting for it
+func (s *Server) createJobHandler(c *gin.Context) {
+	// Parse request
+	var request struct {
+		Directory       string   `json:"directory" binding:"required"`
+		ExcludePatterns []string `json:"excludePatterns"`
+		Parallel        bool     `json:"parallel"`
+		Incremental     bool     `json:"incremental"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	// Check if directory exists
+	if _, err := os.Stat(request.Directory); os.IsNotExist(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Directory does not exist",
+		})
+		return
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to create job: %v", err),
+		})
+		return
+	}
+
+	// Jobs outlive the request, so they get their own context
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &scanJob{
+		id:              id,
+		directory:       request.Directory,
+		excludePatterns: request.ExcludePatterns,
+		parallel:        request.Parallel,
+		incremental:     request.Incremental,
+		server:          s,
+		ctx:             ctx,
+		cancel:          cancel,
+		status:          JobPending,
+		createdAt:       time.Now(),
+	}
+
+	s.jobsMutex.Lock()
+	s.evictJobs(job.createdAt)
+	s.jobs[id] = job
+	s.jobsMutex.Unlock()
+
+	if !s.pool.TrySubmit(job) {
+		cancel()
+		s.jobsMutex.Lock()
+		delete(s.jobs, id)
+		s.jobsMutex.Unlock()
+
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Too many queued jobs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"jobId":  id,
+		"status": JobPending,
+	})
+}
+
+// getJobHandler returns the status of a job and, once it is done, its results. With the
+// severity, file, page or pageSize query parameters, the results are replaced by a page
+// of the matching matches, so that clients of large scans need not load them all.
+func (s *Server) getJobHandler(c *gin.Context) {
+	query, paginated, err := parseResultsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	s.jobsMutex.RLock()
+	defer s.jobsMutex.RUnlock()
+
+	job, ok := s.jobs[c.Param("id")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Job not found",
+		})
+		return
+	}
+
+	response := job.snapshot()
+	if paginated && job.status == JobDone {
+		matches, total := query.apply(job.results)
+		delete(response, "results")
+		response["matches"] = matches
+		response["total"] = total
+		response["page"] = query.page
+		response["pageSize"] = query.pageSize
+		response["totalPages"] = (total + query.pageSize - 1) / query.pageSize
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// cancelJobHandler cancels a pending or running job
+func (s *Server) cancelJobHandler(c *gin.Context) {
+	s.jobsMutex.Lock()
+	defer s.jobsMutex.Unlock()
+
+	job, ok := s.jobs[c.Param("id")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Job not found",
+		})
+		return
+	}
+
+	switch job.status {
+	case JobDone, JobFailed:
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Job already finished",
+		})
+		return
+	case JobPending:
+		// The worker skips the job when it is dequeued
+		job.status = JobFailed
+		job.err = "cancelled"
+		job.finishedAt = time.Now()
+	}
+
+	// A running scan stops before its next file
+	job.cancel()
+
+	c.JSON(http.StatusOK, job.snapshot())
+}
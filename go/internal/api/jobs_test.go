@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 发送请求并解析JSON响应
+func doJSON(t *testing.T, server *Server, method string, path string, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w.Code, response
+}
+
+// 测试异步扫描任务的创建和查询
+func TestScanJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	tmpdir, err := ioutil.TempDir("", "api-job-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "vuln.py"), []byte("result = eval(user_input)\n"), 0644))
+
+	body, _ := json.Marshal(map[string]string{"directory": tmpdir})
+	code, response := doJSON(t, server, http.MethodPost, "/api/jobs", string(body))
+	assert.Equal(t, http.StatusAccepted, code)
+	id, _ := response["jobId"].(string)
+	assert.NotEmpty(t, id)
+
+	// 轮询直到任务完成
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		code, response = doJSON(t, server, http.MethodGet, "/api/jobs/"+id, "")
+		assert.Equal(t, http.StatusOK, code)
+		if response["status"] == string(JobDone) || response["status"] == string(JobFailed) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, string(JobDone), response["status"])
+	if summary, ok := response["summary"].(map[string]interface{}); assert.True(t, ok) {
+		assert.Equal(t, float64(1), summary["high"])
+	}
+
+	// 已完成的任务不能取消
+	code, _ = doJSON(t, server, http.MethodDelete, "/api/jobs/"+id, "")
+	assert.Equal(t, http.StatusConflict, code)
+
+	// 未知的任务
+	code, _ = doJSON(t, server, http.MethodGet, "/api/jobs/unknown", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// 不存在的目录
+	code, _ = doJSON(t, server, http.MethodPost, "/api/jobs", `{"directory": "/re-movery-does-not-exist"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+// 测试取消排队中的任务
+func TestCancelPendingJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &scanJob{
+		id:        "queued",
+		directory: os.TempDir(),
+		server:    server,
+		ctx:       ctx,
+		cancel:    cancel,
+		status:    JobPending,
+		createdAt: time.Now(),
+	}
+	server.jobs[job.id] = job
+
+	code, response := doJSON(t, server, http.MethodDelete, "/api/jobs/queued", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, string(JobFailed), response["status"])
+	assert.Equal(t, "cancelled", response["error"])
+	assert.Error(t, ctx.Err())
+
+	// 工作者取出任务后不会再扫描
+	assert.NoError(t, job.Execute())
+	assert.Equal(t, JobFailed, job.status)
+}
+
+// 测试并发的任务各自使用自己的扫描设置，不修改服务器的扫描器
+func TestConcurrentScanJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	tmpdir, err := ioutil.TempDir("", "api-job-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, fmt.Sprintf("vuln%d.py", i)), []byte("result = eval(user_input)\n"), 0644))
+	}
+
+	ids := []string{}
+	for _, parallel := range []bool{true, false, true, false} {
+		body, _ := json.Marshal(map[string]interface{}{"directory": tmpdir, "parallel": parallel, "incremental": parallel})
+		code, response := doJSON(t, server, http.MethodPost, "/api/jobs", string(body))
+		assert.Equal(t, http.StatusAccepted, code)
+		ids = append(ids, response["jobId"].(string))
+	}
+
+	for _, id := range ids {
+		var response map[string]interface{}
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			_, response = doJSON(t, server, http.MethodGet, "/api/jobs/"+id, "")
+			if response["status"] == string(JobDone) || response["status"] == string(JobFailed) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, string(JobDone), response["status"])
+		assert.Len(t, response["results"], 20)
+	}
+	assert.False(t, server.scanner.IsParallel())
+	assert.False(t, server.scanner.IsIncremental())
+}
+
+// 测试过期和超出数量上限的已完成任务被移除
+func TestEvictJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+	now := time.Now()
+
+	addDoneJob(server, "expired", nil)
+	server.jobs["expired"].finishedAt = now.Add(-finishedJobTTL - time.Minute)
+	server.jobs["running"] = &scanJob{id: "running", status: JobRunning, createdAt: now.Add(-2 * finishedJobTTL)}
+	for i := 0; i <= maxFinishedJobs; i++ {
+		id := fmt.Sprintf("done%d", i)
+		addDoneJob(server, id, nil)
+		server.jobs[id].finishedAt = now.Add(time.Duration(i-maxFinishedJobs) * time.Second)
+	}
+
+	server.evictJobs(now)
+	assert.NotContains(t, server.jobs, "expired")
+	assert.NotContains(t, server.jobs, "done0")
+	assert.Contains(t, server.jobs, "done1")
+	assert.Contains(t, server.jobs, "running")
+	assert.Len(t, server.jobs, maxFinishedJobs+1)
+}
+
+// 创建包含指定结果的已完成任务
+func addDoneJob(server *Server, id string, results map[string][]core.Match) {
+	ctx, cancel := context.WithCancel(context.Background())
+	server.jobs[id] = &scanJob{
+		id:         id,
+		directory:  os.TempDir(),
+		server:     server,
+		ctx:        ctx,
+		cancel:     cancel,
+		status:     JobDone,
+		results:    results,
+		summary:    core.GenerateSummary(results),
+		createdAt:  time.Now(),
+		finishedAt: time.Now(),
+	}
+}
+
+// 返回响应中匹配的文件和行号
+func matchLocations(response map[string]interface{}) []string {
+	locations := []string{}
+	matches, _ := response["matches"].([]interface{})
+	for _, m := range matches {
+		match, _ := m.(map[string]interface{})
+		locations = append(locations, fmt.Sprintf("%v:%v", match["filePath"], match["lineNumber"]))
+	}
+	return locations
+}
+
+// 测试按严重程度和文件过滤任务结果并分页，包括分页边界
+func TestJobResultsPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	high := core.Signature{ID: "PY001", Severity: "high"}
+	low := core.Signature{ID: "PY012", Severity: "low"}
+	addDoneJob(server, "big", map[string][]core.Match{
+		"src/b.py": {
+			{Signature: high, FilePath: "src/b.py", LineNumber: 9},
+			{Signature: high, FilePath: "src/b.py", LineNumber: 2},
+		},
+		"src/a.py": {
+			{Signature: low, FilePath: "src/a.py", LineNumber: 1},
+			{Signature: high, FilePath: "src/a.py", LineNumber: 5},
+		},
+		"test/a_test.py": {
+			{Signature: high, FilePath: "test/a_test.py", LineNumber: 3},
+		},
+	})
+
+	// 没有查询参数时返回完整结果
+	code, response := doJSON(t, server, http.MethodGet, "/api/jobs/big", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, response, "results")
+	assert.NotContains(t, response, "matches")
+
+	// 按文件路径和行号排序分页
+	code, response = doJSON(t, server, http.MethodGet, "/api/jobs/big?pageSize=2", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.NotContains(t, response, "results")
+	assert.Equal(t, []string{"src/a.py:1", "src/a.py:5"}, matchLocations(response))
+	assert.Equal(t, float64(5), response["total"])
+	assert.Equal(t, float64(3), response["totalPages"])
+	assert.Equal(t, float64(1), response["page"])
+
+	_, response = doJSON(t, server, http.MethodGet, "/api/jobs/big?pageSize=2&page=3", "")
+	assert.Equal(t, []string{"test/a_test.py:3"}, matchLocations(response))
+
+	// 超出最后一页时返回空列表
+	_, response = doJSON(t, server, http.MethodGet, "/api/jobs/big?pageSize=2&page=4", "")
+	assert.Equal(t, []string{}, matchLocations(response))
+	assert.Equal(t, float64(5), response["total"])
+
+	// 极大的页码不会溢出
+	code, response = doJSON(t, server, http.MethodGet, "/api/jobs/big?page=9223372036854775807&pageSize=1000", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{}, matchLocations(response))
+
+	_, response = doJSON(t, server, http.MethodGet, "/api/jobs/big?pageSize=5", "")
+	assert.Len(t, matchLocations(response), 5)
+	assert.Equal(t, float64(1), response["totalPages"])
+
+	// 组合过滤，摘要仍为整个任务的摘要
+	_, response = doJSON(t, server, http.MethodGet, "/api/jobs/big?severity=HIGH&file=src/", "")
+	assert.Equal(t, []string{"src/a.py:5", "src/b.py:2", "src/b.py:9"}, matchLocations(response))
+	assert.Equal(t, float64(3), response["total"])
+	assert.Equal(t, float64(defaultPageSize), response["pageSize"])
+	if summary, ok := response["summary"].(map[string]interface{}); assert.True(t, ok) {
+		assert.Equal(t, float64(4), summary["high"])
+	}
+
+	_, response = doJSON(t, server, http.MethodGet, "/api/jobs/big?severity=high&file=a&page=2&pageSize=1", "")
+	assert.Equal(t, []string{"test/a_test.py:3"}, matchLocations(response))
+	assert.Equal(t, float64(2), response["total"])
+
+	_, response = doJSON(t, server, http.MethodGet, "/api/jobs/big?severity=medium", "")
+	assert.Equal(t, []string{}, matchLocations(response))
+	assert.Equal(t, float64(0), response["totalPages"])
+
+	// 无效的参数
+	for _, query := range []string{"severity=critical", "page=0", "page=x", "pageSize=0", "pageSize=1001"} {
+		code, _ = doJSON(t, server, http.MethodGet, "/api/jobs/big?"+query, "")
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultRateLimitPerHour is the default number of requests a client may make per hour
+const DefaultRateLimitPerHour = 1000
+
+// rateLimiter is a sliding-window rate limiter keyed by client. It keeps the time of
+// every request within the window, so a client may make at most limit requests in
+// any window-long interval.
+type rateLimiter struct {
+	limit     int
+	window    time.Duration
+	now       func() time.Time
+	mutex     sync.Mutex
+	requests  map[string][]time.Time
+	lastSweep time.Time
+}
+
+// newRateLimiter creates a rate limiter allowing limit requests per window
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:    limit,
+		window:   window,
+		now:      time.Now,
+		requests: make(map[string][]time.Time),
+	}
+}
+
+// allow records a request of a client if it is within the limit. Otherwise it returns
+// false and how long the client has to wait until its next request is allowed.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	cutoff := now.Add(-l.window)
+
+	// Forget clients that made no requests within the window
+	if now.Sub(l.lastSweep) > l.window {
+		for key, times := range l.requests {
+			if !times[len(times)-1].After(cutoff) {
+				delete(l.requests, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	times := l.requests[client]
+	expired := 0
+	for expired < len(times) && !times[expired].After(cutoff) {
+		expired++
+	}
+	times = times[expired:]
+
+	if len(times) >= l.limit {
+		l.requests[client] = times
+		return false, times[0].Sub(cutoff)
+	}
+
+	l.requests[client] = append(times, now)
+	return true, 0
+}
+
+// SetRateLimit sets the number of requests per hour each client may make to the /api
+// routes. Clients are identified by their bearer token when authentication is required
+// and the token is valid, and by their IP address otherwise, so that requests with
+// invalid tokens share the budget of their IP address. A limit of 0 or less disables
+// rate limiting.
+func (s *Server) SetRateLimit(perHour int) {
+	if perHour <= 0 {
+		s.limiter = nil
+		return
+	}
+	s.limiter = newRateLimiter(perHour, time.Hour)
+}
+
+// rateLimitMiddleware rejects requests of clients that exceeded their hourly budget
+func (s *Server) rateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limiter := s.limiter
+		if limiter == nil || c.Request.URL.Path == "/health" {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if s.requireAuth && s.validToken(c.GetHeader("Authorization")) {
+			token, _ := bearerToken(c.GetHeader("Authorization"))
+			client = "token:" + token
+		}
+
+		if ok, retryAfter := limiter.allow(client); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": "Rate limit exceeded",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
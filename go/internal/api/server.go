@@ -1,35 +1,65 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/re-movery/re-movery/internal/analyzers"
 	"github.com/re-movery/re-movery/internal/core"
 	"github.com/re-movery/re-movery/internal/detectors"
+	"github.com/re-movery/re-movery/internal/notify"
+	"github.com/re-movery/re-movery/internal/utils"
 )
 
 // Server is the API server
 type Server struct {
-	scanner *core.Scanner
-	router  *gin.Engine
+	scanner  *core.Scanner
+	router   *gin.Engine
+	notifier notify.Notifier
+
+	// Asynchronous directory scan jobs
+	pool      *utils.WorkerPool
+	jobs      map[string]*scanJob
+	jobsMutex sync.RWMutex
+
+	// Bearer token authentication of the /api routes
+	requireAuth bool
+	tokens      []string
+
+	// Per-client rate limiting of the /api routes
+	limiter *rateLimiter
+
+	// Configuration reported by GET /api/config
+	config *core.Config
 }
 
 // NewServer creates a new API server
 func NewServer() *Server {
-	server := &Server{
-		scanner: core.NewScanner(),
-		router:  gin.Default(),
-	}
+	scanner := core.NewScanner()
 
 	// Register detectors
-	server.scanner.RegisterDetector(detectors.NewPythonDetector())
-	server.scanner.RegisterDetector(detectors.NewJavaScriptDetector())
+	scanner.RegisterDetector(detectors.NewPythonDetector())
+	scanner.RegisterDetector(detectors.NewJavaScriptDetector())
+	scanner.RegisterDetector(detectors.NewGoDetector())
+	scanner.RegisterDetector(detectors.NewHTMLDetector())
+	scanner.RegisterDetector(detectors.NewDockerfileDetector())
+	scanner.RegisterDetector(detectors.NewCDetector())
+	scanner.RegisterDetector(detectors.NewKotlinDetector())
+	scanner.RegisterDetector(detectors.NewSQLDetector())
+	scanner.RegisterDetector(detectors.NewTLSDetector())
+	scanner.RegisterDetector(detectors.NewSecretsDetector())
+
+	server := newServer(scanner, gin.Default())
+	server.SetConfig(core.NewConfig())
 
 	// Setup routes
 	server.setupRoutes()
@@ -37,32 +67,98 @@ func NewServer() *Server {
 	return server
 }
 
+// Mount creates an API server that serves the /api routes on an existing engine and
+// scans with an existing scanner, e.g. to run next to the web interface in one process.
+// The engine's owner serves /health and all routes outside /api.
+func Mount(router *gin.Engine, scanner *core.Scanner) *Server {
+	server := newServer(scanner, router)
+	server.setupAPIRoutes()
+	return server
+}
+
+// newServer creates an API server without routes and starts its job workers
+func newServer(scanner *core.Scanner, router *gin.Engine) *Server {
+	server := &Server{
+		scanner: scanner,
+		router:  router,
+		pool:    utils.NewWorkerPool(jobWorkers, jobQueueSize),
+		jobs:    make(map[string]*scanJob),
+		limiter: newRateLimiter(DefaultRateLimitPerHour, time.Hour),
+		config:  core.NewConfig(),
+	}
+
+	// Start the job workers; job errors are reported through the job status
+	server.pool.Start()
+	go func() {
+		for range server.pool.Results() {
+		}
+	}()
+
+	return server
+}
+
 // setupRoutes sets up the routes for the API server
 func (s *Server) setupRoutes() {
-	// API routes
-	api := s.router.Group("/api")
+	s.setupAPIRoutes()
+
+	// Health check
+	s.router.GET("/health", s.healthHandler)
+}
+
+// setupAPIRoutes sets up the /api routes, which are rate limited and require
+// authentication. The rate limit comes first, so that requests with invalid tokens
+// count against it and tokens cannot be guessed at an unlimited rate.
+func (s *Server) setupAPIRoutes() {
+	api := s.router.Group("/api", s.rateLimitMiddleware(), s.authMiddleware())
 	{
 		api.POST("/scan/code", s.scanCodeHandler)
 		api.POST("/scan/file", s.scanFileHandler)
 		api.POST("/scan/directory", s.scanDirectoryHandler)
 		api.GET("/languages", s.languagesHandler)
+		api.GET("/rules", s.rulesHandler)
+		api.GET("/config", s.configHandler)
+		api.POST("/jobs", s.createJobHandler)
+		api.GET("/jobs/:id", s.getJobHandler)
+		api.DELETE("/jobs/:id", s.cancelJobHandler)
 	}
+}
 
-	// Health check
-	s.router.GET("/health", s.healthHandler)
+// SetNotifier sets the notifier that receives the findings of every scan
+func (s *Server) SetNotifier(notifier notify.Notifier) {
+	s.notifier = notifier
+}
+
+// notifyFindings sends the findings of a scan to the notifier, if one is set
+func (s *Server) notifyFindings(results map[string][]core.Match) {
+	if s.notifier == nil {
+		return
+	}
+
+	var matches []core.Match
+	for _, fileMatches := range results {
+		matches = append(matches, fileMatches...)
+	}
+
+	if err := s.notifier.Notify(matches); err != nil {
+		fmt.Fprintf(os.Stderr, "Error sending notification: %v\n", err)
+	}
 }
 
 // Run runs the API server
-func (s *Server) Run(host string, port int) error {
+func (s *Server) Run(host string, port int, debug bool) error {
+	if !debug {
+		gin.SetMode(gin.ReleaseMode)
+	}
 	return s.router.Run(fmt.Sprintf("%s:%d", host, port))
 }
 
-// scanCodeHandler handles code scanning
+// scanCodeHandler handles code scanning. The language is inferred from the file name
+// if not given; code whose language no detector supports is scanned with all detectors.
 func (s *Server) scanCodeHandler(c *gin.Context) {
 	// Parse request
 	var request struct {
 		Code     string `json:"code" binding:"required"`
-		Language string `json:"language" binding:"required"`
+		Language string `json:"language"`
 		FileName string `json:"fileName"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -72,52 +168,69 @@ func (s *Server) scanCodeHandler(c *gin.Context) {
 		return
 	}
 
+	// Infer the language from the file name if not provided
+	inferred := false
+	if request.Language == "" {
+		if request.FileName == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Cannot determine the language: provide language or fileName",
+			})
+			return
+		}
+		request.Language = analyzers.GetFileLanguage(request.FileName)
+		inferred = true
+	}
+
 	// Set default file name if not provided
 	if request.FileName == "" {
 		request.FileName = "code." + request.Language
 	}
 
-	// Check if language is supported
-	supported := false
-	for _, lang := range s.scanner.SupportedLanguages() {
-		if lang == request.Language {
-			supported = true
-			break
-		}
-	}
-	if !supported {
+	// Check if language is supported, by name or by the file name's extension
+	supported := s.supportsLanguage(request.Language) || s.supportsLanguage(strings.TrimPrefix(filepath.Ext(request.FileName), "."))
+	if !supported && !inferred {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Unsupported language: " + request.Language,
 		})
 		return
 	}
 
-	// Create temporary file
-	tempDir, err := ioutil.TempDir("", "re-movery-")
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create temporary directory: " + err.Error(),
-		})
-		return
-	}
-	defer os.RemoveAll(tempDir)
-
-	tempFile := filepath.Join(tempDir, request.FileName)
-	if err := ioutil.WriteFile(tempFile, []byte(request.Code), 0644); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to write temporary file: " + err.Error(),
-		})
-		return
+	var results []core.Match
+	if supported {
+		// Scan the code directly, with the detectors that support the file name. Names
+		// without a supported extension, such as unsaved editor buffers, are given the
+		// language as extension to select the detectors.
+		scanName := request.FileName
+		if !s.supportsLanguage(strings.TrimPrefix(filepath.Ext(request.FileName), ".")) {
+			scanName = request.FileName + "." + request.Language
+		}
+		var err error
+		results, err = s.scanner.ScanReader(strings.NewReader(request.Code), scanName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to scan code: " + err.Error(),
+			})
+			return
+		}
+		for i := range results {
+			results[i].FilePath = request.FileName
+		}
+	} else {
+		// No detector matches the file name, so try all of them
+		var err error
+		results, err = s.scanner.ScanCode(request.Code, request.FileName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to scan code: " + err.Error(),
+			})
+			return
+		}
 	}
 
-	// Scan file
-	results, err := s.scanner.ScanFile(tempFile)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to scan code: " + err.Error(),
-		})
-		return
-	}
+	// Notify new findings
+	s.notifyFindings(map[string][]core.Match{
+		request.FileName: results,
+	})
 
 	// Generate summary
 	summary := core.GenerateSummary(map[string][]core.Match{
@@ -144,15 +257,24 @@ func (s *Server) scanFileHandler(c *gin.Context) {
 		return
 	}
 
-	// Save file to temporary location
-	tempFile := filepath.Join(os.TempDir(), file.Filename)
+	// Save file to a temporary directory of its own, so concurrent uploads
+	// of the same file name do not overwrite each other
+	tempDir, err := os.MkdirTemp("", "re-movery-upload-")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create temporary directory",
+		})
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	tempFile := filepath.Join(tempDir, utils.SafeFileName(file.Filename))
 	if err := c.SaveUploadedFile(file, tempFile); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to save file",
 		})
 		return
 	}
-	defer os.Remove(tempFile)
 
 	// Scan file
 	results, err := s.scanner.ScanFile(tempFile)
@@ -163,6 +285,11 @@ func (s *Server) scanFileHandler(c *gin.Context) {
 		return
 	}
 
+	// Notify new findings
+	s.notifyFindings(map[string][]core.Match{
+		file.Filename: results,
+	})
+
 	// Generate summary
 	summary := core.GenerateSummary(map[string][]core.Match{
 		file.Filename: results,
@@ -201,29 +328,118 @@ func (s *Server) scanDirectoryHandler(c *gin.Context) {
 		return
 	}
 
-	// Set scanner options
-	s.scanner.SetParallel(request.Parallel)
-	s.scanner.SetIncremental(request.Incremental)
+	// Set scanner options on a scanner of the request's own, as other requests scan at
+	// the same time
+	scanner := s.scanner.Clone()
+	scanner.SetParallel(request.Parallel)
+	scanner.SetIncremental(request.Incremental)
 
-	// Scan directory
-	results, err := s.scanner.ScanDirectory(request.Directory, request.ExcludePatterns)
+	// Stream the results file by file if the client accepts NDJSON
+	if strings.Contains(c.GetHeader("Accept"), ndjsonContentType) {
+		s.streamDirectoryScan(c, scanner, request.Directory, request.ExcludePatterns)
+		return
+	}
+
+	// Scan directory, stopping early if the client disconnects
+	results, scanErrors, err := scanner.ScanDirectoryErrors(c.Request.Context(), request.Directory, request.ExcludePatterns)
 	if err != nil {
+		if c.Request.Context().Err() != nil {
+			// Nobody is waiting for the response
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": fmt.Sprintf("Failed to scan directory: %v", err),
 		})
 		return
 	}
 
+	// Notify new findings
+	s.notifyFindings(results)
+
 	// Generate summary
 	summary := core.GenerateSummary(results)
 
-	// Return results
+	// Return results, with the files that could not be scanned
 	c.JSON(http.StatusOK, gin.H{
 		"results": results,
+		"errors":  scanErrors,
 		"summary": summary,
 	})
 }
 
+// ndjsonContentType is the content type of streamed directory scan results
+const ndjsonContentType = "application/x-ndjson"
+
+// streamDirectoryScan scans a directory and writes the results as newline-delimited
+// JSON: a {"type": "file", "filePath": ..., "matches": [...]} line for each file with
+// findings as soon as it is scanned, a {"type": "error", "path": ..., "error": ...} line
+// for each file that could not be scanned, then a {"type": "summary", "summary": {...}}
+// line, or a {"type": "error", "error": ...} line if the scan fails. Only the findings
+// of the file being written are kept in memory, unless a notifier needs all of them.
+func (s *Server) streamDirectoryScan(c *gin.Context, scanner *core.Scanner, directory string, excludePatterns []string) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	lines := make(chan gin.H)
+	send := func(line gin.H) {
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(lines)
+
+		notified := make(map[string][]core.Match)
+		summary, scanErrors, err := scanner.ScanDirectoryStream(ctx, directory, excludePatterns, func(filePath string, matches []core.Match) {
+			if s.notifier != nil {
+				notified[filePath] = matches
+			}
+			send(gin.H{"type": "file", "filePath": filePath, "matches": matches})
+		})
+		if err != nil {
+			send(gin.H{"type": "error", "error": fmt.Sprintf("Failed to scan directory: %v", err)})
+			return
+		}
+
+		for _, scanErr := range scanErrors {
+			send(gin.H{"type": "error", "path": scanErr.Path, "error": scanErr.Err.Error()})
+		}
+		s.notifyFindings(notified)
+		send(gin.H{"type": "summary", "summary": summary})
+	}()
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	c.Stream(func(w io.Writer) bool {
+		line, ok := <-lines
+		if !ok {
+			return false
+		}
+		return json.NewEncoder(w).Encode(line) == nil
+	})
+
+	// Stop the scan if the client went away and wait for it to finish
+	cancel()
+	for range lines {
+	}
+}
+
+// supportsLanguage reports whether a registered detector supports a language, given by
+// name or extension
+func (s *Server) supportsLanguage(language string) bool {
+	if language == "" {
+		return false
+	}
+	for _, lang := range s.scanner.SupportedLanguages() {
+		if lang == language {
+			return true
+		}
+	}
+	return false
+}
+
 // languagesHandler handles the supported languages request
 func (s *Server) languagesHandler(c *gin.Context) {
 	languages := s.scanner.SupportedLanguages()
@@ -232,6 +448,14 @@ func (s *Server) languagesHandler(c *gin.Context) {
 	})
 }
 
+// rulesHandler handles the rule catalog request, optionally filtered by ?language=
+func (s *Server) rulesHandler(c *gin.Context) {
+	language := strings.ToLower(c.Query("language"))
+	c.JSON(http.StatusOK, gin.H{
+		"rules": s.scanner.Signatures(language),
+	})
+}
+
 // healthHandler handles the health check request
 func (s *Server) healthHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
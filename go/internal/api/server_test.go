@@ -0,0 +1,501 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/re-movery/re-movery/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// 上传文件并解析JSON响应
+func uploadFile(t *testing.T, server *Server, filename string, content string) (int, map[string]interface{}) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/scan/file", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w.Code, response
+}
+
+// 读取响应摘要中的高危问题数
+func highCount(response map[string]interface{}) interface{} {
+	summary, _ := response["summary"].(map[string]interface{})
+	return summary["high"]
+}
+
+// 测试同名文件并发上传互不覆盖
+func TestScanFileConcurrentUploads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			code, response := uploadFile(t, server, "app.py", "result = eval(user_input)\n")
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, float64(1), highCount(response))
+		}()
+		go func() {
+			defer wg.Done()
+			code, response := uploadFile(t, server, "app.py", "result = int(user_input)\n")
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, float64(0), highCount(response))
+		}()
+	}
+	wg.Wait()
+}
+
+// 发送代码扫描请求并解析JSON响应
+func scanCode(t *testing.T, server *Server, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodPost, "/api/scan/code", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w.Code, response
+}
+
+// 测试VS Code扩展只发送代码和文件名时根据文件名推断语言
+func TestScanCodeWithoutLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	code, response := scanCode(t, server, `{"code": "result = eval(user_input)\n", "filename": "app.py"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), highCount(response))
+	assert.Contains(t, response["results"], "app.py")
+
+	// 检测器不支持的文件名使用所有检测器扫描，Python和JavaScript检测器都会报告eval
+	code, response = scanCode(t, server, `{"code": "result = eval(user_input)\n", "filename": "snippet.txt"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), highCount(response))
+	assert.Contains(t, response["results"], "snippet.txt")
+
+	// 显式指定的语言仍然优先
+	code, response = scanCode(t, server, `{"code": "result = eval(user_input)\n", "language": "py"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, response["results"], "code.py")
+
+	// 文件名没有扩展名时按指定的语言选择检测器，结果仍使用请求中的文件名
+	code, response = scanCode(t, server, `{"language": "python", "fileName": "Untitled-1", "code": "eval(x)"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), highCount(response))
+	if matches, ok := response["results"].(map[string]interface{})["Untitled-1"].([]interface{}); assert.True(t, ok) && assert.Len(t, matches, 1) {
+		assert.Equal(t, "Untitled-1", matches[0].(map[string]interface{})["filePath"])
+	}
+
+	// 无法确定语言或语言不受支持
+	code, response = scanCode(t, server, `{"code": "result = eval(user_input)\n"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, response["error"], "Cannot determine the language")
+	code, _ = scanCode(t, server, `{"code": "x", "language": "cobol"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+// 测试代码不经临时文件直接扫描，匹配结果使用请求中的文件名
+func TestScanCodeFilePath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	code, response := scanCode(t, server, `{"code": "result = eval(user_input)\n", "fileName": "src/app.py"}`)
+	assert.Equal(t, http.StatusOK, code)
+	results := response["results"].(map[string]interface{})
+	matches := results["src/app.py"].([]interface{})
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "src/app.py", matches[0].(map[string]interface{})["filePath"])
+	}
+}
+
+// recordingNotifier 记录收到的问题
+type recordingNotifier struct {
+	matches []core.Match
+}
+
+func (n *recordingNotifier) Notify(matches []core.Match) error {
+	n.matches = append(n.matches, matches...)
+	return nil
+}
+
+// 测试API响应、任务结果和通知中的秘密被脱敏
+func TestScanRedactsSecrets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+	notifier := &recordingNotifier{}
+	server.SetNotifier(notifier)
+	secret := "S3cr3tPassw0rd"
+
+	body, _ := json.Marshal(map[string]string{"code": "password = \"" + secret + "\"\n", "fileName": "settings.py"})
+	code, response := scanCode(t, server, string(body))
+	assert.Equal(t, http.StatusOK, code)
+	data, _ := json.Marshal(response)
+	assert.Contains(t, string(data), core.RedactedSecret)
+	assert.NotContains(t, string(data), secret)
+
+	tmpdir, err := ioutil.TempDir("", "api-redact-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "settings.py"), []byte("password = \""+secret+"\"\n"), 0644))
+
+	body, _ = json.Marshal(map[string]string{"directory": tmpdir})
+	code, response = doJSON(t, server, http.MethodPost, "/api/scan/directory", string(body))
+	assert.Equal(t, http.StatusOK, code)
+	data, _ = json.Marshal(response)
+	assert.Contains(t, string(data), core.RedactedSecret)
+	assert.NotContains(t, string(data), secret)
+
+	if assert.NotEmpty(t, notifier.matches) {
+		for _, match := range notifier.matches {
+			assert.NotContains(t, match.MatchedCode, secret)
+		}
+	}
+}
+
+// 测试目录扫描的响应列出无法扫描的文件
+func TestScanDirectoryErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	tmpdir, err := ioutil.TempDir("", "api-errors-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "vuln.py"), []byte("result = eval(user_input)\n"), 0644))
+	assert.NoError(t, os.Symlink(filepath.Join(tmpdir, "missing"), filepath.Join(tmpdir, "broken.py")))
+
+	body, err := json.Marshal(map[string]interface{}{"directory": tmpdir})
+	assert.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/scan/directory", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Results map[string][]core.Match `json:"results"`
+		Errors  []struct {
+			Path  string `json:"path"`
+			Error string `json:"error"`
+		} `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Results, filepath.Join(tmpdir, "vuln.py"))
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, filepath.Join(tmpdir, "broken.py"), response.Errors[0].Path)
+		assert.Contains(t, response.Errors[0].Error, "file does not exist")
+	}
+}
+
+// 测试规则目录接口及按语言过滤
+func TestRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	rules := func(url string) []map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Rules []map[string]interface{} `json:"rules"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Rules
+	}
+	ids := func(rules []map[string]interface{}) []interface{} {
+		ids := []interface{}{}
+		for _, rule := range rules {
+			ids = append(ids, rule["id"])
+		}
+		return ids
+	}
+
+	all := rules("/api/rules")
+	assert.Contains(t, ids(all), "PY001")
+	assert.Contains(t, ids(all), "JS001")
+	assert.Contains(t, ids(all), "PY011")
+	for _, rule := range all {
+		assert.NotEmpty(t, rule["name"])
+		assert.NotEmpty(t, rule["severity"])
+	}
+
+	python := rules("/api/rules?language=python")
+	assert.Contains(t, ids(python), "PY001")
+	assert.NotContains(t, ids(python), "JS001")
+	assert.Less(t, len(python), len(all))
+
+	assert.Empty(t, rules("/api/rules?language=cobol"))
+}
+
+// 测试上传文件名中的路径被忽略
+func TestScanFileTraversalName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	code, response := uploadFile(t, server, "../../vuln.py", "result = eval(user_input)\n")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), highCount(response))
+
+	code, _ = uploadFile(t, server, `..\..\vuln.py`, "result = eval(user_input)\n")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+// 测试Bearer令牌认证
+func TestAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+	server.SetAuth(true, []string{"first-token", " second-token "})
+
+	request := func(path string, header string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 缺少或错误的令牌
+	assert.Equal(t, http.StatusUnauthorized, request("/api/languages", ""))
+	assert.Equal(t, http.StatusUnauthorized, request("/api/languages", "Bearer wrong-token"))
+	assert.Equal(t, http.StatusUnauthorized, request("/api/languages", "first-token"))
+	assert.Equal(t, http.StatusUnauthorized, request("/api/languages", "Basic first-token"))
+
+	// 任一配置的令牌均可访问
+	assert.Equal(t, http.StatusOK, request("/api/languages", "Bearer first-token"))
+	assert.Equal(t, http.StatusOK, request("/api/languages", "Bearer second-token"))
+
+	// 健康检查无需认证
+	assert.Equal(t, http.StatusOK, request("/health", ""))
+
+	// 关闭认证后无需令牌
+	server.SetAuth(false, nil)
+	assert.Equal(t, http.StatusOK, request("/api/languages", ""))
+}
+
+// 测试/api/config返回生效的配置和已注册的检测器，令牌被隐藏，启用认证时需要令牌
+func TestConfigEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+
+	settings := core.NewConfig()
+	settings.Scanner.ConfidenceThreshold = 0.9
+	settings.Server.Port = 9090
+	assert.NoError(t, server.SetConfig(settings))
+	server.SetAuth(true, []string{"secret-token"})
+	server.SetRateLimit(50)
+
+	request := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request("").Code)
+
+	w := request("Bearer secret-token")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret-token")
+
+	var effective EffectiveConfig
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &effective))
+	assert.Equal(t, 0.9, effective.Config.Scanner.ConfidenceThreshold)
+	assert.Equal(t, 9090, effective.Config.Server.Port)
+	assert.True(t, effective.Security.RequireAuth)
+	assert.Equal(t, []string{redactedToken}, effective.Security.APITokens)
+	assert.Equal(t, 50, effective.Security.RateLimitPerHour)
+
+	names := []string{}
+	for _, detector := range effective.Detectors {
+		names = append(names, detector.Name)
+		if detector.Name == "python" {
+			assert.Contains(t, detector.Languages, "py")
+		}
+	}
+	assert.Contains(t, names, "python")
+	assert.Contains(t, names, "sql")
+
+	// 配置中的扫描器设置已应用到扫描器
+	assert.False(t, server.scanner.IsParallel())
+	settings.Scanner.Parallel = true
+	assert.NoError(t, server.SetConfig(settings))
+	assert.True(t, server.scanner.IsParallel())
+
+	// 无效的配置不被应用
+	settings = core.NewConfig()
+	settings.Scanner.MinSeverity = "urgent"
+	assert.Error(t, server.SetConfig(settings))
+}
+
+// 测试从环境变量读取令牌
+func TestTokensFromEnv(t *testing.T) {
+	os.Setenv(TokensEnvVar, "a,b")
+	defer os.Unsetenv(TokensEnvVar)
+	assert.Equal(t, []string{"a", "b"}, TokensFromEnv())
+
+	os.Setenv(TokensEnvVar, " a , ,b")
+	assert.Equal(t, []string{"a", "b"}, TokensFromEnv())
+
+	os.Setenv(TokensEnvVar, "")
+	assert.Nil(t, TokensFromEnv())
+
+	os.Setenv(TokensEnvVar, " , ")
+	assert.Nil(t, TokensFromEnv())
+}
+
+// 测试超出每小时请求预算后返回429
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+	const limit = 5
+	server.SetRateLimit(limit)
+
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	server.limiter.now = func() time.Time { return current }
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/languages", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < limit; i++ {
+		assert.Equal(t, http.StatusOK, request("192.0.2.1:1234").Code)
+		current = current.Add(time.Minute)
+	}
+	w := request("192.0.2.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "3300", w.Header().Get("Retry-After"))
+
+	// 其他客户端和健康检查不受影响
+	assert.Equal(t, http.StatusOK, request("192.0.2.2:1234").Code)
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	hw := httptest.NewRecorder()
+	server.router.ServeHTTP(hw, req)
+	assert.Equal(t, http.StatusOK, hw.Code)
+
+	// 最早的请求滑出窗口后恢复
+	current = current.Add(55 * time.Minute)
+	assert.Equal(t, http.StatusOK, request("192.0.2.1:1234").Code)
+	assert.Equal(t, http.StatusTooManyRequests, request("192.0.2.1:1234").Code)
+}
+
+// 测试无效令牌的请求按IP地址计入速率限制，有效令牌按令牌计数
+func TestRateLimitInvalidTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+	server.SetAuth(true, []string{"secret"})
+	const limit = 3
+	server.SetRateLimit(limit)
+
+	request := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/languages", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 每次猜测不同的令牌也会被限制
+	for i := 0; i < limit; i++ {
+		assert.Equal(t, http.StatusUnauthorized, request(fmt.Sprintf("guess%d", i)))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, request("guess"))
+	assert.Equal(t, http.StatusTooManyRequests, request("another-guess"))
+
+	// 来自同一IP地址的有效令牌有自己的预算
+	assert.Equal(t, http.StatusOK, request("secret"))
+}
+
+// 测试请求NDJSON时逐个文件流式返回目录扫描结果，最后一行为摘要
+func TestScanDirectoryStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := NewServer()
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	tmpdir, err := ioutil.TempDir("", "api-stream-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "vuln.py"), []byte("result = eval(user_input)\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "vuln.js"), []byte("var result = eval(userInput);\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "clean.py"), []byte("print('Hello')\n"), 0644))
+	// 指向不存在文件的符号链接无法扫描
+	assert.NoError(t, os.Symlink(filepath.Join(tmpdir, "missing"), filepath.Join(tmpdir, "broken.py")))
+
+	body, err := json.Marshal(map[string]interface{}{"directory": tmpdir})
+	assert.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, httpServer.URL+"/api/scan/directory", bytes.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/x-ndjson")
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	lines := []map[string]interface{}{}
+	reader := bufio.NewScanner(resp.Body)
+	for reader.Scan() {
+		var line map[string]interface{}
+		assert.NoError(t, json.Unmarshal(reader.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	assert.NoError(t, reader.Err())
+
+	// 每个有问题的文件一行，没有问题的文件不输出，无法扫描的文件各一行错误
+	if assert.Len(t, lines, 4) {
+		files := []interface{}{lines[0]["filePath"], lines[1]["filePath"]}
+		assert.ElementsMatch(t, []interface{}{filepath.Join(tmpdir, "vuln.py"), filepath.Join(tmpdir, "vuln.js")}, files)
+		assert.Equal(t, "file", lines[0]["type"])
+		assert.NotEmpty(t, lines[0]["matches"])
+		assert.Equal(t, "error", lines[2]["type"])
+		assert.Equal(t, filepath.Join(tmpdir, "broken.py"), lines[2]["path"])
+		assert.NotEmpty(t, lines[2]["error"])
+		assert.Equal(t, "summary", lines[3]["type"])
+		assert.Equal(t, float64(2), highCount(lines[3]))
+	}
+
+	// 默认仍返回完整的JSON响应
+	code, response := doJSON(t, server, http.MethodPost, "/api/scan/directory", string(body))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, response["results"], 2)
+	assert.Len(t, response["errors"], 1)
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// baselineVersion is the version of the baseline file format
+const baselineVersion = 1
+
+// baseline is a file of accepted findings, which scans with --baseline do not report
+type baseline struct {
+	Version  int             `json:"version"`
+	Findings []baselineEntry `json:"findings"`
+}
+
+// baselineEntry is an accepted finding. The justification and metadata are written by
+// hand and kept when the baseline is updated.
+type baselineEntry struct {
+	Fingerprint   string            `json:"fingerprint"`
+	Rule          string            `json:"rule"`
+	File          string            `json:"file"`
+	Line          int               `json:"line"`
+	Justification string            `json:"justification,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// loadBaseline reads a baseline file. A missing file is an empty baseline.
+func loadBaseline(path string) (*baseline, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &baseline{Version: baselineVersion}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var b baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid baseline file %s: %v", path, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline file version %d in %s", b.Version, path)
+	}
+	return &b, nil
+}
+
+// save writes the baseline to a file
+func (b *baseline) save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// suppress removes the findings in the baseline from the results and returns how many
+// were removed. Files without remaining findings stay in the results, so they are
+// still counted as scanned.
+func (b *baseline) suppress(results map[string][]core.Match, baseDir string) int {
+	known := make(map[string]bool, len(b.Findings))
+	for _, entry := range b.Findings {
+		known[entry.Fingerprint] = true
+	}
+
+	suppressed := 0
+	for filePath, matches := range results {
+		kept := []core.Match{}
+		for _, match := range matches {
+			if fingerprint, _ := baselineFingerprint(match, baseDir); known[fingerprint] {
+				suppressed++
+				continue
+			}
+			kept = append(kept, match)
+		}
+		results[filePath] = kept
+	}
+	return suppressed
+}
+
+// update replaces the findings in the baseline with the findings in the results,
+// keeping the justification and metadata of findings that were already in it, and
+// returns the number of findings added and removed
+func (b *baseline) update(results map[string][]core.Match, baseDir string) (int, int) {
+	previous := make(map[string]baselineEntry, len(b.Findings))
+	for _, entry := range b.Findings {
+		previous[entry.Fingerprint] = entry
+	}
+
+	// Identical code matched twice in a file has a single fingerprint
+	current := make(map[string]baselineEntry)
+	for _, matches := range results {
+		for _, match := range matches {
+			fingerprint, file := baselineFingerprint(match, baseDir)
+			if _, ok := current[fingerprint]; ok {
+				continue
+			}
+			entry := previous[fingerprint]
+			entry.Fingerprint = fingerprint
+			entry.Rule = match.Signature.ID
+			entry.File = file
+			entry.Line = match.LineNumber
+			current[fingerprint] = entry
+		}
+	}
+
+	added, removed := 0, 0
+	for fingerprint := range current {
+		if _, ok := previous[fingerprint]; !ok {
+			added++
+		}
+	}
+	for fingerprint := range previous {
+		if _, ok := current[fingerprint]; !ok {
+			removed++
+		}
+	}
+
+	b.Version = baselineVersion
+	b.Findings = make([]baselineEntry, 0, len(current))
+	for _, entry := range current {
+		b.Findings = append(b.Findings, entry)
+	}
+
+	// Sort so that updates produce small diffs
+	sort.Slice(b.Findings, func(i, j int) bool {
+		a, c := b.Findings[i], b.Findings[j]
+		if a.File != c.File {
+			return a.File < c.File
+		}
+		if a.Line != c.Line {
+			return a.Line < c.Line
+		}
+		if a.Rule != c.Rule {
+			return a.Rule < c.Rule
+		}
+		return a.Fingerprint < c.Fingerprint
+	})
+
+	return added, removed
+}
+
+// baselineFingerprint returns the fingerprint of a match and its path relative to
+// baseDir. Paths are made relative so that the baseline stays valid when the scanned
+// directory is given by another path.
+func baselineFingerprint(match core.Match, baseDir string) (string, string) {
+	filePath := match.FilePath
+	if baseDir != "" {
+		if relPath, err := filepath.Rel(baseDir, filePath); err == nil {
+			filePath = relPath
+		}
+	}
+	match.FilePath = filepath.ToSlash(filePath)
+	return core.Fingerprint(match), match.FilePath
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// compareRefs scans the base and head refs of the git repository at repoDir, each checked
+// out in a temporary worktree, and returns the findings present in head but not in base.
+// Results are keyed by paths relative to the repository root. The returned coverage is
+// the coverage of the head scan.
+func compareRefs(scanner *core.Scanner, repoDir string, refRange string, excludePatterns []string) (map[string][]core.Match, float64, error) {
+	base, head, err := parseRefRange(refRange)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tmpdir, err := ioutil.TempDir("", "re-movery-compare")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer os.RemoveAll(tmpdir)
+
+	baseResults, err := scanRef(scanner, repoDir, base, filepath.Join(tmpdir, "base"), excludePatterns)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	headResults, err := scanRef(scanner, repoDir, head, filepath.Join(tmpdir, "head"), excludePatterns)
+	if err != nil {
+		return nil, 0, err
+	}
+	coverage := scanner.LastScanStats().Coverage()
+
+	// Findings are matched by fingerprint so that moved lines are not reported as new
+	known := make(map[string]bool)
+	for _, matches := range baseResults {
+		for _, match := range matches {
+			known[core.Fingerprint(match)] = true
+		}
+	}
+
+	results := make(map[string][]core.Match)
+	for filePath, matches := range headResults {
+		for _, match := range matches {
+			if !known[core.Fingerprint(match)] {
+				results[filePath] = append(results[filePath], match)
+			}
+		}
+	}
+
+	return results, coverage, nil
+}
+
+// parseRefRange splits a "base..head" range into its two refs. Refs starting with "-"
+// are rejected so that they cannot be taken as git options.
+func parseRefRange(refRange string) (string, string, error) {
+	parts := strings.SplitN(refRange, "..", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.HasPrefix(parts[1], ".") {
+		return "", "", fmt.Errorf("invalid --compare range: %s (expected base..head)", refRange)
+	}
+	if strings.HasPrefix(parts[0], "-") || strings.HasPrefix(parts[1], "-") {
+		return "", "", fmt.Errorf("invalid --compare range: %s (refs must not start with '-')", refRange)
+	}
+	return parts[0], parts[1], nil
+}
+
+// scanRef checks out ref in a temporary worktree at dir, scans it and removes the worktree.
+// Paths in the results are relative to the worktree root.
+func scanRef(scanner *core.Scanner, repoDir string, ref string, dir string, excludePatterns []string) (map[string][]core.Match, error) {
+	if output, err := exec.Command("git", "-C", repoDir, "worktree", "add", "--detach", "--end-of-options", dir, ref).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("checking out %s: %v: %s", ref, err, strings.TrimSpace(string(output)))
+	}
+	defer exec.Command("git", "-C", repoDir, "worktree", "remove", "--force", dir).Run()
+
+	results, err := scanner.ScanDirectory(dir, excludePatterns)
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %v", ref, err)
+	}
+
+	return relativeResults(results, dir), nil
+}
+
+// relativeResults returns copies of results scanned under dir with paths relative to
+// dir. The matches are copied because the scanner's cache shares them.
+func relativeResults(results map[string][]core.Match, dir string) map[string][]core.Match {
+	relative := make(map[string][]core.Match, len(results))
+	for filePath, matches := range results {
+		relPath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			relPath = filePath
+		}
+		copied := append([]core.Match{}, matches...)
+		for i := range copied {
+			copied[i].FilePath = relPath
+		}
+		relative[relPath] = copied
+	}
+	return relative
+}
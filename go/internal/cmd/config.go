@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/api"
+	"github.com/re-movery/re-movery/internal/config"
+	"github.com/re-movery/re-movery/internal/core"
+	"github.com/re-movery/re-movery/internal/detectors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configShowFormat string
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+	Long: `Inspect the configuration Re-movery runs with.
+Examples:
+  re-movery config show
+  re-movery config show --config config.json
+  re-movery config show --format json`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Long: `Print the effective configuration: the defaults, overridden by the config file
+given with --config and by REMOVERY_* environment variables, the authentication and
+rate limit settings of JSON config files with the API tokens redacted, and the
+registered detectors with their languages. The API server reports the same at
+GET /api/config.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runConfigShow(cmd, args); err != nil {
+			exit(err)
+		}
+	},
+}
+
+// runConfigShow runs the config show command
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	if configShowFormat != "yaml" && configShowFormat != "json" {
+		return fmt.Errorf("invalid --format: %s (expected yaml or json)", configShowFormat)
+	}
+
+	configFile, _ := cmd.Flags().GetString("config")
+	effective, err := effectiveConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if configShowFormat == "json" {
+		data, err = json.MarshalIndent(effective, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(effective)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+// effectiveConfig merges the defaults, the config file and the environment into the
+// configuration the commands run with. The security settings are only read from JSON
+// config files, like those of the server command.
+func effectiveConfig(configFile string) (api.EffectiveConfig, error) {
+	settings, err := core.LoadConfig(configFile)
+	if err != nil {
+		return api.EffectiveConfig{}, fmt.Errorf("loading config file: %v", err)
+	}
+
+	envTokens := api.TokensFromEnv()
+	security := api.SecuritySettings{
+		RequireAuth:      len(envTokens) > 0,
+		APITokens:        []string{},
+		RateLimitPerHour: api.DefaultRateLimitPerHour,
+	}
+	var tokens []string
+	if configFile != "" && strings.EqualFold(filepath.Ext(configFile), ".json") {
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			return api.EffectiveConfig{}, fmt.Errorf("loading config file: %v", err)
+		}
+		if cfg.Security.RequireAuth {
+			security.RequireAuth = true
+			tokens = cfg.Security.APITokens
+		}
+		if cfg.Security.RateLimitPerHour > 0 {
+			security.RateLimitPerHour = cfg.Security.RateLimitPerHour
+		}
+	}
+	if security.RequireAuth {
+		security.APITokens = api.RedactTokens(append(tokens, envTokens...))
+	}
+
+	scanner := core.NewScanner()
+	registerDetectors(scanner, detectors.DefaultMaxFileSizeMB, detectors.DefaultEntropyThreshold, false)
+
+	return api.EffectiveConfig{
+		Config:    settings,
+		Security:  security,
+		Detectors: scanner.Detectors(),
+	}, nil
+}
+
+func init() {
+	// Add flags
+	configShowCmd.Flags().StringVar(&configShowFormat, "format", "yaml", "Output format (yaml, json)")
+
+	// Add subcommands
+	configCmd.AddCommand(configShowCmd)
+}
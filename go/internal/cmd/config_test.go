@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/api"
+)
+
+// 测试输出合并后的生效配置，API令牌被隐藏
+func TestConfigShow(t *testing.T) {
+	defer func() { configShowFormat = "yaml" }()
+	tmpdir, err := ioutil.TempDir("", "config-cmd-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	// 默认配置
+	output := captureStdout(t, func() {
+		assert.NoError(t, runConfigShow(configShowCmd, nil))
+	})
+	assert.Contains(t, output, "confidenceThreshold: 0.7")
+	assert.Contains(t, output, "name: python")
+
+	// 配置文件和环境变量覆盖默认值
+	configFile := filepath.Join(tmpdir, "config.json")
+	assert.NoError(t, ioutil.WriteFile(configFile, []byte(`{
+  "scanner": {"parallel": true},
+  "security": {"require_auth": true, "api_tokens": ["secret-token"], "rate_limit_per_hour": 60}
+}`), 0644))
+	os.Setenv("REMOVERY_SERVER_PORT", "9090")
+	defer os.Unsetenv("REMOVERY_SERVER_PORT")
+
+	effective, err := effectiveConfig(configFile)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, effective.Config.Scanner.Parallel)
+	assert.Equal(t, 9090, effective.Config.Server.Port)
+	assert.True(t, effective.Security.RequireAuth)
+	assert.Equal(t, api.RedactTokens([]string{"secret-token"}), effective.Security.APITokens)
+	assert.Equal(t, 60, effective.Security.RateLimitPerHour)
+
+	data, err := json.Marshal(effective)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-token")
+
+	// JSON输出可被解析
+	configShowFormat = "json"
+	output = captureStdout(t, func() {
+		assert.NoError(t, runConfigShow(configShowCmd, nil))
+	})
+	var decoded api.EffectiveConfig
+	assert.NoError(t, json.Unmarshal([]byte(output), &decoded))
+	assert.NotEmpty(t, decoded.Detectors)
+
+	configShowFormat = "toml"
+	assert.Error(t, runConfigShow(configShowCmd, nil))
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/re-movery/re-movery/internal/core"
+	"github.com/re-movery/re-movery/internal/detectors"
+	"github.com/re-movery/re-movery/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffDir          string
+	diffBase         string
+	diffHead         string
+	diffChangedLines bool
+	diffOutputFile   string
+	diffFormat       string
+	diffFailOn       string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Scan only the files changed in git",
+	Long: `Scan only the files changed between two git refs, e.g. the files of a pull request.
+The changed files are scanned as they are in the working tree, which should have the
+head ref checked out. Results are keyed by paths relative to the repository root.
+Examples:
+  re-movery diff
+  re-movery diff --base origin/develop --changed-lines
+  re-movery diff --dir path/to/repository --output report.json --fail-on high`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDiff(cmd, args); err != nil {
+			exit(err)
+		}
+	},
+}
+
+// runDiff runs the diff command and returns an error whose exit code is given by exitCode
+func runDiff(cmd *cobra.Command, args []string) error {
+	if diffFailOn != "" && severityRank(diffFailOn) == 0 {
+		return fmt.Errorf("invalid --fail-on severity: %s (expected high, medium or low)", diffFailOn)
+	}
+
+	repoDir, err := gitTopLevel(diffDir)
+	if err != nil {
+		return err
+	}
+
+	refRange := diffBase + "..." + diffHead
+	changedFiles, err := gitDiff(repoDir, refRange, "--name-only", "-z")
+	if err != nil {
+		return err
+	}
+
+	// Lines added or changed by the diff, by file
+	var changed map[string]map[int]bool
+	if diffChangedLines {
+		patch, err := gitDiff(repoDir, refRange, "-U0")
+		if err != nil {
+			return err
+		}
+		changed = diffAddedLines(patch)
+	}
+
+	scanner := core.NewScanner()
+	registerDetectors(scanner, detectors.DefaultMaxFileSizeMB, detectors.DefaultEntropyThreshold, false)
+
+	results := make(map[string][]core.Match)
+	startTime := time.Now()
+	scanned := 0
+	// Names are NUL-terminated, so that git does not quote unusual paths
+	for _, name := range strings.Split(changedFiles, "\x00") {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if name == "" || !scanner.SupportsFile(path) {
+			continue
+		}
+
+		matches, err := scanner.ScanFile(path)
+		if err != nil {
+			// Log error but continue
+			fmt.Fprintf(os.Stderr, "Error scanning file %s: %v\n", name, err)
+			continue
+		}
+		scanned++
+
+		for _, match := range matches {
+			if changed != nil && !changed[name][match.LineNumber] {
+				continue
+			}
+			match.FilePath = name
+			match.Explanation = nil
+			results[name] = append(results[name], match)
+		}
+	}
+	duration := time.Since(startTime)
+
+	summary := core.GenerateSummary(results)
+	fmt.Printf("Changed files scanned: %d (%s)\n", scanned, refRange)
+	fmt.Printf("Issues found: %d (High: %d, Medium: %d, Low: %d)\n",
+		summary.Total(), summary.High, summary.Medium, summary.Low)
+
+	if diffOutputFile != "" {
+		reporter, err := newReporter(diffFormat, diffOutputFile)
+		if err != nil {
+			return err
+		}
+
+		reportData := core.ReportData{
+			Title:     "Re-movery Security Scan Report (" + refRange + ")",
+			Timestamp: time.Now().Format(time.RFC3339),
+			Results:   results,
+			Summary:   summary,
+			Duration:  duration.Seconds(),
+		}
+		if err := reporter.GenerateReport(reportData, diffOutputFile); err != nil {
+			return fmt.Errorf("generating report: %v", err)
+		}
+
+		fmt.Printf("Report generated: %s\n", diffOutputFile)
+	}
+
+	return checkFailOn(summary, diffFailOn)
+}
+
+// gitTopLevel returns the root of the git repository containing dir
+func gitTopLevel(dir string) (string, error) {
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not a git repository", dir)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitDiff runs git diff with the given options over a ref range, leaving out deleted
+// files. The range is never taken as an option, even if it starts with a dash.
+func gitDiff(repoDir string, refRange string, options ...string) (string, error) {
+	args := append([]string{"-C", repoDir, "diff"}, options...)
+	args = append(args, "--diff-filter=d", "--end-of-options", refRange, "--")
+	cmd := exec.Command("git", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff %s: %v: %s", refRange, err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// diffAddedLines returns the lines added by a unified diff of several files, by file
+// path relative to the repository root
+func diffAddedLines(diff string) map[string]map[int]bool {
+	patches := make(map[string]*strings.Builder)
+	var current *strings.Builder
+	inHeader := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current, inHeader = nil, true
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			// New files are named b/<path>; deleted files /dev/null. git ends names
+			// containing spaces with a tab and quotes unusual names C-style.
+			name := strings.TrimSuffix(strings.TrimPrefix(line, "+++ "), "\t")
+			if strings.HasPrefix(name, `"`) {
+				if unquoted, err := strconv.Unquote(name); err == nil {
+					name = unquoted
+				}
+			}
+			name = strings.TrimPrefix(name, "b/")
+			current = &strings.Builder{}
+			patches[name] = current
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+			fallthrough
+		case !inHeader && current != nil:
+			current.WriteString(line)
+			current.WriteString("\n")
+		}
+	}
+
+	lines := make(map[string]map[int]bool, len(patches))
+	for name, patch := range patches {
+		lines[name] = notify.ChangedLines(patch.String())
+	}
+	return lines
+}
+
+func init() {
+	// Add flags
+	diffCmd.Flags().StringVar(&diffDir, "dir", ".", "Directory in the git repository to diff")
+	diffCmd.Flags().StringVar(&diffBase, "base", "origin/main", "Base ref; files are diffed against the merge base of base and head")
+	diffCmd.Flags().StringVar(&diffHead, "head", "HEAD", "Head ref")
+	diffCmd.Flags().BoolVar(&diffChangedLines, "changed-lines", false, "Only report findings on lines added or changed by the diff")
+	diffCmd.Flags().StringVar(&diffOutputFile, "output", "", "Output file for the report")
+	diffCmd.Flags().StringVar(&diffFormat, "format", "", "Report format (html, json, xml, csv, junit, ndjson, gitlab, text)")
+	diffCmd.Flags().StringVar(&diffFailOn, "fail-on", "", "Exit with code 2 if any finding is at or above this severity (high, medium, low)")
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 重置diff命令的标志
+func resetDiffFlags() {
+	diffDir = "."
+	diffBase = "origin/main"
+	diffHead = "HEAD"
+	diffChangedLines = false
+	diffOutputFile = ""
+	diffFormat = ""
+	diffFailOn = ""
+}
+
+// 测试只扫描git中变更的文件
+func TestDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	defer resetDiffFlags()
+
+	repo, err := ioutil.TempDir("", "diff-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	runGit(t, repo, "init", "-q")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repo, "old.py"), []byte("result = eval(user_input)\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repo, "app.py"), []byte("import os\nresult = eval(user_input)\n"), 0644))
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "base")
+	runGit(t, repo, "branch", "base")
+
+	// 修改app.py，新增new.py和不支持的文件
+	runGit(t, repo, "checkout", "-q", "-b", "feature")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repo, "app.py"), []byte("import os\nresult = eval(user_input)\nvalue = eval(other)\n"), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(repo, "src"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repo, "src", "new.py"), []byte("value = eval(request_data)\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repo, "notes.txt"), []byte("eval(x)\n"), 0644))
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "feature")
+
+	readReport := func(path string) core.ReportData {
+		content, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		var report core.ReportData
+		assert.NoError(t, json.Unmarshal(content, &report))
+		return report
+	}
+
+	// 扫描变更文件中的所有问题
+	resetDiffFlags()
+	diffDir = repo
+	diffBase = "base"
+	diffOutputFile = filepath.Join(repo, "report.json")
+	diffFailOn = "high"
+	err = runDiff(diffCmd, nil)
+	assert.Equal(t, ExitFindings, exitCode(err))
+
+	report := readReport(diffOutputFile)
+	assert.Len(t, report.Results, 2)
+	assert.Len(t, report.Results["app.py"], 2)
+	if assert.Len(t, report.Results["src/new.py"], 1) {
+		assert.Equal(t, "src/new.py", report.Results["src/new.py"][0].FilePath)
+	}
+
+	// 只报告变更行上的问题
+	diffChangedLines = true
+	diffFailOn = ""
+	assert.NoError(t, runDiff(diffCmd, nil))
+	report = readReport(diffOutputFile)
+	if assert.Len(t, report.Results["app.py"], 1) {
+		assert.Equal(t, 3, report.Results["app.py"][0].LineNumber)
+	}
+	assert.Len(t, report.Results["src/new.py"], 1)
+
+	// 无效的基准引用
+	resetDiffFlags()
+	diffDir = repo
+	diffBase = "missing"
+	assert.Error(t, runDiff(diffCmd, nil))
+}
+
+// 测试diff报告中的秘密被脱敏
+func TestDiffRedactsSecrets(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	defer resetDiffFlags()
+
+	repo, err := ioutil.TempDir("", "diff-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	runGit(t, repo, "init", "-q")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "base")
+	runGit(t, repo, "branch", "base")
+	secret := "S3cr3tPassw0rd"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(repo, "settings.py"), []byte("password = \""+secret+"\"\n"), 0644))
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "secret")
+
+	resetDiffFlags()
+	diffDir = repo
+	diffBase = "base"
+	diffOutputFile = filepath.Join(repo, "report.json")
+	assert.NoError(t, runDiff(diffCmd, nil))
+
+	content, err := ioutil.ReadFile(diffOutputFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), core.RedactedSecret)
+	assert.NotContains(t, string(content), secret)
+}
+
+// 测试特殊文件名和以短横线开头的引用
+func TestDiffUnusualNames(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	defer resetDiffFlags()
+
+	repo, err := ioutil.TempDir("", "diff-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	runGit(t, repo, "init", "-q")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "base")
+	runGit(t, repo, "branch", "base")
+	name := "módulo \"x\".py"
+	spaced := "my module.py"
+	for _, file := range []string{name, spaced} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(repo, file), []byte("result = eval(user_input)\n"), 0644))
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "feature")
+
+	// git会为这样的文件名加引号，但仍按原样扫描，也能匹配差异中的新增行
+	for _, changedLines := range []bool{false, true} {
+		resetDiffFlags()
+		diffDir = repo
+		diffBase = "base"
+		diffChangedLines = changedLines
+		diffOutputFile = filepath.Join(repo, "report.json")
+		assert.NoError(t, runDiff(diffCmd, nil))
+		content, err := ioutil.ReadFile(diffOutputFile)
+		assert.NoError(t, err)
+		var report core.ReportData
+		assert.NoError(t, json.Unmarshal(content, &report))
+		assert.Len(t, report.Results[name], 1, "changed lines: %v", changedLines)
+		assert.Len(t, report.Results[spaced], 1, "changed lines: %v", changedLines)
+	}
+
+	// 以短横线开头的引用不会被当作git选项
+	output := filepath.Join(repo, "injected.txt")
+	resetDiffFlags()
+	diffDir = repo
+	diffBase = "--output=" + output
+	assert.Error(t, runDiff(diffCmd, nil))
+	assert.NoFileExists(t, output)
+}
+
+// 测试目标不是git仓库时返回明确的错误
+func TestDiffNotGitRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	defer resetDiffFlags()
+
+	tmpdir, err := ioutil.TempDir("", "diff-nogit")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	resetDiffFlags()
+	diffDir = tmpdir
+	err = runDiff(diffCmd, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "is not a git repository")
+	}
+	assert.Equal(t, ExitError, exitCode(err))
+}
+
+// 测试从多文件差异中提取新增行
+func TestDiffAddedLines(t *testing.T) {
+	diff := `diff --git a/app.py b/app.py
+index 1111111..2222222 100644
+--- a/app.py
++++ b/app.py
+@@ -2,0 +3 @@ result = eval(user_input)
++value = eval(other)
+diff --git a/src/new.py b/src/new.py
+new file mode 100644
+--- /dev/null
++++ b/src/new.py
+@@ -0,0 +1,2 @@
++++counter
++x = 1
+diff --git a/my app.py b/my app.py
+--- a/my app.py	
++++ b/my app.py	
+@@ -1,0 +2 @@
++y = 2
+diff --git "a/m\303\263dulo.py" "b/m\303\263dulo.py"
+--- "a/m\303\263dulo.py"
++++ "b/m\303\263dulo.py"
+@@ -4,0 +5 @@
++z = 3
+`
+	assert.Equal(t, map[string]map[int]bool{
+		"app.py":     {3: true},
+		"src/new.py": {1: true, 2: true},
+		"my app.py":  {2: true},
+		"módulo.py":  {5: true},
+	}, diffAddedLines(diff))
+}
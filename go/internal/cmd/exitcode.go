@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Exit codes shared by all re-movery commands:
+//
+//	0  the command completed and no threshold was exceeded
+//	1  operational error (invalid flags, unreadable input, report failure, ...)
+//	2  findings exceed the --fail-on threshold or a --max-* budget
+//	3  the share of successfully scanned files is below --min-coverage
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitFindings = 2
+	ExitCoverage = 3
+)
+
+// findingsError reports that findings exceed the configured severity threshold
+type findingsError struct {
+	message string
+}
+
+func (e *findingsError) Error() string {
+	return e.message
+}
+
+// coverageError reports that too few of the candidate files were scanned
+type coverageError struct {
+	coverage    float64
+	minCoverage float64
+}
+
+func (e *coverageError) Error() string {
+	return fmt.Sprintf("scan coverage %.1f%% is below the required %.1f%%", e.coverage, e.minCoverage)
+}
+
+// exitCode maps an error returned by a command to its process exit code
+func exitCode(err error) int {
+	var fErr *findingsError
+	var cErr *coverageError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &fErr):
+		return ExitFindings
+	case errors.As(err, &cErr):
+		return ExitCoverage
+	default:
+		return ExitError
+	}
+}
+
+// exit prints err to stderr and terminates the process with the matching exit code
+func exit(err error) {
+	if err == nil {
+		os.Exit(ExitOK)
+	}
+
+	if exitCode(err) == ExitError {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	} else {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(exitCode(err))
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+	"github.com/re-movery/re-movery/internal/detectors"
+	"github.com/spf13/cobra"
+)
+
+// maxSuggestions is the number of close rule IDs suggested for an unknown rule ID
+const maxSuggestions = 3
+
+var (
+	explainFormat        string
+	explainSignaturesDir string
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain RULEID",
+	Short: "Explain a detection rule",
+	Long: `Explain a detection rule: its name, severity and description, examples of
+vulnerable and safe code where documented, and references.
+Examples:
+  re-movery explain JS007
+  re-movery explain py001 --format json
+  re-movery explain ORG001 --signatures-dir signatures/`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runExplain(cmd, args); err != nil {
+			exit(err)
+		}
+	},
+}
+
+// runExplain runs the explain command
+func runExplain(cmd *cobra.Command, args []string) error {
+	if explainFormat != "text" && explainFormat != "json" {
+		return fmt.Errorf("invalid --format: %s (expected text or json)", explainFormat)
+	}
+
+	scanner := core.NewScanner()
+	registerDetectors(scanner, detectors.DefaultMaxFileSizeMB, detectors.DefaultEntropyThreshold, false)
+	if explainSignaturesDir != "" {
+		customDetector := detectors.NewCustomDetector()
+		if err := customDetector.LoadSignaturesDir(explainSignaturesDir); err != nil {
+			return fmt.Errorf("loading --signatures-dir: %v", err)
+		}
+		scanner.RegisterDetector(customDetector)
+	}
+
+	rules := scanner.Signatures("")
+	id := strings.ToUpper(strings.TrimSpace(args[0]))
+	var rule *core.Signature
+	for i := range rules {
+		if rules[i].ID == id {
+			rule = &rules[i]
+			break
+		}
+	}
+	if rule == nil {
+		if suggestions := closeRuleIDs(id, rules); len(suggestions) > 0 {
+			return fmt.Errorf("unknown rule ID: %s (did you mean %s?)", args[0], strings.Join(suggestions, ", "))
+		}
+		return fmt.Errorf("unknown rule ID: %s (see re-movery rules list)", args[0])
+	}
+
+	if explainFormat == "json" {
+		data, err := json.MarshalIndent(rule, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s  %s\n\n", rule.ID, rule.Name)
+	fmt.Printf("Severity: %s\n", rule.Severity)
+	if rule.Category != "" {
+		fmt.Printf("Category: %s\n", rule.Category)
+	}
+	if rule.CWE != "" {
+		fmt.Printf("CWE:      %s\n", rule.CWE)
+	}
+	fmt.Printf("\n%s\n", rule.Description)
+
+	if docs := rule.Docs; docs != nil {
+		if docs.VulnerableExample != "" {
+			fmt.Printf("\nVulnerable example:\n%s\n", indentLines(docs.VulnerableExample))
+		}
+		if docs.SafeExample != "" {
+			fmt.Printf("\nSafe example:\n%s\n", indentLines(docs.SafeExample))
+		}
+		if docs.Remediation != "" {
+			fmt.Printf("\nRemediation:\n%s\n", indentLines(docs.Remediation))
+		}
+	}
+
+	if len(rule.References) > 0 {
+		fmt.Println("\nReferences:")
+		for _, reference := range rule.References {
+			fmt.Printf("  - %s\n", reference)
+		}
+	}
+	return nil
+}
+
+// indentLines indents each line of text for the text output of explain
+func indentLines(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// closeRuleIDs returns the rule IDs within two edits of id, closest first
+func closeRuleIDs(id string, rules []core.Signature) []string {
+	type candidate struct {
+		id       string
+		distance int
+	}
+	var candidates []candidate
+	for _, rule := range rules {
+		if distance := editDistance(id, rule.ID); distance <= 2 {
+			candidates = append(candidates, candidate{rule.ID, distance})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].id < candidates[j].id
+	})
+
+	suggestions := []string{}
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].id)
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func init() {
+	// Add flags
+	explainCmd.Flags().StringVar(&explainFormat, "format", "text", "Output format (text, json)")
+	explainCmd.Flags().StringVar(&explainSignaturesDir, "signatures-dir", "", "Directory of JSON and YAML signature files whose rules can be explained in addition to the built-in rules")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 重置explain命令的标志
+func resetExplainFlags() {
+	explainFormat = "text"
+	explainSignaturesDir = ""
+}
+
+// 测试解释规则，包括示例代码、JSON输出和自定义签名
+func TestExplain(t *testing.T) {
+	defer resetExplainFlags()
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, runExplain(explainCmd, []string{"js007"}))
+	})
+	assert.Contains(t, output, "JS007  Potential prototype pollution")
+	assert.Contains(t, output, "Severity: high")
+	assert.Contains(t, output, "CWE:      CWE-1321")
+	assert.Contains(t, output, "Vulnerable example:\n    target.__proto__.isAdmin")
+	assert.Contains(t, output, "Safe example:\n    const target = Object.create(null);\n    target.isAdmin")
+	assert.Contains(t, output, "References:\n  - https://")
+
+	// 没有扩展文档的规则只显示目录中的信息
+	output = captureStdout(t, func() {
+		assert.NoError(t, runExplain(explainCmd, []string{"JS008"}))
+	})
+	assert.Contains(t, output, "Insecure JWT verification")
+	assert.NotContains(t, output, "example:")
+
+	explainFormat = "json"
+	output = captureStdout(t, func() {
+		assert.NoError(t, runExplain(explainCmd, []string{"PY001"}))
+	})
+	var rule core.Signature
+	assert.NoError(t, json.Unmarshal([]byte(output), &rule))
+	assert.Equal(t, "PY001", rule.ID)
+	if assert.NotNil(t, rule.Docs) {
+		assert.Contains(t, rule.Docs.SafeExample, "ast.literal_eval")
+	}
+
+	// 自定义签名文件可以提供扩展文档
+	dir, err := ioutil.TempDir("", "explain")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	signatures := `signatures:
+  - id: ORG001
+    name: Internal debug endpoint
+    severity: medium
+    description: Debug endpoints must not be deployed
+    codePatterns: ["/debug/"]
+    languages: [go]
+    docs:
+      safeExample: mux.Handle("/healthz", health)
+`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "org.yaml"), []byte(signatures), 0644))
+	explainSignaturesDir = dir
+	output = captureStdout(t, func() {
+		assert.NoError(t, runExplain(explainCmd, []string{"ORG001"}))
+	})
+	assert.NoError(t, json.Unmarshal([]byte(output), &rule))
+	if assert.NotNil(t, rule.Docs) {
+		assert.Equal(t, `mux.Handle("/healthz", health)`, rule.Docs.SafeExample)
+	}
+
+	explainFormat = "yaml"
+	assert.Error(t, runExplain(explainCmd, []string{"PY001"}))
+}
+
+// 测试未知规则ID时提示相近的规则
+func TestExplainUnknownRule(t *testing.T) {
+	defer resetExplainFlags()
+
+	err := runExplain(explainCmd, []string{"JS07"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown rule ID: JS07")
+		assert.Contains(t, err.Error(), "did you mean JS007, ")
+	}
+
+	err = runExplain(explainCmd, []string{"NOSUCHRULE"})
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "did you mean")
+	}
+
+	assert.Equal(t, 0, editDistance("JS007", "JS007"))
+	assert.Equal(t, 1, editDistance("JS07", "JS007"))
+	assert.Equal(t, 2, editDistance("PY001", "PY010"))
+}
@@ -136,10 +136,12 @@ security-scan:
   image: golang:1.17
   script:
     - go install github.com/re-movery/re-movery@latest
-    - re-movery scan --dir . --exclude "vendor,node_modules,*.min.js" --output report.html --format html
+    - re-movery scan --dir . --exclude "vendor,node_modules,*.min.js" --output gl-sast-report.json --format gitlab
   artifacts:
+    reports:
+      sast: gl-sast-report.json
     paths:
-      - report.html
+      - gl-sast-report.json
     expire_in: 1 week
 `
 	
@@ -281,11 +283,11 @@ async function scanWorkspace() {
             updateDiagnostics(results);
             
             const totalIssues = Object.values(results).reduce((sum, matches) => sum + matches.length, 0);
-            vscode.window.showInformationMessage(\`Workspace scan completed. Found \${totalIssues} issues.\`);
+            vscode.window.showInformationMessage(` + "`" + `Workspace scan completed. Found ${totalIssues} issues.` + "`" + `);
             
             progress.report({ increment: 100 });
         } catch (error) {
-            vscode.window.showErrorMessage(\`Error scanning workspace: \${error.message}\`);
+            vscode.window.showErrorMessage(` + "`" + `Error scanning workspace: ${error.message}` + "`" + `);
         }
     });
 }
@@ -347,13 +349,13 @@ async function scanCode(code, filename) {
                         reject(new Error('Invalid response from server'));
                     }
                 } else {
-                    reject(new Error(\`Server returned status code \${res.statusCode}\`));
+                    reject(new Error(` + "`" + `Server returned status code ${res.statusCode}` + "`" + `));
                 }
             });
         });
         
         req.on('error', (error) => {
-            reject(new Error(\`Error connecting to Re-movery server: \${error.message}\`));
+            reject(new Error(` + "`" + `Error connecting to Re-movery server: ${error.message}` + "`" + `));
         });
         
         req.write(postData);
@@ -398,13 +400,13 @@ async function scanDirectory(directory) {
                         reject(new Error('Invalid response from server'));
                     }
                 } else {
-                    reject(new Error(\`Server returned status code \${res.statusCode}\`));
+                    reject(new Error(` + "`" + `Server returned status code ${res.statusCode}` + "`" + `));
                 }
             });
         });
         
         req.on('error', (error) => {
-            reject(new Error(\`Error connecting to Re-movery server: \${error.message}\`));
+            reject(new Error(` + "`" + `Error connecting to Re-movery server: ${error.message}` + "`" + `));
         });
         
         req.write(postData);
@@ -442,7 +444,7 @@ function updateDiagnosticsForFile(uri, results) {
         
         return new vscode.Diagnostic(
             range,
-            \`\${match.name}: \${match.description}\`,
+            ` + "`" + `${match.name}: ${match.description}` + "`" + `,
             severity
         );
     });
@@ -491,9 +493,9 @@ This extension integrates the Re-movery security scanner into VS Code, providing
 
 This extension contributes the following settings:
 
-* \`re-movery.serverHost\`: Host of the Re-movery API server
-* \`re-movery.serverPort\`: Port of the Re-movery API server
-* \`re-movery.enableBackgroundScanning\`: Enable background scanning of files
+* ` + "`" + `re-movery.serverHost` + "`" + `: Host of the Re-movery API server
+* ` + "`" + `re-movery.serverPort` + "`" + `: Port of the Re-movery API server
+* ` + "`" + `re-movery.enableBackgroundScanning` + "`" + `: Enable background scanning of files
 
 ## Known Issues
 
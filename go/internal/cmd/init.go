@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/re-movery/re-movery/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initForce      bool
+	initSignatures bool
+)
+
+// defaultConfigFile is the config file written by init when no path is given
+const defaultConfigFile = "config.yaml"
+
+// exampleSignatures is the signature file written by init --signatures
+const exampleSignatures = `{
+  "signatures": [
+    {
+      "id": "ORG001",
+      "name": "Internal debug endpoint",
+      "severity": "medium",
+      "description": "Debug endpoints expose internal state and must not be deployed",
+      "category": "exposure",
+      "codePatterns": ["/debug/"],
+      "references": [],
+      "languages": ["py", "js"]
+    }
+  ]
+}
+`
+
+var initCmd = &cobra.Command{
+	Use:   "init [config-file]",
+	Short: "Create a config file with the default settings",
+	Long: `Create a config file (config.yaml by default) with the default settings.
+The format is chosen by the extension: .yaml, .yml or .json; YAML files describe
+each setting in comments. Existing files are not overwritten unless --force is given.
+Examples:
+  re-movery init
+  re-movery init re-movery.json
+  re-movery init --signatures`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runInit(cmd, args); err != nil {
+			exit(err)
+		}
+	},
+}
+
+// runInit runs the init command
+func runInit(cmd *cobra.Command, args []string) error {
+	configPath := defaultConfigFile
+	if len(args) > 0 {
+		configPath = args[0]
+	}
+	signaturesDir := filepath.Join(filepath.Dir(configPath), "signatures")
+	signaturesPath := filepath.Join(signaturesDir, "signatures.json")
+
+	// Check every file before writing any of them
+	paths := []string{configPath}
+	if initSignatures {
+		paths = append(paths, signaturesPath)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil && !initForce {
+			return fmt.Errorf("%s already exists (use --force to overwrite it)", path)
+		}
+	}
+
+	if err := core.SaveConfig(core.NewConfig(), configPath); err != nil {
+		return fmt.Errorf("writing config file: %v", err)
+	}
+	fmt.Printf("Config file written: %s\n", configPath)
+
+	if initSignatures {
+		if err := os.MkdirAll(signaturesDir, 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(signaturesPath, []byte(exampleSignatures), 0644); err != nil {
+			return fmt.Errorf("writing signature file: %v", err)
+		}
+		fmt.Printf("Example signature file written: %s\n", signaturesPath)
+	}
+
+	fmt.Println("\nNext steps:")
+	fmt.Printf("  1. Review the settings in %s\n", configPath)
+	if initSignatures {
+		fmt.Printf("  2. Replace the example rule in %s with your own\n", signaturesPath)
+		fmt.Printf("  3. Scan with: re-movery scan --dir . --config %s --signatures-dir %s\n", configPath, signaturesDir)
+	} else {
+		fmt.Printf("  2. Scan with: re-movery scan --dir . --config %s\n", configPath)
+	}
+	return nil
+}
+
+func init() {
+	// Add flags
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite existing files")
+	initCmd.Flags().BoolVar(&initSignatures, "signatures", false, "Also create signatures/signatures.json with an example rule for --signatures-dir")
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+	"github.com/re-movery/re-movery/internal/detectors"
+)
+
+// 重置init命令的标志
+func resetInitFlags() {
+	initForce = false
+	initSignatures = false
+}
+
+// 测试生成默认配置文件和示例签名文件，已存在的文件只在--force时覆盖
+func TestInit(t *testing.T) {
+	defer resetInitFlags()
+	tmpdir, err := ioutil.TempDir("", "init-cmd-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	configPath := filepath.Join(tmpdir, "config.yaml")
+	initSignatures = true
+	output := captureStdout(t, func() {
+		assert.NoError(t, runInit(initCmd, []string{configPath}))
+	})
+	assert.Contains(t, output, "Next steps:")
+	assert.Contains(t, output, "--signatures-dir "+filepath.Join(tmpdir, "signatures"))
+
+	// 配置文件带有说明，且与默认配置一致
+	data, err := ioutil.ReadFile(configPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "# Scanner settings")
+	config, err := core.LoadConfig(configPath)
+	assert.NoError(t, err)
+	assert.Equal(t, core.NewConfig(), config)
+
+	// 示例签名文件可以被--signatures-dir加载
+	detector := detectors.NewCustomDetector()
+	assert.NoError(t, detector.LoadSignaturesDir(filepath.Join(tmpdir, "signatures")))
+	assert.Len(t, detector.Signatures(), 1)
+
+	// 不覆盖已存在的文件
+	assert.NoError(t, ioutil.WriteFile(configPath, []byte("scanner: {}\n"), 0644))
+	err = runInit(initCmd, []string{configPath})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "--force")
+	}
+	data, err = ioutil.ReadFile(configPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "scanner: {}\n", string(data))
+
+	initForce = true
+	captureStdout(t, func() {
+		assert.NoError(t, runInit(initCmd, []string{configPath}))
+	})
+	data, err = ioutil.ReadFile(configPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "confidenceThreshold: 0.7")
+}
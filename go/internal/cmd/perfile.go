@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// perFileIndexName is the name of the index written next to the per-file reports. The
+// underscore keeps it apart from the report of a source file named index, e.g.
+// index.json for a file named index in json format.
+const perFileIndexName = "_index.json"
+
+// formatExtensions are the extensions of per-file reports by format
+var formatExtensions = map[string]string{
+	"html":   ".html",
+	"json":   ".json",
+	"xml":    ".xml",
+	"csv":    ".csv",
+	"junit":  ".xml",
+	"ndjson": ".ndjson",
+	"gitlab": ".json",
+	"text":   ".txt",
+}
+
+// perFileIndex lists the per-file reports, so that tools can link each source file
+// to its report
+type perFileIndex struct {
+	Title     string              `json:"title"`
+	Timestamp string              `json:"timestamp"`
+	Format    string              `json:"format"`
+	Files     []perFileIndexEntry `json:"files"`
+}
+
+// perFileIndexEntry is the report of one source file. Paths are slash-separated and
+// relative to the scan root and the output directory respectively.
+type perFileIndexEntry struct {
+	File    string       `json:"file"`
+	Report  string       `json:"report"`
+	Summary core.Summary `json:"summary"`
+}
+
+// splitCleanFiles removes the files without findings from results and returns them
+// in lexical order
+func splitCleanFiles(results map[string][]core.Match) []string {
+	clean := []string{}
+	for filePath, matches := range results {
+		if len(matches) == 0 {
+			clean = append(clean, filePath)
+			delete(results, filePath)
+		}
+	}
+	sort.Strings(clean)
+	return clean
+}
+
+// writePerFileReports writes one report in the given format for each file of the
+// report data, and for each of the clean files, to outputDir, mirroring the paths of
+// the files relative to baseDir, and an index of the reports. It returns the number of
+// reports written.
+func writePerFileReports(data core.ReportData, clean []string, baseDir string, outputDir string, format string) (int, error) {
+	if format == "" {
+		format = "html"
+	}
+	format = strings.ToLower(format)
+	reporter, err := newReporter(format, "")
+	if err != nil {
+		return 0, err
+	}
+
+	files := append([]string{}, clean...)
+	for filePath := range data.Results {
+		files = append(files, filePath)
+	}
+	sort.Strings(files)
+
+	index := perFileIndex{
+		Title:     data.Title,
+		Timestamp: data.Timestamp,
+		Format:    format,
+		Files:     []perFileIndexEntry{},
+	}
+	for _, filePath := range files {
+		relPath := reportRelPath(filePath, baseDir)
+		reportPath := relPath + formatExtensions[format]
+		if reportPath == perFileIndexName {
+			return 0, fmt.Errorf("the report of %s would overwrite the index %s", filePath, perFileIndexName)
+		}
+		target := filepath.Join(outputDir, filepath.FromSlash(reportPath))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return 0, err
+		}
+
+		fileData := data.ForFile(filePath)
+		if err := reporter.GenerateReport(fileData, target); err != nil {
+			return 0, fmt.Errorf("generating report %s: %v", target, err)
+		}
+		index.Files = append(index.Files, perFileIndexEntry{
+			File:    relPath,
+			Report:  reportPath,
+			Summary: fileData.Summary,
+		})
+	}
+
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(outputDir, perFileIndexName), content, 0644); err != nil {
+		return 0, err
+	}
+
+	return len(files), nil
+}
+
+// reportRelPath returns the slash-separated path of a scanned file relative to baseDir,
+// without parent directory references, so that its report stays in the output directory
+func reportRelPath(filePath string, baseDir string) string {
+	relPath := filePath
+	if baseDir != "" {
+		if rel, err := filepath.Rel(baseDir, filePath); err == nil {
+			relPath = rel
+		}
+	}
+
+	segments := []string{}
+	for _, segment := range strings.Split(filepath.ToSlash(relPath), "/") {
+		if segment != "" && segment != "." && segment != ".." {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
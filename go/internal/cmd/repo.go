@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// repoTokenEnv is the environment variable holding the token used to clone private
+// repositories with --repo
+const repoTokenEnv = "REMOVERY_GIT_TOKEN"
+
+// scanRepository shallow-clones the git repository at repoURL into a temporary
+// directory, scans it like --dir and removes the clone. Paths in the results are
+// relative to the repository root.
+func scanRepository(ctx context.Context, scanner *core.Scanner, repoURL string, ref string, excludePatterns []string) (map[string][]core.Match, error) {
+	tmpdir, err := ioutil.TempDir("", "re-movery-repo")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpdir)
+
+	if err := cloneRepo(repoURL, ref, tmpdir, os.Getenv(repoTokenEnv)); err != nil {
+		return nil, err
+	}
+
+	results, err := scanner.ScanDirectoryContext(ctx, tmpdir, excludePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return relativeResults(results, tmpdir), nil
+}
+
+// cloneRepo checks out ref (a branch, tag or commit; the default branch if empty) of the
+// repository at repoURL into dir, fetching only that commit. The token, if any, is sent
+// as an HTTP authorization header through the environment of git, so that it appears
+// neither on the command line nor in the clone's config.
+func cloneRepo(repoURL string, ref string, dir string, token string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git is required for --repo but was not found in PATH")
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// Fail instead of prompting for credentials
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if token != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+		)
+	}
+
+	// Fetching a single ref works for commits as well, which git clone --branch does not accept
+	steps := [][]string{
+		{"init", "-q", dir},
+		{"-C", dir, "fetch", "-q", "--depth", "1", "--", repoURL, ref},
+		{"-C", dir, "checkout", "-q", "--detach", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		command := exec.Command("git", args...)
+		command.Env = env
+		if output, err := command.CombinedOutput(); err != nil {
+			return fmt.Errorf("cloning %s at %s: %v: %s", redactURL(repoURL), ref, err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	// The history is not scanned
+	return os.RemoveAll(filepath.Join(dir, ".git"))
+}
+
+// redactURL hides a password or token embedded in a repository URL
+func redactURL(repoURL string) string {
+	u, err := url.Parse(repoURL)
+	if err != nil || u.User == nil {
+		return repoURL
+	}
+	return u.Redacted()
+}
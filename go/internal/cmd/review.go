@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/re-movery/re-movery/internal/core"
+	"github.com/re-movery/re-movery/internal/notify"
+)
+
+// newReviewNotifier creates the notifier for --github-pr. The token defaults to the
+// GITHUB_TOKEN environment variable.
+func newReviewNotifier(ref string, token string) (*notify.GitHubReviewNotifier, error) {
+	owner, repo, number, err := notify.ParsePullRequest(ref)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	return notify.NewGitHubReviewNotifier(owner, repo, number, token), nil
+}
+
+// reviewMatches returns the matches of the results with file paths relative to the
+// repository root baseDir, as used by the GitHub API
+func reviewMatches(results map[string][]core.Match, baseDir string) []core.Match {
+	var matches []core.Match
+	for filePath, fileMatches := range results {
+		relPath, err := filepath.Rel(baseDir, filePath)
+		if err != nil {
+			relPath = filePath
+		}
+		for _, match := range fileMatches {
+			match.FilePath = filepath.ToSlash(relPath)
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/re-movery/re-movery/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +14,14 @@ var rootCmd = &cobra.Command{
 	Long: `Re-movery is a powerful security vulnerability scanner designed to detect 
 potential security issues in your codebase. It supports multiple programming 
 languages and provides various interfaces for scanning and reporting.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Debug messages, e.g. about skipped binary files, go to stderr so that they
+		// do not mix with reports written to stdout
+		if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+			utils.GetLogger().SetOutput(os.Stderr)
+			utils.SetVerbosity(true)
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// If no subcommand is provided, print help
 		cmd.Help()
@@ -31,9 +40,14 @@ func init() {
 
 	// Add subcommands
 	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(diffCmd)
 	rootCmd.AddCommand(webCmd)
 	rootCmd.AddCommand(serverCmd)
 	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(rulesCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+	"github.com/re-movery/re-movery/internal/detectors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rulesLanguage string
+	rulesFormat   string
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Inspect the detection rules",
+	Long: `Inspect the detection rules of the registered detectors.
+Examples:
+  re-movery rules list
+  re-movery rules list --language python
+  re-movery rules list --format json`,
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the detection rules",
+	Long: `List every rule the detectors can report, sorted by ID. The IDs can be used with
+--disable-rules and --enable-only of the scan command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runRulesList(cmd, args); err != nil {
+			exit(err)
+		}
+	},
+}
+
+// runRulesList runs the rules list command
+func runRulesList(cmd *cobra.Command, args []string) error {
+	if rulesFormat != "text" && rulesFormat != "json" {
+		return fmt.Errorf("invalid --format: %s (expected text or json)", rulesFormat)
+	}
+
+	scanner := core.NewScanner()
+	registerDetectors(scanner, detectors.DefaultMaxFileSizeMB, detectors.DefaultEntropyThreshold, false)
+	rules := scanner.Signatures(strings.ToLower(rulesLanguage))
+
+	if rulesFormat == "json" {
+		data, err := json.MarshalIndent(rules, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, rule := range rules {
+		fmt.Printf("%-8s %-7s %s\n", rule.ID, rule.Severity, rule.Name)
+		fmt.Printf("         %s\n", rule.Description)
+	}
+	fmt.Printf("Rules: %d\n", len(rules))
+	return nil
+}
+
+func init() {
+	// Add flags
+	rulesListCmd.Flags().StringVar(&rulesLanguage, "language", "", "Only list the rules of detectors that support this language")
+	rulesListCmd.Flags().StringVar(&rulesFormat, "format", "text", "Output format (text, json)")
+
+	// Add subcommands
+	rulesCmd.AddCommand(rulesListCmd)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 重置rules命令的标志
+func resetRulesFlags() {
+	rulesLanguage = ""
+	rulesFormat = "text"
+}
+
+// 测试以文本和JSON格式列出规则
+func TestRulesList(t *testing.T) {
+	defer resetRulesFlags()
+
+	output := captureStdout(t, func() {
+		assert.NoError(t, runRulesList(rulesListCmd, nil))
+	})
+	assert.Contains(t, output, "PY001")
+	assert.Contains(t, output, "JS001")
+	assert.Contains(t, output, "SEC001")
+
+	// 按语言过滤，JSON输出可被解析
+	rulesLanguage = "python"
+	rulesFormat = "json"
+	output = captureStdout(t, func() {
+		assert.NoError(t, runRulesList(rulesListCmd, nil))
+	})
+	var rules []core.Signature
+	assert.NoError(t, json.Unmarshal([]byte(output), &rules))
+	ids := []string{}
+	for _, rule := range rules {
+		ids = append(ids, rule.ID)
+	}
+	assert.Contains(t, ids, "PY001")
+	assert.NotContains(t, ids, "JS001")
+
+	rulesFormat = "yaml"
+	assert.Error(t, runRulesList(rulesListCmd, nil))
+}
@@ -1,27 +1,91 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/re-movery/re-movery/internal/config"
 	"github.com/re-movery/re-movery/internal/core"
 	"github.com/re-movery/re-movery/internal/detectors"
+	"github.com/re-movery/re-movery/internal/notify"
 	"github.com/re-movery/re-movery/internal/reporters"
+	"github.com/re-movery/re-movery/internal/utils"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var (
-	scanFile       string
-	scanDir        string
-	excludePattern string
-	outputFile     string
-	reportFormat   string
-	parallel       bool
-	incremental    bool
-	confidence     float64
+	scanFile         string
+	scanDir          string
+	scanArchive      string
+	scanRepo         string
+	repoRef          string
+	excludePattern   string
+	includePattern   string
+	outputFile       string
+	reportFormat     string
+	parallel         bool
+	incremental      bool
+	confidence       float64
+	precision        float64
+	cacheFile        string
+	annotateDir      string
+	badgeFile        string
+	failOn           string
+	minCoverage      float64
+	chunkSizeMB      int
+	maxFileSizeMB    int
+	entropyThreshold float64
+	maxHigh          int
+	maxMedium        int
+	maxLow           int
+	compareRange     string
+	outputTemplate   string
+	explainFindings  bool
+	crossFile        bool
+	gate             bool
+	githubPR         string
+	githubToken      string
+	disableRules     string
+	enableOnly       string
+	contextLines     int
+	workers          int
+	minSeverity      string
+	noColor          bool
+	quiet            bool
+	showProgress     bool
+	jsonSummary      bool
+	memoryLimitGB    float64
+	summaryOnly      bool
+	pythonAST        bool
+	baselineFile     string
+	baselineUpdate   bool
+	signaturesDir    string
+	htmlTemplateFile string
+	noDefaultSigs    bool
+	logFindings      bool
+	fileTimeout      time.Duration
+	ignoreFile       string
+	noRedact         bool
+	perFileReports   bool
+	perFileOutputDir string
+	includeClean     bool
+	maxFindings      int
+)
+
+// memoryCheckInterval is how often --memory checks memory usage, and memoryLimitTicks
+// the number of consecutive checks over the limit after which the scan is aborted
+const (
+	memoryCheckInterval = time.Second
+	memoryLimitTicks    = 3
 )
 
 var scanCmd = &cobra.Command{
@@ -30,137 +94,1028 @@ var scanCmd = &cobra.Command{
 	Long: `Scan files or directories for security vulnerabilities.
 Examples:
   re-movery scan --file path/to/file.py
+  re-movery scan --archive code-drop.zip --output report.json
+  re-movery scan --repo https://github.com/org/repo --ref main
   re-movery scan --dir path/to/directory --exclude "node_modules,*.min.js"
-  re-movery scan --dir path/to/directory --output report.html --format html`,
+  re-movery scan --dir path/to/directory --include "src/**/*.py,lib/**/*.js"
+  re-movery scan --dir path/to/directory --disable-rules JS004,PY005
+  re-movery scan --dir path/to/directory --ignore-file ci.removeryignore
+  re-movery scan --dir path/to/directory --output report.html --format html
+  re-movery scan --dir path/to/directory --output report.html --html-template branding.html.tmpl
+  re-movery scan --dir path/to/directory --output report.html,results.json,junit.xml --format ,,junit
+  re-movery scan --dir path/to/directory --output report.xml --min-severity medium
+  re-movery scan --dir path/to/directory --per-file-reports --output-dir reports/ --format json
+  re-movery scan --dir path/to/directory --annotate annotated/
+  re-movery scan --dir path/to/directory --cross-file
+  re-movery scan --dir path/to/directory --python-ast
+  re-movery scan --dir path/to/directory --signatures-dir rules/
+  re-movery scan --dir path/to/directory --signatures-dir rules/ --no-default-signatures
+  re-movery scan --dir path/to/directory --badge badge.json
+  re-movery scan --dir path/to/directory --baseline baseline.json --fail-on high
+  re-movery scan --dir path/to/directory --baseline baseline.json --baseline-update
+  re-movery scan --dir path/to/directory --fail-on high --min-coverage 95
+  re-movery scan --dir path/to/directory --fail-on high --gate
+  re-movery scan --dir path/to/directory --quiet --json-summary --fail-on high
+  re-movery scan --dir path/to/directory --summary-only --json-summary --badge badge.json
+  re-movery scan --dir path/to/directory --max-high 0 --max-medium 10
+  re-movery scan --dir path/to/repository --compare main..feature --fail-on high
+  re-movery scan --dir path/to/repository --github-pr owner/repo#123 --token $GITHUB_TOKEN
+  re-movery scan --dir path/to/directory --output-template '{{.FilePath}}:{{.LineNumber}} [{{.Signature.Severity}}] {{.Signature.ID}}'
+
+Exit codes:
+  0  scan completed and no threshold was exceeded
+  1  operational error
+  2  findings exceed the --fail-on threshold or a --max-high/--max-medium/--max-low budget
+  3  scan coverage is below --min-coverage`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Create scanner
-		scanner := core.NewScanner()
-		
-		// Register detectors
-		scanner.RegisterDetector(detectors.NewPythonDetector())
-		scanner.RegisterDetector(detectors.NewJavaScriptDetector())
-		
-		// Set scanner options
-		scanner.SetParallel(parallel)
-		scanner.SetIncremental(incremental)
-		scanner.SetConfidenceThreshold(confidence)
-		
-		// Parse exclude patterns
-		var excludePatterns []string
-		if excludePattern != "" {
-			excludePatterns = strings.Split(excludePattern, ",")
-			for i, pattern := range excludePatterns {
-				excludePatterns[i] = strings.TrimSpace(pattern)
+		if err := runScan(cmd, args); err != nil {
+			exit(err)
+		}
+	},
+}
+
+// runScan runs the scan command and returns an error whose exit code is given by exitCode
+func runScan(cmd *cobra.Command, args []string) error {
+	// Validate thresholds before doing any work
+	if failOn != "" && severityRank(failOn) == 0 {
+		return fmt.Errorf("invalid --fail-on severity: %s (expected high, medium or low)", failOn)
+	}
+	if minSeverity != "" && severityRank(minSeverity) == 0 {
+		return fmt.Errorf("invalid --min-severity: %s (expected high, medium or low)", minSeverity)
+	}
+	reports, err := reportOutputs(outputFile, reportFormat)
+	if err != nil {
+		return err
+	}
+	if htmlTemplateFile != "" {
+		for _, report := range reports {
+			if htmlReporter, ok := report.reporter.(*reporters.HTMLReporter); ok {
+				if err := htmlReporter.SetTemplate(htmlTemplateFile); err != nil {
+					return fmt.Errorf("invalid --html-template: %v", err)
+				}
 			}
 		}
-		
-		// Scan file or directory
-		var results map[string][]core.Match
-		var err error
-		
-		if scanFile != "" {
-			// Check if file exists
-			if _, err := os.Stat(scanFile); os.IsNotExist(err) {
-				fmt.Fprintf(os.Stderr, "Error: File does not exist: %s\n", scanFile)
-				os.Exit(1)
+	}
+	configFile, _ := cmd.Flags().GetString("config")
+	settings, err := scanSettings(cmd, configFile)
+	if err != nil {
+		return err
+	}
+	if precision < 0 || precision > 1 {
+		return fmt.Errorf("invalid --precision: %.2f (expected 0.0-1.0)", precision)
+	}
+	if minCoverage < 0 || minCoverage > 100 {
+		return fmt.Errorf("invalid --min-coverage: %.1f (expected 0-100)", minCoverage)
+	}
+
+	if jsonSummary && outputTemplate != "" {
+		return fmt.Errorf("--json-summary cannot be used with --output-template")
+	}
+	if summaryOnly {
+		if err := checkSummaryOnly(); err != nil {
+			return err
+		}
+	}
+
+	if repoRef != "" && scanRepo == "" {
+		return fmt.Errorf("--ref requires --repo")
+	}
+	if scanRepo != "" && (scanFile != "" || scanDir != "" || scanArchive != "" || compareRange != "" || annotateDir != "") {
+		return fmt.Errorf("--repo cannot be used with --file, --dir, --archive, --compare or --annotate")
+	}
+
+	if perFileReports && perFileOutputDir == "" {
+		return fmt.Errorf("--per-file-reports requires --output-dir")
+	}
+	if perFileOutputDir != "" && !perFileReports {
+		return fmt.Errorf("--output-dir requires --per-file-reports")
+	}
+	if includeClean && !perFileReports {
+		return fmt.Errorf("--include-clean requires --per-file-reports")
+	}
+	if perFileReports {
+		formats := splitPatterns(reportFormat)
+		if len(formats) > 1 {
+			return fmt.Errorf("--per-file-reports takes a single --format")
+		}
+		if len(formats) == 1 {
+			if _, err := newReporter(formats[0], ""); err != nil {
+				return err
 			}
-			
-			// Scan file
-			matches, err := scanner.ScanFile(scanFile)
+		}
+	}
+
+	if noDefaultSigs && signaturesDir == "" {
+		return fmt.Errorf("--no-default-signatures requires --signatures-dir")
+	}
+
+	// Load the accepted findings before doing any work
+	var knownFindings *baseline
+	if baselineUpdate && baselineFile == "" {
+		return fmt.Errorf("--baseline-update requires --baseline")
+	}
+	if baselineFile != "" {
+		knownFindings, err = loadBaseline(baselineFile)
+		if err != nil {
+			return fmt.Errorf("loading baseline: %v", err)
+		}
+	}
+
+	var templateReporter *reporters.TemplateReporter
+	if outputTemplate != "" {
+		var err error
+		templateReporter, err = reporters.NewTemplateReporter(outputTemplate)
+		if err != nil {
+			return fmt.Errorf("invalid --output-template: %v", err)
+		}
+	}
+
+	var reviewNotifier *notify.GitHubReviewNotifier
+	if githubPR != "" {
+		var err error
+		reviewNotifier, err = newReviewNotifier(githubPR, githubToken)
+		if err != nil {
+			return fmt.Errorf("invalid --github-pr: %v", err)
+		}
+	}
+
+	// Load processing and security defaults from the config file, if given. These
+	// sections are only read from JSON config files, and only the keys set in the file
+	// replace the flag defaults.
+	var cfg *config.Config
+	if configFile != "" && strings.EqualFold(filepath.Ext(configFile), ".json") {
+		var err error
+		cfg, err = config.LoadConfig(configFile)
+		if err != nil {
+			return fmt.Errorf("loading config file: %v", err)
+		}
+	}
+
+	chunkSize := chunkSizeMB
+	if cfg.IsSet("processing.chunk_size_mb") && !cmd.Flags().Changed("chunk-size-mb") {
+		chunkSize = cfg.Processing.ChunkSizeMB
+	}
+	if chunkSize < 0 {
+		return fmt.Errorf("invalid --chunk-size-mb: %d", chunkSize)
+	}
+
+	maxFileSize := maxFileSizeMB
+	if cfg.IsSet("security.max_file_size_mb") && !cmd.Flags().Changed("max-file-size-mb") {
+		maxFileSize = cfg.Security.MaxFileSizeMB
+	}
+	if maxFileSize < 0 {
+		return fmt.Errorf("invalid --max-file-size-mb: %d", maxFileSize)
+	}
+
+	numWorkers := workers
+	if cfg.IsSet("processing.num_workers") && !cmd.Flags().Changed("workers") {
+		numWorkers = cfg.Processing.NumWorkers
+	}
+	if numWorkers < 0 {
+		return fmt.Errorf("invalid --workers: %d", numWorkers)
+	}
+
+	numContextLines := contextLines
+	if cfg.IsSet("detector.context_lines") && !cmd.Flags().Changed("context-lines") {
+		numContextLines = cfg.Detector.ContextLines
+	}
+	if numContextLines < 0 {
+		return fmt.Errorf("invalid --context-lines: %d", numContextLines)
+	}
+
+	findingsLog := logFindings
+	if cfg.IsSet("logging.log_findings") && !cmd.Flags().Changed("log-findings") {
+		findingsLog = cfg.Logging.LogFindings
+	}
+	if findingsLog && summaryOnly {
+		return fmt.Errorf("--log-findings cannot be used with --summary-only: it needs the individual findings, which are not kept")
+	}
+
+	memoryLimit := memoryLimitGB
+	if cfg.IsSet("processing.max_memory_gb") && !cmd.Flags().Changed("memory") {
+		memoryLimit = cfg.Processing.MaxMemoryGB
+	}
+	if memoryLimit < 0 {
+		return fmt.Errorf("invalid --memory: %.2f", memoryLimit)
+	}
+
+	// Create scanner, without the built-in detectors if only custom signatures should run
+	scanner := core.NewScanner()
+	scanner.SetRedactSecrets(false)
+	if !noDefaultSigs {
+		registerDetectors(scanner, maxFileSize, entropyThreshold, pythonAST)
+	}
+
+	// Add the signatures of the organization's signature files
+	if signaturesDir != "" {
+		customDetector := detectors.NewCustomDetector()
+		if err := customDetector.LoadSignaturesDir(signaturesDir); err != nil {
+			return fmt.Errorf("loading --signatures-dir: %v", err)
+		}
+		scanner.RegisterDetector(customDetector)
+	}
+
+	// Set scanner options
+	if err := settings.ApplyToScanner(scanner); err != nil {
+		return err
+	}
+	scanner.SetPrecision(precision)
+	scanner.SetChunkSize(int64(chunkSize) * 1024 * 1024)
+	scanner.SetMaxArchiveEntrySize(int64(maxFileSize) * 1024 * 1024)
+	scanner.SetCrossFile(crossFile)
+	scanner.SetContextLines(numContextLines)
+	scanner.SetWorkers(numWorkers)
+	scanner.SetSummaryOnly(summaryOnly)
+	scanner.SetKeepCleanFiles(includeClean)
+
+	// Use the given ignore file instead of the .removeryignore in the scan root
+	if ignoreFile != "" {
+		ignoreRules, err := core.LoadIgnoreFile(ignoreFile)
+		if err != nil {
+			return fmt.Errorf("loading ignore file: %v", err)
+		}
+		scanner.SetIgnoreRules(ignoreRules)
+	}
+
+	// Load the incremental cache from previous runs
+	if cacheFile != "" {
+		scanner.SetIncremental(true)
+		if err := scanner.LoadCache(cacheFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Ignoring cache file: %v\n", err)
+		}
+	}
+
+	// Show the progress of directory scans on stderr, keeping stdout for the results
+	progress := &progressIndicator{w: os.Stderr}
+	drawProgress := showProgress
+	if cfg.IsSet("logging.show_progress") && !cmd.Flags().Changed("progress") {
+		drawProgress = cfg.Logging.ShowProgress
+	}
+	if drawProgress && !quiet {
+		scanner.SetProgressFunc(progress.update)
+	}
+
+	// Abort directory scans that keep memory usage above the limit instead of
+	// running until the process is killed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	memoryErr := make(chan error, 1)
+	if memoryLimit > 0 {
+		utils.GetLogger().SetOutput(os.Stderr)
+		monitor := utils.NewMemoryMonitor(memoryLimit, memoryCheckInterval)
+		monitor.SetLimitFunc(memoryLimitTicks, func(usedGB float64) {
+			memoryErr <- fmt.Errorf("scanner memory usage (%.2f GB) exceeded the --memory limit of %.2f GB, scan aborted", usedGB, memoryLimit)
+			cancel()
+		})
+		monitor.Start()
+		defer monitor.Stop()
+	}
+
+	// Log the findings as JSON to logging.file, or to stderr so that they are not
+	// mixed with the findings printed on stdout
+	if findingsLog {
+		var w io.Writer = os.Stderr
+		if cfg != nil && cfg.Logging.File != "" {
+			file, err := utils.OpenLogFile(cfg.Logging.File)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error scanning file: %v\n", err)
-				os.Exit(1)
+				return fmt.Errorf("opening log file: %v", err)
+			}
+			defer file.Close()
+			w = file
+		}
+		utils.SetJSONOutput(w)
+	}
+
+	// Parse include and exclude patterns
+	excludePatterns := settings.Scanner.ExcludePatterns
+	scanner.SetIncludePatterns(splitPatterns(includePattern))
+
+	// Scan file or directory
+	var results map[string][]core.Match
+	coverage := 100.0
+	startTime := time.Now()
+
+	if compareRange != "" {
+		// Scan both refs and keep only the findings introduced by head
+		if scanFile != "" || scanArchive != "" || annotateDir != "" {
+			return fmt.Errorf("--compare cannot be used with --file, --archive or --annotate")
+		}
+
+		repoDir := scanDir
+		if repoDir == "" {
+			repoDir = "."
+		}
+
+		results, coverage, err = compareRefs(scanner, repoDir, compareRange, excludePatterns)
+		progress.finish()
+		if err != nil {
+			return fmt.Errorf("comparing refs: %v", err)
+		}
+	} else if scanFile != "" {
+		// Check if file exists
+		if _, err := os.Stat(scanFile); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", scanFile)
+		}
+
+		// Scan file
+		matches, err := scanner.ScanFile(scanFile)
+		if err != nil {
+			return fmt.Errorf("scanning file: %v", err)
+		}
+
+		results = map[string][]core.Match{
+			scanFile: matches,
+		}
+	} else if scanArchive != "" {
+		// The extracted files are removed after the scan
+		if annotateDir != "" {
+			return fmt.Errorf("--annotate cannot be used with --archive")
+		}
+
+		// Check if archive exists
+		if _, err := os.Stat(scanArchive); os.IsNotExist(err) {
+			return fmt.Errorf("archive does not exist: %s", scanArchive)
+		}
+
+		// Scan archive
+		results, err = scanner.ScanArchive(scanArchive)
+		progress.finish()
+		if err != nil {
+			return fmt.Errorf("scanning archive: %v", err)
+		}
+		coverage = scanner.LastScanStats().Coverage()
+	} else if scanRepo != "" {
+		// The clone is removed after the scan
+		results, err = scanRepository(ctx, scanner, scanRepo, repoRef, excludePatterns)
+		progress.finish()
+		if err != nil {
+			select {
+			case err := <-memoryErr:
+				return err
+			default:
 			}
-			
-			results = map[string][]core.Match{
-				scanFile: matches,
+			return fmt.Errorf("scanning repository: %v", err)
+		}
+		coverage = scanner.LastScanStats().Coverage()
+	} else if scanDir != "" {
+		// Check if directory exists
+		if _, err := os.Stat(scanDir); os.IsNotExist(err) {
+			return fmt.Errorf("directory does not exist: %s", scanDir)
+		}
+
+		// Scan directory
+		results, err = scanner.ScanDirectoryContext(ctx, scanDir, excludePatterns)
+		progress.finish()
+		if err != nil {
+			select {
+			case err := <-memoryErr:
+				return err
+			default:
+			}
+			return fmt.Errorf("scanning directory: %v", err)
+		}
+		coverage = scanner.LastScanStats().Coverage()
+	} else {
+		cmd.Help()
+		return fmt.Errorf("please specify a file or directory to scan")
+	}
+
+	// Per-file reports are also written for the files without findings on request;
+	// the rest of the scan only sees the files with findings
+	var cleanFiles []string
+	if perFileReports {
+		cleanFiles = splitCleanFiles(results)
+		if !includeClean {
+			cleanFiles = nil
+		}
+	}
+
+	// Persist the incremental cache for the next run
+	if cacheFile != "" {
+		if err := scanner.SaveCache(cacheFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save cache file: %v\n", err)
+		}
+	}
+
+	// Record the findings as accepted if requested, and leave accepted findings out
+	var baselineAdded, baselineRemoved, baselineSuppressed int
+	if knownFindings != nil {
+		// Archive, repository and compare results are already relative to their root
+		baseDir := scanDir
+		if scanFile != "" {
+			baseDir = filepath.Dir(scanFile)
+		} else if scanArchive != "" || scanRepo != "" || compareRange != "" {
+			baseDir = ""
+		}
+
+		if baselineUpdate {
+			baselineAdded, baselineRemoved = knownFindings.update(results, baseDir)
+			if err := knownFindings.save(baselineFile); err != nil {
+				return fmt.Errorf("writing baseline: %v", err)
 			}
-		} else if scanDir != "" {
-			// Check if directory exists
-			if _, err := os.Stat(scanDir); os.IsNotExist(err) {
-				fmt.Fprintf(os.Stderr, "Error: Directory does not exist: %s\n", scanDir)
-				os.Exit(1)
+		}
+		baselineSuppressed = knownFindings.suppress(results, baseDir)
+	}
+
+	// Keep secrets out of the reports and CI logs; baselines are matched on the
+	// unredacted code above, so the scanner does not redact them itself
+	if !noRedact {
+		core.RedactSecrets(results)
+	}
+
+	if findingsLog {
+		logResults(results)
+	}
+
+	duration := time.Since(startTime)
+
+	// Explanations are only reported on request
+	if !explainFindings {
+		stripExplanations(results)
+	}
+
+	// Generate summary; summary-only directory and archive scans return no results
+	summary := core.GenerateSummary(results)
+	if summaryOnly && scanFile == "" {
+		summary = scanner.LastSummary()
+	}
+
+	// Print one line per match if a template is given, the summary as JSON if requested,
+	// otherwise the summary, preceded by the findings themselves if no report file is written
+	if templateReporter != nil {
+		if err := templateReporter.Render(os.Stdout, results); err != nil {
+			return fmt.Errorf("rendering --output-template: %v", err)
+		}
+	} else if jsonSummary {
+		output, err := json.Marshal(summary)
+		if err != nil {
+			return fmt.Errorf("encoding summary: %v", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		progressf("Scan completed in %s\n", time.Now().Format(time.RFC3339))
+		progressf("Files scanned: %d\n", summary.TotalFiles)
+		if outputFile == "" && !summaryOnly {
+			// The findings are the results of the scan, so --quiet still prints them
+			consoleReporter := reporters.NewConsoleReporter()
+			if noColor {
+				consoleReporter.SetColor(false)
 			}
-			
-			// Scan directory
-			results, err = scanner.ScanDirectory(scanDir, excludePatterns)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error scanning directory: %v\n", err)
-				os.Exit(1)
+			if err := consoleReporter.GenerateReport(core.ReportData{Results: results, Summary: summary}, ""); err != nil {
+				return fmt.Errorf("printing findings: %v", err)
 			}
 		} else {
-			fmt.Fprintf(os.Stderr, "Error: Please specify a file or directory to scan\n")
-			cmd.Help()
-			os.Exit(1)
-		}
-		
-		// Generate summary
-		summary := core.GenerateSummary(results)
-		
-		// Print summary to console
-		fmt.Printf("Scan completed in %s\n", time.Now().Format(time.RFC3339))
-		fmt.Printf("Files scanned: %d\n", summary.TotalFiles)
-		fmt.Printf("Issues found: %d (High: %d, Medium: %d, Low: %d)\n",
-			summary.High+summary.Medium+summary.Low, summary.High, summary.Medium, summary.Low)
-		
-		// Generate report if output file is specified
-		if outputFile != "" {
-			// Create report data
-			reportData := core.ReportData{
-				Title:     "Re-movery Security Scan Report",
-				Timestamp: time.Now().Format(time.RFC3339),
-				Results:   results,
-				Summary:   summary,
+			progressf("Issues found: %d (High: %d, Medium: %d, Low: %d)\n",
+				summary.Total(), summary.High, summary.Medium, summary.Low)
+		}
+	}
+
+	if templateReporter == nil && !jsonSummary && !quiet {
+		if explainFindings {
+			printExplanations(results)
+		}
+
+		// List the files that could not be scanned once more, as the warnings about
+		// them scroll by during the scan
+		if scanErrors := scanner.LastScanErrors(); len(scanErrors) > 0 {
+			fmt.Fprintf(os.Stderr, "Files not scanned: %d\n", len(scanErrors))
+			for _, scanErr := range scanErrors {
+				fmt.Fprintf(os.Stderr, "  %v\n", scanErr)
 			}
-			
-			// Determine report format
-			if reportFormat == "" {
-				// Try to determine format from file extension
-				ext := strings.ToLower(filepath.Ext(outputFile))
-				switch ext {
-				case ".html":
-					reportFormat = "html"
-				case ".json":
-					reportFormat = "json"
-				case ".xml":
-					reportFormat = "xml"
-				default:
-					reportFormat = "html" // Default to HTML
-				}
+		}
+	}
+
+	// Generate the reports if output files are specified
+	if len(reports) > 0 {
+		// Create report data
+		reportData := core.ReportData{
+			Title:     "Re-movery Security Scan Report",
+			Timestamp: time.Now().Format(time.RFC3339),
+			Results:   results,
+			Summary:   summary,
+			Duration:  duration.Seconds(),
+		}
+
+		// Generate every report from the same results
+		for _, report := range reports {
+			if err := report.reporter.GenerateReport(reportData, report.path); err != nil {
+				return fmt.Errorf("generating report %s: %v", report.path, err)
 			}
-			
-			// Generate report
-			var reporter core.Reporter
-			switch strings.ToLower(reportFormat) {
-			case "html":
-				reporter = reporters.NewHTMLReporter()
-			case "json":
-				reporter = reporters.NewJSONReporter()
-			case "xml":
-				reporter = reporters.NewXMLReporter()
-			default:
-				fmt.Fprintf(os.Stderr, "Error: Unsupported report format: %s\n", reportFormat)
-				os.Exit(1)
+
+			progressf("Report generated: %s\n", report.path)
+		}
+	}
+
+	// Write one report per scanned file and an index of the reports if requested
+	if perFileReports {
+		// Archive, repository and compare results are already relative to their root
+		baseDir := scanDir
+		if scanFile != "" {
+			baseDir = filepath.Dir(scanFile)
+		} else if scanArchive != "" || scanRepo != "" || compareRange != "" {
+			baseDir = ""
+		}
+
+		format := ""
+		if formats := splitPatterns(reportFormat); len(formats) == 1 {
+			format = formats[0]
+		}
+		reportData := core.ReportData{
+			Title:     "Re-movery Security Scan Report",
+			Timestamp: time.Now().Format(time.RFC3339),
+			Results:   results,
+			Summary:   summary,
+			Duration:  duration.Seconds(),
+		}
+		written, err := writePerFileReports(reportData, cleanFiles, baseDir, perFileOutputDir, format)
+		if err != nil {
+			return fmt.Errorf("writing per-file reports: %v", err)
+		}
+
+		progressf("Per-file reports written: %d (in %s, index %s)\n", written, perFileOutputDir, filepath.Join(perFileOutputDir, perFileIndexName))
+	}
+
+	// Write the findings count badge if requested
+	if badgeFile != "" {
+		if err := reporters.NewBadgeReporter().GenerateReport(core.ReportData{Summary: summary}, badgeFile); err != nil {
+			return fmt.Errorf("generating badge: %v", err)
+		}
+
+		progressf("Badge generated: %s\n", badgeFile)
+	}
+
+	if baselineUpdate {
+		progressf("Baseline updated: %s (%d findings, %d added, %d removed)\n", baselineFile, len(knownFindings.Findings), baselineAdded, baselineRemoved)
+	} else if baselineSuppressed > 0 {
+		progressf("Findings accepted in baseline: %d (not reported)\n", baselineSuppressed)
+	}
+
+	// Write annotated copies of flagged files if requested
+	if annotateDir != "" {
+		baseDir := scanDir
+		if scanFile != "" {
+			baseDir = filepath.Dir(scanFile)
+		}
+
+		written, err := reporters.NewAnnotator().Annotate(results, baseDir, annotateDir)
+		if err != nil {
+			return fmt.Errorf("writing annotated files: %v", err)
+		}
+
+		progressf("Annotated files written: %d (in %s)\n", len(written), annotateDir)
+	}
+
+	// Post findings on the changed lines of the pull request as a review
+	if reviewNotifier != nil {
+		// Compare results are already relative to the repository root
+		baseDir := scanDir
+		if compareRange != "" || baseDir == "" {
+			baseDir = "."
+		}
+
+		if err := reviewNotifier.Notify(reviewMatches(results, baseDir)); err != nil {
+			return fmt.Errorf("posting GitHub review: %v", err)
+		}
+
+		progressf("Review posted: %s\n", githubPR)
+	}
+
+	// Apply thresholds only after all outputs have been written
+	err = checkThresholds(summary, coverage)
+	if gate {
+		fmt.Println(gateBanner(err))
+	}
+	return err
+}
+
+// scanSettings returns the scanner settings of the scan. They are loaded from the
+// scanner section of the config file, if given, and the REMOVERY_SCANNER_* environment
+// variables; only flags that are set on the command line override them.
+func scanSettings(cmd *cobra.Command, configFile string) (*core.Config, error) {
+	settings, err := core.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %v", err)
+	}
+
+	fromFlag := cmd.Flags().Changed
+	if fromFlag("parallel") {
+		settings.Scanner.Parallel = parallel
+	}
+	if fromFlag("incremental") {
+		settings.Scanner.Incremental = incremental
+	}
+	if fromFlag("confidence") {
+		settings.Scanner.ConfidenceThreshold = confidence
+	}
+	if fromFlag("exclude") {
+		settings.Scanner.ExcludePatterns = splitPatterns(excludePattern)
+	}
+	if fromFlag("min-severity") {
+		settings.Scanner.MinSeverity = minSeverity
+	}
+	if fromFlag("file-timeout") {
+		settings.Scanner.FileTimeout = fileTimeout.String()
+	}
+	if fromFlag("max-findings-per-file") {
+		settings.Scanner.MaxFindingsPerFile = maxFindings
+	}
+	if fromFlag("disable-rules") || fromFlag("enable-only") {
+		settings.Scanner.DisabledRules = splitPatterns(disableRules)
+		settings.Scanner.EnableOnly = splitPatterns(enableOnly)
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	if len(settings.Scanner.DisabledRules) > 0 && len(settings.Scanner.EnableOnly) > 0 {
+		return nil, fmt.Errorf("--disable-rules and --enable-only cannot be used together")
+	}
+	return settings, nil
+}
+
+// checkSummaryOnly returns an error if --summary-only is combined with an output that
+// needs the individual findings, which summary-only scans do not keep
+func checkSummaryOnly() error {
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--output", outputFile != ""},
+		{"--output-template", outputTemplate != ""},
+		{"--annotate", annotateDir != ""},
+		{"--per-file-reports", perFileReports},
+		{"--explain-findings", explainFindings},
+		{"--cross-file", crossFile},
+		{"--compare", compareRange != ""},
+		{"--github-pr", githubPR != ""},
+		{"--baseline", baselineFile != ""},
+	}
+	for _, conflict := range conflicts {
+		if conflict.set {
+			return fmt.Errorf("%s cannot be used with --summary-only: it needs the individual findings, which are not kept", conflict.flag)
+		}
+	}
+	return nil
+}
+
+// registerDetectors registers the detectors of all supported languages with a scanner
+func registerDetectors(scanner *core.Scanner, maxFileSize int, entropyThreshold float64, pythonAST bool) {
+	pythonDetector := detectors.NewPythonDetector()
+	pythonDetector.SetMaxFileSizeMB(maxFileSize)
+	pythonDetector.SetASTAnalysis(pythonAST)
+	scanner.RegisterDetector(pythonDetector)
+
+	javascriptDetector := detectors.NewJavaScriptDetector()
+	javascriptDetector.SetMaxFileSizeMB(maxFileSize)
+	scanner.RegisterDetector(javascriptDetector)
+
+	scanner.RegisterDetector(detectors.NewGoDetector())
+	scanner.RegisterDetector(detectors.NewHTMLDetector())
+	scanner.RegisterDetector(detectors.NewDockerfileDetector())
+	scanner.RegisterDetector(detectors.NewCDetector())
+	scanner.RegisterDetector(detectors.NewKotlinDetector())
+	scanner.RegisterDetector(detectors.NewSQLDetector())
+	scanner.RegisterDetector(detectors.NewTLSDetector())
+
+	secretsDetector := detectors.NewSecretsDetector()
+	secretsDetector.SetEntropyThreshold(entropyThreshold)
+	scanner.RegisterDetector(secretsDetector)
+}
+
+// reportOutput is a report file written by the scan command and its reporter
+type reportOutput struct {
+	path     string
+	reporter core.Reporter
+}
+
+// reportOutputs returns the reports for the comma-separated files of --output and
+// formats of --format. A single format applies to every file; otherwise there must be
+// one format per file, and files with an empty format get it from their extension.
+func reportOutputs(outputs string, formats string) ([]reportOutput, error) {
+	paths := splitPatterns(outputs)
+	formatList := splitPatterns(formats)
+	if len(formatList) > 1 && len(formatList) != len(paths) {
+		return nil, fmt.Errorf("--format lists %d formats for %d --output files", len(formatList), len(paths))
+	}
+
+	reports := []reportOutput{}
+	for i, path := range paths {
+		if path == "" {
+			return nil, fmt.Errorf("invalid --output: empty file name in %q", outputs)
+		}
+
+		format := ""
+		if len(formatList) == 1 {
+			format = formatList[0]
+		} else if len(formatList) > 1 {
+			format = formatList[i]
+		}
+
+		reporter, err := newReporter(format, path)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, reportOutput{path: path, reporter: reporter})
+	}
+	return reports, nil
+}
+
+// newReporter returns the reporter for a report format. Without a format, it is
+// determined from the extension of the output file, defaulting to HTML.
+func newReporter(format string, outputFile string) (core.Reporter, error) {
+	if format == "" {
+		// Try to determine format from file extension
+		ext := strings.ToLower(filepath.Ext(outputFile))
+		switch ext {
+		case ".html":
+			format = "html"
+		case ".json":
+			format = "json"
+		case ".xml":
+			format = "xml"
+		case ".csv":
+			format = "csv"
+		case ".ndjson", ".jsonl":
+			format = "ndjson"
+		case ".txt":
+			format = "text"
+		default:
+			format = "html" // Default to HTML
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case "html":
+		return reporters.NewHTMLReporter(), nil
+	case "json":
+		return reporters.NewJSONReporter(), nil
+	case "xml":
+		return reporters.NewXMLReporter(), nil
+	case "csv":
+		return reporters.NewCSVReporter(), nil
+	case "junit":
+		return reporters.NewJUnitReporter(), nil
+	case "ndjson":
+		return reporters.NewNDJSONReporter(), nil
+	case "gitlab":
+		return reporters.NewGitLabSASTReporter(), nil
+	case "text":
+		return reporters.NewConsoleReporter(), nil
+	default:
+		return nil, fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// progressIndicator renders the progress of directory scans on a single line
+type progressIndicator struct {
+	mutex   sync.Mutex
+	w       io.Writer
+	printed bool
+	scanned int
+}
+
+// update redraws the progress line; it is used as the scanner's progress function.
+// Parallel workers call it concurrently, so progress older than the line already
+// drawn is skipped.
+func (p *progressIndicator) update(scanned, total int, currentFile string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if scanned <= p.scanned {
+		return
+	}
+	fmt.Fprintf(p.w, "\rScanning: %d/%d files", scanned, total)
+	p.printed = true
+	p.scanned = scanned
+}
+
+// finish ends the progress line, if one was drawn
+func (p *progressIndicator) finish() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.scanned = 0
+	if p.printed {
+		fmt.Fprintln(p.w)
+		p.printed = false
+	}
+}
+
+// progressf prints a progress message to stdout unless --quiet is set
+func progressf(format string, args ...interface{}) {
+	if !quiet {
+		fmt.Printf(format, args...)
+	}
+}
+
+// gateBanner returns the final pass/fail line printed by --gate for the threshold check result
+func gateBanner(err error) string {
+	if err == nil {
+		return "SCAN PASSED"
+	}
+	return "SCAN FAILED: " + err.Error()
+}
+
+// logResults writes one log entry per finding, ordered by file path and line number
+func logResults(results map[string][]core.Match) {
+	logger := utils.GetLogger()
+	for _, result := range core.SortedResults(results) {
+		for _, match := range result.Matches {
+			logger.WithFields(logrus.Fields{
+				"rule_id":    match.Signature.ID,
+				"severity":   core.NormalizeSeverity(match.Signature.Severity),
+				"file":       result.FilePath,
+				"line":       match.LineNumber,
+				"confidence": match.Confidence,
+			}).Info("finding")
+		}
+	}
+}
+
+// stripExplanations removes the explanations recorded by the detectors from all matches
+func stripExplanations(results map[string][]core.Match) {
+	for _, matches := range results {
+		for i := range matches {
+			matches[i].Explanation = nil
+		}
+	}
+}
+
+// printExplanations prints why each match was reported, ordered by file path and line number
+func printExplanations(results map[string][]core.Match) {
+	filePaths := make([]string, 0, len(results))
+	for filePath := range results {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	for _, filePath := range filePaths {
+		matches := append([]core.Match{}, results[filePath]...)
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].LineNumber < matches[j].LineNumber
+		})
+
+		for _, match := range matches {
+			fmt.Printf("\n%s:%d %s %s (confidence %.2f)\n", filePath, match.LineNumber, match.Signature.ID, match.Signature.Name, match.Confidence)
+			if match.Explanation == nil {
+				fmt.Println("  no explanation recorded")
+				continue
 			}
-			
-			if err := reporter.GenerateReport(reportData, outputFile); err != nil {
-				fmt.Fprintf(os.Stderr, "Error generating report: %v\n", err)
-				os.Exit(1)
+			fmt.Printf("  pattern: %s\n", match.Explanation.Pattern)
+			for _, factor := range match.Explanation.Factors {
+				fmt.Printf("  %+.2f %s\n", factor.Value, factor.Reason)
 			}
-			
-			fmt.Printf("Report generated: %s\n", outputFile)
 		}
-	},
+	}
+}
+
+// splitPatterns splits a comma separated list of glob patterns
+func splitPatterns(list string) []string {
+	if list == "" {
+		return nil
+	}
+
+	patterns := strings.Split(list, ",")
+	for i, pattern := range patterns {
+		patterns[i] = strings.TrimSpace(pattern)
+	}
+	return patterns
+}
+
+// checkThresholds returns a findingsError or coverageError when the scan result exceeds the configured thresholds
+func checkThresholds(summary core.Summary, coverage float64) error {
+	if err := checkFailOn(summary, failOn); err != nil {
+		return err
+	}
+
+	// Per-severity budgets allow a limited number of findings
+	budgets := []struct {
+		severity string
+		count    int
+		max      int
+	}{
+		{"high", summary.High, maxHigh},
+		{"medium", summary.Medium, maxMedium},
+		{"low", summary.Low, maxLow},
+	}
+	for _, budget := range budgets {
+		if budget.max >= 0 && budget.count > budget.max {
+			return &findingsError{
+				message: fmt.Sprintf("%d %s severity findings exceed the budget of %d", budget.count, budget.severity, budget.max),
+			}
+		}
+	}
+
+	if minCoverage > 0 && coverage < minCoverage {
+		return &coverageError{coverage: coverage, minCoverage: minCoverage}
+	}
+
+	return nil
+}
+
+// checkFailOn returns a findingsError when the summary has findings at or above the
+// given severity. No severity disables the check.
+func checkFailOn(summary core.Summary, failOn string) error {
+	if failOn == "" {
+		return nil
+	}
+	if count := countAtOrAbove(summary, failOn); count > 0 {
+		severity := strings.ToLower(failOn)
+		if severityRank(severity) < 3 {
+			severity += "-or-higher"
+		}
+		return &findingsError{
+			message: fmt.Sprintf("%d %s-severity findings exceed threshold", count, severity),
+		}
+	}
+	return nil
+}
+
+// severityRank returns the rank of a severity level (high=3, medium=2, low=1, unknown=0)
+func severityRank(severity string) int {
+	switch core.NormalizeSeverity(severity) {
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// countAtOrAbove counts the findings in a summary at or above the given severity
+func countAtOrAbove(summary core.Summary, severity string) int {
+	count := 0
+	rank := severityRank(severity)
+	if rank <= 3 {
+		count += summary.High
+	}
+	if rank <= 2 {
+		count += summary.Medium
+	}
+	if rank <= 1 {
+		count += summary.Low
+	}
+	return count
 }
 
 func init() {
 	// Add flags
 	scanCmd.Flags().StringVar(&scanFile, "file", "", "File to scan")
 	scanCmd.Flags().StringVar(&scanDir, "dir", "", "Directory to scan")
-	scanCmd.Flags().StringVar(&excludePattern, "exclude", "", "Patterns to exclude (comma separated)")
-	scanCmd.Flags().StringVar(&outputFile, "output", "", "Output file for the report")
-	scanCmd.Flags().StringVar(&reportFormat, "format", "", "Report format (html, json, xml)")
-	scanCmd.Flags().BoolVar(&parallel, "parallel", false, "Enable parallel processing")
-	scanCmd.Flags().BoolVar(&incremental, "incremental", false, "Enable incremental scanning")
-	scanCmd.Flags().Float64Var(&confidence, "confidence", 0.7, "Confidence threshold (0.0-1.0)")
-} 
\ No newline at end of file
+	scanCmd.Flags().StringVar(&scanArchive, "archive", "", "Archive to scan (.zip, .tar.gz or .tgz); findings are reported by their path in the archive, and files larger than --max-file-size-mb are skipped")
+	scanCmd.Flags().StringVar(&scanRepo, "repo", "", "URL of a git repository to shallow-clone into a temporary directory and scan; findings are reported by their path in the repository (private repositories: set $"+repoTokenEnv+")")
+	scanCmd.Flags().StringVar(&repoRef, "ref", "", "Branch, tag or commit of --repo to scan (default: the default branch)")
+	scanCmd.Flags().StringVar(&excludePattern, "exclude", "", "Glob patterns to exclude, matched against paths relative to the scan root (comma separated, supports **, defaults to scanner.excludePatterns from --config)")
+	scanCmd.Flags().StringVar(&includePattern, "include", "", "Glob patterns of files to scan, matched against paths relative to the scan root (comma separated, supports **); files must also not match --exclude")
+	scanCmd.Flags().StringVar(&ignoreFile, "ignore-file", "", "Ignore file of path globs to skip and ruleId:pathGlob entries to suppress a rule in matching files, used instead of the .removeryignore file in the root of directory, archive and repository scans")
+	scanCmd.Flags().StringVar(&disableRules, "disable-rules", "", "Signature IDs whose findings are not reported (comma separated, e.g. JS004,PY005)")
+	scanCmd.Flags().StringVar(&enableOnly, "enable-only", "", "Only report findings of these signature IDs (comma separated, e.g. PY004,JS003); cannot be combined with --disable-rules")
+	scanCmd.Flags().StringVar(&outputFile, "output", "", "Output files for the reports (comma separated)")
+	scanCmd.Flags().BoolVar(&perFileReports, "per-file-reports", false, "Write one report per scanned file to --output-dir, mirroring the directory structure, in the single --format (default html), plus an _index.json of the reports")
+	scanCmd.Flags().StringVar(&perFileOutputDir, "output-dir", "", "Directory for the reports of --per-file-reports")
+	scanCmd.Flags().BoolVar(&includeClean, "include-clean", false, "Also write per-file reports for files without findings")
+	scanCmd.Flags().StringVar(&outputTemplate, "output-template", "", "Print each finding to stdout with this Go text/template, applied per match, instead of the summary (e.g. '{{.FilePath}}:{{.LineNumber}} {{.Signature.ID}}')")
+	scanCmd.Flags().BoolVar(&noRedact, "no-redact", false, "Report the matched code of secret findings as is instead of replacing the secrets with ****, e.g. for local debugging")
+	scanCmd.Flags().BoolVar(&explainFindings, "explain-findings", false, "Include the pattern that matched and the confidence factors of each finding in the console and report output")
+	scanCmd.Flags().StringVar(&reportFormat, "format", "", "Report formats, one for all outputs or one per output; empty formats are inferred from the extension (html, json, xml, csv, junit, ndjson, gitlab, text)")
+	scanCmd.Flags().StringVar(&htmlTemplateFile, "html-template", "", "html/template file that replaces the built-in template of HTML reports")
+	scanCmd.Flags().StringVar(&minSeverity, "min-severity", "", "Drop findings below this severity (high, medium, low) while scanning, so they are not kept, reported or counted towards --fail-on and the --max-* budgets")
+	scanCmd.Flags().BoolVar(&quiet, "quiet", false, "Only print results: the findings if there is no --output, but not the summary lines and progress messages such as \"Report generated\"")
+	scanCmd.Flags().BoolVar(&showProgress, "progress", true, "Show the progress of directory scans on stderr (defaults to logging.show_progress from --config; not shown with --quiet)")
+	scanCmd.Flags().BoolVar(&jsonSummary, "json-summary", false, "Print the summary as a JSON object to stdout instead of the summary lines")
+	scanCmd.Flags().BoolVar(&summaryOnly, "summary-only", false, "Only count the findings of directory and archive scans instead of keeping them, to save memory on large repositories; cannot be used with outputs that need the findings, such as --output")
+	scanCmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colors in the findings printed to the console (colors are off when stdout is not a terminal)")
+	scanCmd.Flags().Float64Var(&memoryLimitGB, "memory", 0, "Abort directory scans when the scanner's own memory usage stays above this many GB (0 disables, defaults to processing.max_memory_gb from --config)")
+	scanCmd.Flags().BoolVar(&parallel, "parallel", false, "Enable parallel processing (defaults to scanner.parallel from --config)")
+	scanCmd.Flags().IntVar(&workers, "workers", 0, "Number of files scanned concurrently with --parallel (0 uses one per CPU, defaults to processing.num_workers from --config)")
+	scanCmd.Flags().BoolVar(&incremental, "incremental", false, "Enable incremental scanning (defaults to scanner.incremental from --config)")
+	scanCmd.Flags().BoolVar(&crossFile, "cross-file", false, "Annotate Go findings with the definitions of the functions called on their lines (slower)")
+	scanCmd.Flags().StringVar(&annotateDir, "annotate", "", "Write copies of flagged files with findings inserted as comments to this directory")
+	scanCmd.Flags().StringVar(&badgeFile, "badge", "", "Write a findings count badge to this file (.svg for an image, otherwise shields.io endpoint JSON)")
+	scanCmd.Flags().StringVar(&compareRange, "compare", "", "Scan two refs of the git repository given by --dir (default: current directory) and report only findings in head that are not in base (base..head)")
+	scanCmd.Flags().StringVar(&githubPR, "github-pr", "", "Post findings on lines changed by this pull request (owner/repo#number) as review comments; paths are taken relative to --dir, which must be the repository root")
+	scanCmd.Flags().StringVar(&githubToken, "token", "", "GitHub token for --github-pr (default: $GITHUB_TOKEN)")
+	scanCmd.Flags().StringVar(&baselineFile, "baseline", "", "JSON file of accepted findings, which are not reported or counted towards --fail-on and the --max-* budgets")
+	scanCmd.Flags().BoolVar(&baselineUpdate, "baseline-update", false, "Write all current findings to the --baseline file instead of reporting them, keeping the justification and metadata of findings already in it")
+	scanCmd.Flags().StringVar(&cacheFile, "cache-file", "", "File to persist the incremental scan cache between runs (implies --incremental)")
+	scanCmd.Flags().Float64Var(&confidence, "confidence", 0.7, "Confidence threshold (0.0-1.0, defaults to scanner.confidenceThreshold from --config)")
+	scanCmd.Flags().Float64Var(&precision, "precision", 0, "Raise the confidence threshold for lower-severity findings (0.0-1.0); high severity findings are least affected")
+	scanCmd.Flags().IntVar(&contextLines, "context-lines", 0, "Include this many source lines before and after each finding in the report (defaults to detector.context_lines from --config)")
+	scanCmd.Flags().IntVar(&chunkSizeMB, "chunk-size-mb", 0, "Split files larger than this many MB into chunks scanned in parallel (0 disables, defaults to processing.chunk_size_mb from --config)")
+	scanCmd.Flags().IntVar(&maxFileSizeMB, "max-file-size-mb", detectors.DefaultMaxFileSizeMB, "Skip Python and JavaScript files larger than this many MB with a warning (0 disables, defaults to security.max_file_size_mb from --config)")
+	scanCmd.Flags().BoolVar(&pythonAST, "python-ast", false, "Parse Python files with python3 to report only real calls, including calls through import aliases and shell commands (PY020), instead of every regex match (slower; falls back to regexes if parsing fails)")
+	scanCmd.Flags().DurationVar(&fileTimeout, "file-timeout", core.DefaultFileTimeout, "Abandon a file whose scan takes longer than this, with a warning, and continue with the next file; 0 disables the limit (defaults to scanner.fileTimeout from --config)")
+	scanCmd.Flags().IntVar(&maxFindings, "max-findings-per-file", 0, "Report at most this many findings per file, the most severe first, followed by a TRUNCATED finding with the number left out; 0 means unlimited (defaults to scanner.maxFindingsPerFile from --config)")
+	scanCmd.Flags().BoolVar(&logFindings, "log-findings", false, "Log each finding as a JSON entry with rule_id, severity, file, line and confidence, to logging.file from --config or to stderr (defaults to logging.log_findings from --config)")
+	scanCmd.Flags().BoolVar(&noDefaultSigs, "no-default-signatures", false, "Only scan with the signatures of --signatures-dir, without the built-in detectors")
+	scanCmd.Flags().StringVar(&signaturesDir, "signatures-dir", "", "Directory of JSON and YAML signature files to scan with in addition to the built-in signatures; a signature ID defined in an earlier file is a conflict unless the later signature sets override: true")
+	scanCmd.Flags().Float64Var(&entropyThreshold, "entropy-threshold", detectors.DefaultEntropyThreshold, "Shannon entropy in bits per character above which a string literal is reported as a secret (SEC001)")
+	scanCmd.Flags().StringVar(&failOn, "fail-on", "", "Exit with code 2 if any finding is at or above this severity (high, medium, low)")
+	scanCmd.Flags().BoolVar(&gate, "gate", false, "Print SCAN PASSED or SCAN FAILED with the reason as the last line, based on --fail-on, --max-* and --min-coverage")
+	scanCmd.Flags().IntVar(&maxHigh, "max-high", -1, "Exit with code 2 if there are more than this many high severity findings (-1 disables)")
+	scanCmd.Flags().IntVar(&maxMedium, "max-medium", -1, "Exit with code 2 if there are more than this many medium severity findings (-1 disables)")
+	scanCmd.Flags().IntVar(&maxLow, "max-low", -1, "Exit with code 2 if there are more than this many low severity findings (-1 disables)")
+	scanCmd.Flags().Float64Var(&minCoverage, "min-coverage", 0, "Exit with code 3 if fewer than this percentage of files could be scanned (0-100)")
+}
+ 
\ No newline at end of file
@@ -3,15 +3,21 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/re-movery/re-movery/internal/api"
+	"github.com/re-movery/re-movery/internal/config"
+	"github.com/re-movery/re-movery/internal/core"
+	"github.com/re-movery/re-movery/internal/notify"
 	"github.com/spf13/cobra"
 )
 
 var (
-	serverHost  string
-	serverPort  int
-	serverDebug bool
+	serverHost        string
+	serverPort        int
+	serverDebug       bool
+	serverWebhook     string
+	serverDedupWindow time.Duration
 )
 
 var serverCmd = &cobra.Command{
@@ -23,10 +29,44 @@ The API server provides a RESTful API for scanning files and directories for sec
 Examples:
   re-movery server
   re-movery server --host 0.0.0.0 --port 8081
-  re-movery server --debug`,
+  re-movery server --debug
+  re-movery server --webhook https://hooks.example.com/movery --dedup-window 24h
+
+When security.require_auth is set in the config file, or tokens are set in the
+REMOVERY_API_TOKENS environment variable, API requests must carry
+"Authorization: Bearer <token>" with one of security.api_tokens or of the
+comma-separated tokens in REMOVERY_API_TOKENS.
+Each client may make security.rate_limit_per_hour API requests per hour
+(default 1000).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Create API server
+		// Validate the settings before doing any work
+		settings, err := serverSettings(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Create API server with the scanner settings of the config file
 		server := api.NewServer()
+		if err := server.SetConfig(settings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Apply the authentication and rate limit settings of the config file
+		if err := configureAPIServer(cmd, server); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Send findings to the webhook, dropping those already sent within the dedup window
+		if serverWebhook != "" {
+			var notifier notify.Notifier = notify.NewWebhookNotifier(serverWebhook)
+			if serverDedupWindow > 0 {
+				notifier = notify.NewDedupNotifier(notifier, serverDedupWindow)
+			}
+			server.SetNotifier(notifier)
+		}
 		
 		// Start API server
 		addr := fmt.Sprintf("%s:%d", serverHost, serverPort)
@@ -39,9 +79,61 @@ Examples:
 	},
 }
 
+// serverSettings returns the settings the server runs with: the defaults, overridden
+// by the config file and the environment, with the address given by the flags
+func serverSettings(cmd *cobra.Command) (*core.Config, error) {
+	configFile, _ := cmd.Flags().GetString("config")
+	settings, err := core.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading config file: %v", err)
+	}
+	settings.Server.Host = serverHost
+	settings.Server.Port = serverPort
+	settings.Server.Debug = serverDebug
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// configureAPIServer applies the authentication and rate limit settings of the config file,
+// if given. Tokens in the REMOVERY_API_TOKENS environment variable enable authentication
+// even without security.require_auth, so that setting them never leaves the API open.
+func configureAPIServer(cmd *cobra.Command, server *api.Server) error {
+	envTokens := api.TokensFromEnv()
+	requireAuth := len(envTokens) > 0
+	var tokens []string
+
+	configFile, _ := cmd.Flags().GetString("config")
+	if configFile != "" {
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			return fmt.Errorf("loading config file: %v", err)
+		}
+		if cfg.Security.RequireAuth {
+			requireAuth = true
+			tokens = cfg.Security.APITokens
+		}
+		if cfg.Security.RateLimitPerHour > 0 {
+			server.SetRateLimit(cfg.Security.RateLimitPerHour)
+		}
+	}
+
+	if requireAuth {
+		tokens = append(tokens, envTokens...)
+		if len(tokens) == 0 {
+			return fmt.Errorf("security.require_auth is set but no API tokens are configured")
+		}
+		server.SetAuth(true, tokens)
+	}
+	return nil
+}
+
 func init() {
 	// Add flags
 	serverCmd.Flags().StringVar(&serverHost, "host", "localhost", "Host to bind the API server to")
 	serverCmd.Flags().IntVar(&serverPort, "port", 8081, "Port to bind the API server to")
 	serverCmd.Flags().BoolVar(&serverDebug, "debug", false, "Enable debug mode")
+	serverCmd.Flags().StringVar(&serverWebhook, "webhook", "", "Webhook URL to post scan findings to")
+	serverCmd.Flags().DurationVar(&serverDedupWindow, "dedup-window", time.Hour, "Do not re-send a finding to the webhook within this window (0 disables)")
 } 
\ No newline at end of file
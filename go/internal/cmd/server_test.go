@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/api"
+)
+
+// 测试仅在环境变量中设置令牌时也启用API认证
+func TestConfigureAPIServerEnvTokens(t *testing.T) {
+	// --config是根命令的持久参数
+	serverCmd.InheritedFlags()
+
+	// 未设置令牌时不启用认证
+	os.Unsetenv(api.TokensEnvVar)
+	server := api.NewServer()
+	assert.NoError(t, configureAPIServer(serverCmd, server))
+	assert.False(t, server.EffectiveConfig().Security.RequireAuth)
+
+	// 没有配置文件时环境变量中的令牌启用认证
+	os.Setenv(api.TokensEnvVar, "token1,token2")
+	defer os.Unsetenv(api.TokensEnvVar)
+	server = api.NewServer()
+	assert.NoError(t, configureAPIServer(serverCmd, server))
+	security := server.EffectiveConfig().Security
+	assert.True(t, security.RequireAuth)
+	assert.Len(t, security.APITokens, 2)
+
+	effective, err := effectiveConfig("")
+	assert.NoError(t, err)
+	assert.True(t, effective.Security.RequireAuth)
+	assert.Len(t, effective.Security.APITokens, 2)
+}
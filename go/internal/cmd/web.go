@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/re-movery/re-movery/internal/api"
+	"github.com/re-movery/re-movery/internal/core"
 	"github.com/re-movery/re-movery/internal/web"
 	"github.com/spf13/cobra"
 )
@@ -12,6 +14,7 @@ var (
 	webHost  string
 	webPort  int
 	webDebug bool
+	webAPI   bool
 )
 
 var webCmd = &cobra.Command{
@@ -23,10 +26,33 @@ The web interface provides a user-friendly way to scan files and directories for
 Examples:
   re-movery web
   re-movery web --host 0.0.0.0 --port 8080
-  re-movery web --debug`,
+  re-movery web --debug
+  re-movery web --with-api
+
+With --with-api the API is served under /api on the same port, e.g. for the VS Code
+extension, with the authentication and rate limit settings of the server command.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Create web app
-		app := web.NewApp()
+		// Validate the address before doing any work
+		settings := core.NewConfig()
+		settings.Web.Host = webHost
+		settings.Web.Port = webPort
+		if err := settings.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Create web app, with the API on the same engine if requested
+		var app *web.App
+		if webAPI {
+			var server *api.Server
+			app, server = web.NewAppWithAPI()
+			if err := configureAPIServer(cmd, server); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			app = web.NewApp()
+		}
 		
 		// Start web server
 		addr := fmt.Sprintf("%s:%d", webHost, webPort)
@@ -44,4 +70,5 @@ func init() {
 	webCmd.Flags().StringVar(&webHost, "host", "localhost", "Host to bind the web server to")
 	webCmd.Flags().IntVar(&webPort, "port", 8080, "Port to bind the web server to")
 	webCmd.Flags().BoolVar(&webDebug, "debug", false, "Enable debug mode")
+	webCmd.Flags().BoolVar(&webAPI, "with-api", false, "Also serve the API under /api on the same port")
 } 
\ No newline at end of file
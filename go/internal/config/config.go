@@ -10,6 +10,9 @@ type Config struct {
     Detector   DetectorConfig   `mapstructure:"detector"`
     Logging    LoggingConfig    `mapstructure:"logging"`
     Security   SecurityConfig   `mapstructure:"security"`
+
+    // keys are the keys set in the config file, such as "security.max_file_size_mb"
+    keys map[string]bool
 }
 
 // ProcessingConfig contains processing-related configuration
@@ -40,6 +43,7 @@ type LoggingConfig struct {
     Format          string `mapstructure:"format"`
     EnableProfiling bool   `mapstructure:"enable_profiling"`
     ShowProgress    bool   `mapstructure:"show_progress"`
+    LogFindings     bool   `mapstructure:"log_findings"`
 }
 
 // SecurityConfig contains security-related configuration
@@ -48,6 +52,7 @@ type SecurityConfig struct {
     AllowedSchemes    []string `mapstructure:"allowed_schemes"`
     EnableSandbox     bool     `mapstructure:"enable_sandbox"`
     RequireAuth       bool     `mapstructure:"require_auth"`
+    APITokens         []string `mapstructure:"api_tokens"`
     RateLimitPerHour  int      `mapstructure:"rate_limit_per_hour"`
 }
 
@@ -64,10 +69,20 @@ func LoadConfig(configFile string) (*Config, error) {
     if err := viper.Unmarshal(&config); err != nil {
         return nil, err
     }
+    config.keys = make(map[string]bool)
+    for _, key := range viper.AllKeys() {
+        config.keys[key] = true
+    }
 
     return &config, nil
 }
 
+// IsSet reports whether a key, such as "security.max_file_size_mb", is set in the
+// config file. Keys that are not set hold the zero value rather than a default.
+func (c *Config) IsSet(key string) bool {
+    return c != nil && c.keys[key]
+}
+
 // SetDefaults sets default configuration values
 func SetDefaults() {
     viper.SetDefault("processing.num_workers", 4)
@@ -88,6 +103,7 @@ func SetDefaults() {
     viper.SetDefault("logging.format", "text")
     viper.SetDefault("logging.enable_profiling", false)
     viper.SetDefault("logging.show_progress", true)
+    viper.SetDefault("logging.log_findings", false)
 
     viper.SetDefault("security.max_file_size_mb", 10)
     viper.SetDefault("security.enable_sandbox", true)
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxArchiveSize is the default limit in bytes on the total uncompressed size
+// of the files extracted from an archive
+const DefaultMaxArchiveSize = 1024 * 1024 * 1024
+
+// SetMaxArchiveEntrySize sets the size in bytes above which a file in an archive is
+// skipped with a warning instead of being extracted. A size of 0 disables the limit.
+func (s *Scanner) SetMaxArchiveEntrySize(size int64) {
+	s.maxArchiveEntrySize = size
+}
+
+// SetMaxArchiveSize sets the limit in bytes on the total uncompressed size of the
+// files extracted from an archive; archives that exceed it are not scanned.
+// A size of 0 disables the limit.
+func (s *Scanner) SetMaxArchiveSize(size int64) {
+	s.maxArchiveSize = size
+}
+
+// ScanArchive scans the supported files in a .zip, .tar.gz or .tgz archive. The files
+// are extracted to a temporary directory that is removed afterwards, and the results
+// are keyed by the slash-separated paths of the files in the archive.
+func (s *Scanner) ScanArchive(archivePath string) (map[string][]Match, error) {
+	tmpDir, err := ioutil.TempDir("", "re-movery-archive")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	extractor := &archiveExtractor{
+		scanner: s,
+		dir:     tmpDir,
+	}
+
+	name := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		err = extractor.extractZip(archivePath)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		err = extractor.extractTarGz(archivePath)
+	default:
+		return nil, fmt.Errorf("unsupported archive format: %s (expected .zip, .tar.gz or .tgz)", archivePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("extracting %s: %v", archivePath, err)
+	}
+
+	extracted, err := s.ScanDirectory(tmpDir, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Key the results by the paths in the archive
+	results := make(map[string][]Match, len(extracted))
+	for path, matches := range extracted {
+		rel, err := filepath.Rel(tmpDir, path)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		for i := range matches {
+			matches[i].FilePath = rel
+		}
+		results[rel] = matches
+	}
+
+	return results, nil
+}
+
+// archiveExtractor extracts the supported files of an archive into a directory,
+// enforcing the scanner's size limits
+type archiveExtractor struct {
+	scanner *Scanner
+	dir     string
+	total   int64
+}
+
+// extractZip extracts the supported files of a zip archive
+func (e *archiveExtractor) extractZip(archivePath string) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+
+		err := func() error {
+			r, err := file.Open()
+			if err != nil {
+				return err
+			}
+			defer r.Close()
+			return e.extract(file.Name, int64(file.UncompressedSize64), r)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractTarGz extracts the supported files of a gzip-compressed tar archive
+func (e *archiveExtractor) extractTarGz(archivePath string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if err := e.extract(header.Name, header.Size, reader); err != nil {
+			return err
+		}
+	}
+}
+
+// extract writes an archive entry to the directory if a detector supports it. Entries
+// with paths outside the directory are rejected, entries over the size limit skipped.
+// The sizes recorded in the archive are not trusted: at most one byte more than the
+// limits is read from r.
+func (e *archiveExtractor) extract(name string, size int64, r io.Reader) error {
+	target, err := archiveTarget(e.dir, name)
+	if err != nil {
+		return err
+	}
+	// Files whose language is inferred from their content are selected after extraction
+	if !e.scanner.SupportsFile(target) && !sniffedExtensions[strings.ToLower(filepath.Ext(target))] {
+		return nil
+	}
+
+	maxEntrySize := e.scanner.maxArchiveEntrySize
+	if maxEntrySize > 0 && size > maxEntrySize {
+		fmt.Fprintf(os.Stderr, "Warning: Skipping %s: size %d bytes exceeds the limit of %d bytes\n", name, size, maxEntrySize)
+		return nil
+	}
+
+	// Read no more than allowed by the remaining total or the entry limit
+	limit := int64(-1)
+	if maxSize := e.scanner.maxArchiveSize; maxSize > 0 {
+		limit = maxSize - e.total
+	}
+	if maxEntrySize > 0 && (limit < 0 || maxEntrySize < limit) {
+		limit = maxEntrySize
+	}
+	if limit >= 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(out, r)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	e.total += written
+	if maxSize := e.scanner.maxArchiveSize; maxSize > 0 && e.total > maxSize {
+		return fmt.Errorf("uncompressed size exceeds the limit of %d bytes", maxSize)
+	}
+	if maxEntrySize > 0 && written > maxEntrySize {
+		fmt.Fprintf(os.Stderr, "Warning: Skipping %s: size exceeds the limit of %d bytes\n", name, maxEntrySize)
+		e.total -= written
+		return os.Remove(target)
+	}
+	return nil
+}
+
+// archiveTarget returns the path in dir that an archive entry is extracted to, or an
+// error if the entry's path would leave dir (zip slip)
+func archiveTarget(dir string, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(strings.ReplaceAll(name, "\\", "/")))
+	if filepath.IsAbs(clean) || filepath.VolumeName(clean) != "" || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+
+	target := filepath.Join(dir, clean)
+	if !strings.HasPrefix(target, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+	return target, nil
+}
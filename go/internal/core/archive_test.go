@@ -0,0 +1,129 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 创建包含给定文件的zip压缩包
+func createZip(t *testing.T, dir string, files map[string]string) string {
+	path := filepath.Join(dir, "code.zip")
+	out, err := os.Create(path)
+	assert.NoError(t, err)
+	defer out.Close()
+
+	writer := zip.NewWriter(out)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	return path
+}
+
+// 创建包含给定文件的tar.gz压缩包
+func createTarGz(t *testing.T, dir string, files map[string]string) string {
+	path := filepath.Join(dir, "code.tar.gz")
+	out, err := os.Create(path)
+	assert.NoError(t, err)
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	writer := tar.NewWriter(gz)
+	for name, content := range files {
+		assert.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, gz.Close())
+	return path
+}
+
+// 测试扫描zip和tar.gz压缩包，结果以包内路径为键
+func TestScanArchive(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "archive-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	files := map[string]string{
+		"app/main.py": "eval(x)\n",
+		"README.md":   "# Readme\n",
+		"lib/util.py": "print(1)\n",
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+
+	for _, archive := range []string{createZip(t, tmpdir, files), createTarGz(t, tmpdir, files)} {
+		results, err := scanner.ScanArchive(archive)
+		assert.NoError(t, err, archive)
+		assert.Len(t, results, 2, archive)
+		if assert.Len(t, results["app/main.py"], 1, archive) {
+			assert.Equal(t, "app/main.py", results["app/main.py"][0].FilePath)
+		}
+		assert.Contains(t, results, "lib/util.py", archive)
+	}
+
+	_, err = scanner.ScanArchive(filepath.Join(tmpdir, "code.rar"))
+	assert.Error(t, err)
+}
+
+// 测试拒绝路径指向解压目录之外的条目
+func TestScanArchiveZipSlip(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "archive-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+
+	for _, name := range []string{"../evil.py", "app/../../evil.py", "/tmp/evil.py"} {
+		archive := createZip(t, tmpdir, map[string]string{name: "eval(x)\n"})
+		_, err := scanner.ScanArchive(archive)
+		if assert.Error(t, err, name) {
+			assert.Contains(t, err.Error(), "illegal path in archive")
+		}
+	}
+	_, err = os.Stat(filepath.Join(os.TempDir(), "evil.py"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+// 测试单个条目大小和解压总大小的限制
+func TestScanArchiveSizeLimits(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "archive-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	archive := createTarGz(t, tmpdir, map[string]string{
+		"small.py": "eval(x)\n",
+		"large.py": strings.Repeat("x = 1\n", 100),
+	})
+
+	// 超过单个条目大小限制的文件被跳过
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	scanner.SetMaxArchiveEntrySize(100)
+	results, err := scanner.ScanArchive(archive)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Contains(t, results, "small.py")
+
+	// 解压总大小超过限制时不扫描压缩包
+	scanner.SetMaxArchiveEntrySize(0)
+	scanner.SetMaxArchiveSize(300)
+	_, err = scanner.ScanArchive(archive)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "exceeds the limit of 300 bytes")
+	}
+}
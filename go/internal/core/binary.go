@@ -0,0 +1,29 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// binarySniffSize is the number of bytes at the start of a file that are checked for
+// null bytes, as git does to tell binary files from text
+const binarySniffSize = 8000
+
+// isBinaryFile reports whether a file is binary, i.e. has a null byte in its first
+// binarySniffSize bytes. Source code never has one, whatever its extension, while
+// images, archives and compiled files almost always do.
+func isBinaryFile(filePath string) (bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	buffer := make([]byte, binarySniffSize)
+	n, err := io.ReadFull(file, buffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	return bytes.IndexByte(buffer[:n], 0) >= 0, nil
+}
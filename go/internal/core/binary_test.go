@@ -0,0 +1,67 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试扩展名为源代码的二进制文件不交给检测器扫描
+func TestScanBinaryFile(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "binary")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	binaryPath := filepath.Join(tmpdir, "bundle.py")
+	textPath := filepath.Join(tmpdir, "app.py")
+	assert.NoError(t, ioutil.WriteFile(binaryPath, []byte("\x7fELF\x02\x01\x01\x00\x00eval(input())"), 0644))
+	assert.NoError(t, ioutil.WriteFile(textPath, []byte("eval(input())\n"), 0644))
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+
+	matches, err := scanner.ScanFile(binaryPath)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+
+	for _, parallel := range []bool{false, true} {
+		scanner.SetParallel(parallel)
+		results, err := scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+		assert.NotContains(t, results, binaryPath)
+		assert.Contains(t, results, textPath)
+	}
+}
+
+// 测试只检查文件开头的空字节
+func TestIsBinaryFile(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "binary")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	tests := []struct {
+		content string
+		binary  bool
+	}{
+		{"", false},
+		{"print('Hello')\n", false},
+		{"print('café')\n", false},
+		{"PNG\x00", true},
+		{strings.Repeat("x", binarySniffSize-1) + "\x00", true},
+		{strings.Repeat("x", binarySniffSize) + "\x00", false},
+	}
+	for i, tt := range tests {
+		path := filepath.Join(tmpdir, "file"+string(rune('a'+i)))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(tt.content), 0644))
+		binary, err := isBinaryFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.binary, binary, "%q", tt.content)
+	}
+
+	_, err = isBinaryFile(filepath.Join(tmpdir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// cacheVersion is the version of the on-disk cache format. Version 3 caches the
+// matches of the detectors before they are filtered by the scanner's settings.
+const cacheVersion = 3
+
+// cacheEntry is the cached output of the detectors for a file, before it is filtered by
+// the rule filter, the confidence threshold and the minimum severity, together with
+// the content of the file it was computed from. Modification times are not used: they can stay the same when a file
+// changes, e.g. within the timestamp resolution of the file system.
+type cacheEntry struct {
+	Size    int64   `json:"size"`
+	Hash    string  `json:"hash"`
+	Matches []Match `json:"matches"`
+}
+
+// isFresh reports whether a file with the given size and content hash is unchanged
+// since the entry was cached
+func (e cacheEntry) isFresh(size int64, hash string) bool {
+	return e.Size == size && e.Hash == hash
+}
+
+// fileHash returns the hex-encoded SHA-256 hash of the content of a file
+func fileHash(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// cacheFile is the on-disk representation of the incremental scan cache
+type cacheFile struct {
+	Version int                   `json:"version"`
+	Entries map[string]cacheEntry `json:"entries"`
+}
+
+// SaveCache writes the incremental scan cache to a file
+func (s *Scanner) SaveCache(path string) error {
+	s.cache.mutex.RLock()
+	data, err := json.Marshal(cacheFile{
+		Version: cacheVersion,
+		Entries: s.cache.entries,
+	})
+	s.cache.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// LoadCache loads the incremental scan cache from a file written by SaveCache.
+// A missing cache file is not an error. Entries whose file has been removed or whose
+// size no longer matches are dropped; the content of the others is compared when the
+// file is scanned.
+func (s *Scanner) LoadCache(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var cache cacheFile
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return fmt.Errorf("invalid cache file %s: %v", path, err)
+	}
+	if cache.Version != cacheVersion {
+		return nil
+	}
+
+	s.cache.mutex.Lock()
+	defer s.cache.mutex.Unlock()
+	for filePath, entry := range cache.Entries {
+		info, err := os.Stat(filePath)
+		if err != nil || info.Size() != entry.Size {
+			continue
+		}
+		s.cache.entries[filePath] = entry
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试增量缓存的保存与加载
+func TestSaveLoadCache(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "cache-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	file1 := filepath.Join(tmpdir, "test1.py")
+	file2 := filepath.Join(tmpdir, "test2.py")
+	assert.NoError(t, ioutil.WriteFile(file1, []byte("print(eval('1+1'))"), 0644))
+	assert.NoError(t, ioutil.WriteFile(file2, []byte("print('Hello')"), 0644))
+
+	// 首次扫描并保存缓存
+	detector := &countingDetector{}
+	scanner := NewScanner()
+	scanner.SetIncremental(true)
+	scanner.RegisterDetector(detector)
+	_, err = scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, detector.calls)
+
+	cachePath := filepath.Join(tmpdir, "cache", "scan-cache.json")
+	assert.NoError(t, scanner.SaveCache(cachePath))
+
+	// 修改其中一个文件
+	assert.NoError(t, ioutil.WriteFile(file2, []byte("print(eval('2+2'))"), 0644))
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(file2, later, later))
+
+	// 新的扫描器加载缓存后只重新扫描已修改的文件
+	detector = &countingDetector{}
+	scanner = NewScanner()
+	scanner.SetIncremental(true)
+	scanner.RegisterDetector(detector)
+	assert.NoError(t, scanner.LoadCache(cachePath))
+
+	results, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, detector.calls)
+	assert.Len(t, results, 2)
+}
+
+// 测试增量扫描按文件内容判断缓存是否有效：大小和修改时间不变的修改也会重新扫描
+func TestIncrementalScanContentChange(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "cache-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	file := filepath.Join(tmpdir, "test.py")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("x = TOKEN\n"), 0644))
+	info, err := os.Stat(file)
+	assert.NoError(t, err)
+
+	scanner := NewScanner()
+	scanner.SetIncremental(true)
+	scanner.RegisterDetector(&patternDetector{})
+	matches, err := scanner.ScanFile(file)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+
+	// 修改内容但保持大小和修改时间不变
+	assert.NoError(t, ioutil.WriteFile(file, []byte("x = OTHER\n"), 0644))
+	assert.NoError(t, os.Chtimes(file, info.ModTime(), info.ModTime()))
+
+	matches, err = scanner.ScanFile(file)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+
+	// 只修改时间而内容不变时使用缓存
+	detector := &countingDetector{}
+	scanner = NewScanner()
+	scanner.SetIncremental(true)
+	scanner.RegisterDetector(detector)
+	_, err = scanner.ScanFile(file)
+	assert.NoError(t, err)
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(file, later, later))
+	_, err = scanner.ScanFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, detector.calls)
+}
+
+// 测试缓存保存过滤前的检测结果，两次扫描之间修改过滤设置时不会返回过时的结果
+func TestIncrementalScanSettingsChange(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "cache-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	file := filepath.Join(tmpdir, "test.py")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("import os\nprint(eval('1+1'))\n"), 0644))
+	cachePath := filepath.Join(tmpdir, "scan-cache.json")
+
+	detector := &countingDetector{}
+	scanner := NewScanner()
+	scanner.SetIncremental(true)
+	scanner.RegisterDetector(detector)
+	assert.NoError(t, scanner.SetRuleFilter([]string{"MOCK001"}, nil))
+	matches, err := scanner.ScanFile(file)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+	assert.NoError(t, scanner.SaveCache(cachePath))
+
+	// 新的扫描器加载缓存并使用不同的设置
+	detector = &countingDetector{}
+	scanner = NewScanner()
+	scanner.SetIncremental(true)
+	scanner.RegisterDetector(detector)
+	assert.NoError(t, scanner.LoadCache(cachePath))
+	matches, err = scanner.ScanFile(file)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, 0, detector.calls)
+
+	// 上下文行和置信度阈值同样在读取缓存后应用，且不修改缓存
+	scanner.SetContextLines(1)
+	matches, err = scanner.ScanFile(file)
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, []string{"print(eval('1+1'))"}, matches[0].ContextAfter)
+	}
+	scanner.SetContextLines(0)
+	matches, err = scanner.ScanFile(file)
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 1) {
+		assert.Empty(t, matches[0].ContextAfter)
+	}
+	scanner.SetConfidenceThreshold(0.95)
+	matches, err = scanner.ScanFile(file)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+	assert.NoError(t, scanner.SetMinSeverity("high"))
+	scanner.SetConfidenceThreshold(0.5)
+	matches, err = scanner.ScanFile(file)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, 0, detector.calls)
+}
+
+// 测试加载不存在的缓存文件
+func TestLoadCacheMissingFile(t *testing.T) {
+	scanner := NewScanner()
+	assert.NoError(t, scanner.LoadCache(filepath.Join(os.TempDir(), "does-not-exist.json")))
+}
+
+// 计数检测器，记录DetectFile调用次数
+type countingDetector struct {
+	mockDetector
+	calls int
+}
+
+func (d *countingDetector) DetectFile(filePath string) ([]Match, error) {
+	d.calls++
+	return d.mockDetector.DetectFile(filePath)
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// chunkOverlapLines is the number of lines each chunk extends into the next one,
+// so that a match starting near the end of a chunk is still seen in full
+const chunkOverlapLines = 50
+
+// chunk is a range of lines of a file that is scanned on its own
+type chunk struct {
+	startLine int    // line number of the first line in code
+	ownedEnd  int    // last line owned by this chunk; later lines are only overlap
+	code      string // text of the owned lines followed by the overlap
+}
+
+// splitChunks splits content into chunks of about chunkSize bytes on line boundaries.
+// Each chunk owns a disjoint range of lines and additionally includes up to overlap
+// lines of the next chunk, so multi-line matches crossing a boundary are not lost.
+func splitChunks(content string, chunkSize int64, overlap int) []chunk {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	chunks := []chunk{}
+	for start := 0; start < len(lines); {
+		// Take whole lines until the chunk is full, but always at least one
+		end := start
+		var size int64
+		for end < len(lines) && (end == start || size+int64(len(lines[end])) <= chunkSize) {
+			size += int64(len(lines[end]))
+			end++
+		}
+
+		textEnd := end + overlap
+		if textEnd > len(lines) {
+			textEnd = len(lines)
+		}
+
+		chunks = append(chunks, chunk{
+			startLine: start + 1,
+			ownedEnd:  end,
+			code:      strings.Join(lines[start:textEnd], ""),
+		})
+		start = end
+	}
+
+	return chunks
+}
+
+// scanChunks scans a large file by splitting it into chunks that are scanned in parallel.
+// Line numbers are corrected for each chunk's offset, and a match is kept only by the
+// chunk that owns its line, so matches in the overlap are not reported twice.
+func (s *Scanner) scanChunks(filePath string) ([]Match, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only run detectors that support this file type, as DetectCode does not check it
+	language := s.contentLanguage(filePath)
+	var detectors []Detector
+	for _, detector := range s.detectors {
+		if supportsFile(detector, filePath) || (language != "" && supportsLanguage(detector, language)) {
+			detectors = append(detectors, detector)
+		}
+	}
+
+	chunks := splitChunks(string(content), s.chunkSize, chunkOverlapLines)
+	chunkMatches := make([][]Match, len(chunks))
+	errs := make([]error, len(chunks))
+
+	var wg sync.WaitGroup
+	for i, c := range chunks {
+		wg.Add(1)
+		go func(i int, c chunk) {
+			defer wg.Done()
+
+			for _, detector := range detectors {
+				matches, err := detector.DetectCode(c.code, filePath)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+
+				for _, match := range matches {
+					lineNumber := match.LineNumber + c.startLine - 1
+					if lineNumber < c.startLine || lineNumber > c.ownedEnd {
+						continue
+					}
+					match.LineNumber = lineNumber
+					chunkMatches[i] = append(chunkMatches[i], match)
+				}
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	// Reassemble matches in chunk order
+	var allMatches []Match
+	for i := range chunks {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		allMatches = append(allMatches, chunkMatches[i]...)
+	}
+
+	return allMatches, nil
+}
+
+// supportsFile reports whether a detector supports a file based on its name or extension
+func supportsFile(detector Detector, filePath string) bool {
+	base := strings.ToLower(filepath.Base(filePath))
+	for _, name := range detector.SupportedFilenames() {
+		if matched, _ := filepath.Match(strings.ToLower(name), base); matched {
+			return true
+		}
+	}
+
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), ".")
+	for _, lang := range detector.SupportedLanguages() {
+		if lang == ext {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试按行拆分分块
+func TestSplitChunks(t *testing.T) {
+	content := "a\nb\nc\nd\ne\n"
+	chunks := splitChunks(content, 4, 1)
+	assert.Len(t, chunks, 3)
+
+	assert.Equal(t, 1, chunks[0].startLine)
+	assert.Equal(t, 2, chunks[0].ownedEnd)
+	assert.Equal(t, "a\nb\nc\n", chunks[0].code)
+
+	assert.Equal(t, 3, chunks[1].startLine)
+	assert.Equal(t, 4, chunks[1].ownedEnd)
+	assert.Equal(t, "c\nd\ne\n", chunks[1].code)
+
+	assert.Equal(t, 5, chunks[2].startLine)
+	assert.Equal(t, 5, chunks[2].ownedEnd)
+	assert.Equal(t, "e\n", chunks[2].code)
+}
+
+// 测试分块扫描大文件时跨越分块边界的匹配既不丢失也不重复
+func TestScanFileInChunks(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "chunk-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	// 每行固定为10字节，每个分块100字节即10行
+	var builder strings.Builder
+	lines := 1000
+	for i := 1; i <= lines; i++ {
+		switch {
+		case i%10 == 0:
+			// 多行匹配的起始行正好是分块的最后一行
+			builder.WriteString("BEGIN    \n")
+		case i%10 == 1 && i > 1:
+			builder.WriteString("END      \n")
+		case i%7 == 0:
+			builder.WriteString("TOKEN    \n")
+		default:
+			builder.WriteString(fmt.Sprintf("line%05d\n", i))
+		}
+	}
+	filePath := filepath.Join(tmpdir, "large.py")
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte(builder.String()), 0644))
+
+	// 不分块扫描作为对照
+	scanner := NewScanner()
+	scanner.RegisterDetector(&patternDetector{})
+	expected, err := scanner.ScanFile(filePath)
+	assert.NoError(t, err)
+
+	scanner = NewScanner()
+	scanner.RegisterDetector(&patternDetector{})
+	scanner.SetChunkSize(100)
+	matches, err := scanner.ScanFile(filePath)
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, lineKeys(expected), lineKeys(matches))
+
+	// 每个分块边界上各有一个多行匹配
+	count := 0
+	for _, match := range matches {
+		if match.Signature.ID == "MULTI" {
+			count++
+			assert.Equal(t, 0, match.LineNumber%10)
+		}
+	}
+	assert.Equal(t, lines/10-1, count)
+}
+
+// lineKeys returns "ID:line" keys for comparing matches
+func lineKeys(matches []Match) []string {
+	keys := []string{}
+	for _, match := range matches {
+		keys = append(keys, fmt.Sprintf("%s:%d", match.Signature.ID, match.LineNumber))
+	}
+	return keys
+}
+
+// 按正则匹配的检测器，包含单行和跨行规则
+type patternDetector struct {
+	BaseDetector
+}
+
+func (d *patternDetector) Name() string {
+	return "pattern"
+}
+
+func (d *patternDetector) SupportedLanguages() []string {
+	return []string{"py"}
+}
+
+func (d *patternDetector) Signatures() []Signature {
+	return []Signature{{ID: "MULTI", Severity: "high"}, {ID: "SINGLE", Severity: "high"}}
+}
+
+func (d *patternDetector) DetectFile(filePath string) ([]Match, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return d.DetectCode(string(content), filePath)
+}
+
+func (d *patternDetector) DetectReader(r io.Reader, filePath string) ([]Match, error) {
+	return ReadAndDetect(d, r, filePath)
+}
+
+func (d *patternDetector) DetectCode(code string, filePath string) ([]Match, error) {
+	matches := []Match{}
+	patterns := map[string]*regexp.Regexp{
+		"SINGLE": regexp.MustCompile(`TOKEN`),
+		"MULTI":  regexp.MustCompile(`BEGIN\s*\nEND`),
+	}
+
+	for id, re := range patterns {
+		for _, loc := range re.FindAllStringIndex(code, -1) {
+			matches = append(matches, Match{
+				Signature:  Signature{ID: id, Severity: "high"},
+				FilePath:   filePath,
+				LineNumber: strings.Count(code[:loc[0]], "\n") + 1,
+				Confidence: 0.9,
+			})
+		}
+	}
+
+	return matches, nil
+}
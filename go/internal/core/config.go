@@ -6,11 +6,18 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"gopkg.in/yaml.v3"
 )
 
+// EnvPrefix 是覆盖配置项的环境变量名前缀
+const EnvPrefix = "REMOVERY_"
+
 // Config 表示应用程序配置
 type Config struct {
 	Scanner ScannerConfig `json:"scanner" yaml:"scanner"`
@@ -24,6 +31,11 @@ type ScannerConfig struct {
 	Incremental         bool    `json:"incremental" yaml:"incremental"`
 	ConfidenceThreshold float64 `json:"confidenceThreshold" yaml:"confidenceThreshold"`
 	ExcludePatterns     []string `json:"excludePatterns" yaml:"excludePatterns"`
+	DisabledRules       []string `json:"disabledRules,omitempty" yaml:"disabledRules,omitempty"`
+	EnableOnly          []string `json:"enableOnly,omitempty" yaml:"enableOnly,omitempty"`
+	MinSeverity         string   `json:"minSeverity,omitempty" yaml:"minSeverity,omitempty"`
+	FileTimeout         string   `json:"fileTimeout,omitempty" yaml:"fileTimeout,omitempty"`
+	MaxFindingsPerFile  int      `json:"maxFindingsPerFile,omitempty" yaml:"maxFindingsPerFile,omitempty"`
 }
 
 // WebConfig 表示Web界面配置
@@ -48,6 +60,7 @@ func NewConfig() *Config {
 			Incremental:         false,
 			ConfidenceThreshold: 0.7,
 			ExcludePatterns:     []string{},
+			FileTimeout:         DefaultFileTimeout.String(),
 		},
 		Web: WebConfig{
 			Host:  "localhost",
@@ -62,11 +75,19 @@ func NewConfig() *Config {
 	}
 }
 
-// LoadConfig 从文件加载配置
+// LoadConfig 从文件加载配置，然后使用环境变量覆盖配置项。
+// 优先级从低到高依次为：默认值、配置文件、环境变量
 func LoadConfig(configPath string) (*Config, error) {
 	// 如果未指定配置文件，则使用默认配置
 	if configPath == "" {
-		return NewConfig(), nil
+		config := NewConfig()
+		if err := config.ApplyEnv(); err != nil {
+			return nil, err
+		}
+		if err := config.Validate(); err != nil {
+			return nil, err
+		}
+		return config, nil
 	}
 
 	// 检查文件是否存在
@@ -96,10 +117,233 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("不支持的配置文件格式: %s", ext)
 	}
 
+	// 环境变量优先于配置文件
+	if err := config.ApplyEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
-// SaveConfig 将配置保存到文件
+// ConfigError 列出配置中的所有问题
+type ConfigError struct {
+	Problems []string
+}
+
+// Error 返回每行一个问题的错误信息
+func (e *ConfigError) Error() string {
+	return "配置无效:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Validate 检查配置项的取值范围，返回列出所有问题的*ConfigError
+func (c *Config) Validate() error {
+	var problems []string
+
+	if threshold := c.Scanner.ConfidenceThreshold; threshold < 0 || threshold > 1 {
+		problems = append(problems, fmt.Sprintf("scanner.confidenceThreshold 必须在0到1之间，当前为 %g", threshold))
+	}
+	if severity := c.Scanner.MinSeverity; severity != "" && severityRanks[NormalizeSeverity(severity)] == 0 {
+		problems = append(problems, fmt.Sprintf("scanner.minSeverity 必须是 high、medium 或 low，当前为 %q", severity))
+	}
+	if _, err := c.Scanner.Timeout(); err != nil {
+		problems = append(problems, fmt.Sprintf("scanner.fileTimeout 必须是不小于0的时长（如 30s），当前为 %q", c.Scanner.FileTimeout))
+	}
+	if c.Scanner.MaxFindingsPerFile < 0 {
+		problems = append(problems, fmt.Sprintf("scanner.maxFindingsPerFile 不能小于0，当前为 %d", c.Scanner.MaxFindingsPerFile))
+	}
+	for _, pattern := range c.Scanner.ExcludePatterns {
+		if err := validatePattern(pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("scanner.excludePatterns 中的模式 %q 无效: %v", pattern, err))
+		}
+	}
+
+	listeners := []struct {
+		name string
+		host string
+		port int
+	}{
+		{"web", c.Web.Host, c.Web.Port},
+		{"server", c.Server.Host, c.Server.Port},
+	}
+	for _, listener := range listeners {
+		if strings.TrimSpace(listener.host) == "" {
+			problems = append(problems, fmt.Sprintf("%s.host 不能为空", listener.name))
+		}
+		if listener.port < 1 || listener.port > 65535 {
+			problems = append(problems, fmt.Sprintf("%s.port 必须在1到65535之间，当前为 %d", listener.name, listener.port))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+// Timeout 返回单个文件的扫描时限，为空或0表示不限制
+func (c ScannerConfig) Timeout() (time.Duration, error) {
+	if c.FileTimeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(c.FileTimeout)
+	if err != nil {
+		return 0, err
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("负的时长: %s", c.FileTimeout)
+	}
+	return timeout, nil
+}
+
+// ApplyEnv 使用环境变量覆盖配置项。变量名由前缀、配置节和字段名组成，
+// 例如 REMOVERY_SCANNER_PARALLEL、REMOVERY_SERVER_PORT 和
+// REMOVERY_SCANNER_CONFIDENCE_THRESHOLD，列表的值用逗号分隔
+func (c *Config) ApplyEnv() error {
+	sections := reflect.ValueOf(c).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		prefix := EnvPrefix + envName(sections.Type().Field(i)) + "_"
+		for j := 0; j < section.NumField(); j++ {
+			name := prefix + envName(section.Type().Field(j))
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := setField(section.Field(j), value); err != nil {
+				return fmt.Errorf("环境变量 %s 的值无效: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Merge 将other中的非零值字段覆盖到配置上。布尔字段只能由false覆盖为true，
+// 非空列表整体替换原有列表
+func (c *Config) Merge(other *Config) {
+	if other == nil {
+		return
+	}
+
+	dst := reflect.ValueOf(c).Elem()
+	src := reflect.ValueOf(other).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		for j := 0; j < src.Field(i).NumField(); j++ {
+			field := src.Field(i).Field(j)
+			if field.IsZero() || (field.Kind() == reflect.Slice && field.Len() == 0) {
+				continue
+			}
+			if field.Kind() == reflect.Slice {
+				// 复制列表，避免两个配置共享同一底层数组
+				field = reflect.AppendSlice(reflect.MakeSlice(field.Type(), 0, field.Len()), field)
+			}
+			dst.Field(i).Field(j).Set(field)
+		}
+	}
+}
+
+// envName 将字段的yaml标签（如 confidenceThreshold）转换为环境变量名（如 CONFIDENCE_THRESHOLD）
+func envName(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	var name strings.Builder
+	for i, r := range tag {
+		if i > 0 && unicode.IsUpper(r) {
+			name.WriteByte('_')
+		}
+		name.WriteRune(unicode.ToUpper(r))
+	}
+	return name.String()
+}
+
+// setField 将字符串值解析为字段的类型并赋值
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Slice:
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("不支持的字段类型: %s", field.Kind())
+	}
+	return nil
+}
+
+// configComments 是保存YAML配置时写在各配置项上方的说明，键为配置节和字段名
+var configComments = map[string]string{
+	"scanner": "Scanner settings, used by scan --config. Flags given on the command line override them.\n" +
+		"Findings can also be filtered with disabledRules or enableOnly (lists of rule IDs, not both)\n" +
+		"and minSeverity (high, medium or low).",
+	"scanner.parallel":            "Scan files concurrently",
+	"scanner.incremental":         "Only rescan files that changed since the last scan",
+	"scanner.confidenceThreshold": "Findings below this confidence (0.0-1.0) are not reported",
+	"scanner.excludePatterns":     "Glob patterns of paths to skip, relative to the scan root, e.g. [\"vendor/**\", \"*.min.js\"]",
+	"scanner.disabledRules":       "Rule IDs whose findings are not reported",
+	"scanner.enableOnly":          "Only report findings of these rule IDs",
+	"scanner.minSeverity":         "Drop findings below this severity (high, medium or low)",
+	"scanner.fileTimeout":         "Abandon a file whose scan takes longer than this, e.g. 30s or 2m (0 disables the limit)",
+	"scanner.maxFindingsPerFile":  "Report at most this many findings per file, followed by a TRUNCATED marker (0 means unlimited)",
+	"web":                         "Web interface (re-movery web)",
+	"web.host":                    "Address to listen on",
+	"web.port":                    "Port to listen on",
+	"web.debug":                   "Enable debug logging",
+	"server":                      "API server (re-movery server)",
+	"server.host":                 "Address to listen on",
+	"server.port":                 "Port to listen on",
+	"server.debug":                "Enable debug logging",
+}
+
+// marshalYAMLWithComments 将配置序列化为YAML，并在各配置项上方写入说明
+func marshalYAMLWithComments(config *Config) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(config); err != nil {
+		return nil, err
+	}
+	addConfigComments(&node, "")
+	return yaml.Marshal(&node)
+}
+
+// addConfigComments 为映射节点中有说明的键添加注释，并递归处理嵌套的配置节
+func addConfigComments(node *yaml.Node, prefix string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := prefix + node.Content[i].Value
+		if comment, ok := configComments[key]; ok {
+			node.Content[i].HeadComment = comment
+		}
+		addConfigComments(node.Content[i+1], key+".")
+	}
+}
+
+// SaveConfig 将配置保存到文件，YAML文件带有各配置项的说明
 func SaveConfig(config *Config, configPath string) error {
 	// 创建输出目录（如果不存在）
 	outputDir := filepath.Dir(configPath)
@@ -118,7 +362,7 @@ func SaveConfig(config *Config, configPath string) error {
 			return err
 		}
 	case ".yaml", ".yml":
-		data, err = yaml.Marshal(config)
+		data, err = marshalYAMLWithComments(config)
 		if err != nil {
 			return err
 		}
@@ -130,9 +374,19 @@ func SaveConfig(config *Config, configPath string) error {
 	return ioutil.WriteFile(configPath, data, 0644)
 }
 
-// ApplyToScanner 将配置应用到扫描器
-func (c *Config) ApplyToScanner(scanner *Scanner) {
+// ApplyToScanner 将配置应用到扫描器，最低严重程度无效或同时配置禁用规则和仅启用规则时返回错误
+func (c *Config) ApplyToScanner(scanner *Scanner) error {
 	scanner.SetParallel(c.Scanner.Parallel)
 	scanner.SetIncremental(c.Scanner.Incremental)
 	scanner.SetConfidenceThreshold(c.Scanner.ConfidenceThreshold)
+	if err := scanner.SetMinSeverity(c.Scanner.MinSeverity); err != nil {
+		return err
+	}
+	timeout, err := c.Scanner.Timeout()
+	if err != nil {
+		return err
+	}
+	scanner.SetFileTimeout(timeout)
+	scanner.SetMaxFindingsPerFile(c.Scanner.MaxFindingsPerFile)
+	return scanner.SetRuleFilter(c.Scanner.DisabledRules, c.Scanner.EnableOnly)
 } 
\ No newline at end of file
@@ -161,6 +161,12 @@ func TestSaveConfig(t *testing.T) {
 	yamlConfig, err := LoadConfig(yamlPath)
 	assert.NoError(t, err)
 	assert.Equal(t, config, yamlConfig)
+
+	// YAML配置带有各配置项的说明
+	data, err := ioutil.ReadFile(yamlPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "# Findings below this confidence (0.0-1.0) are not reported\n    confidenceThreshold: 0.8")
+	assert.Contains(t, string(data), "# API server (re-movery server)\nserver:")
 }
 
 // 测试应用配置到扫描器
@@ -175,10 +181,145 @@ func TestApplyToScanner(t *testing.T) {
 	scanner := NewScanner()
 	
 	// 应用配置
-	config.ApplyToScanner(scanner)
+	config.Scanner.DisabledRules = []string{"js004", "PY005"}
+	assert.NoError(t, config.ApplyToScanner(scanner))
 	
 	// 检查扫描器设置
 	assert.True(t, scanner.IsParallel())
 	assert.True(t, scanner.IsIncremental())
 	assert.Equal(t, 0.8, scanner.confidenceThreshold)
-} 
\ No newline at end of file
+	assert.False(t, scanner.ruleEnabled("JS004"))
+	assert.False(t, scanner.ruleEnabled("py005"))
+	assert.True(t, scanner.ruleEnabled("PY001"))
+
+	// 禁用规则和仅启用规则不能同时配置
+	config.Scanner.EnableOnly = []string{"PY004"}
+	assert.Error(t, config.ApplyToScanner(scanner))
+
+	// 最低严重程度
+	config.Scanner.EnableOnly = nil
+	config.Scanner.MinSeverity = "medium"
+	assert.NoError(t, config.ApplyToScanner(scanner))
+	assert.Equal(t, 2, scanner.minSeverityRank)
+	config.Scanner.MinSeverity = "critical"
+	assert.NoError(t, config.ApplyToScanner(scanner))
+	assert.Equal(t, 3, scanner.minSeverityRank)
+	config.Scanner.MinSeverity = "urgent"
+	assert.Error(t, config.ApplyToScanner(scanner))
+	assert.Error(t, config.Validate())
+} 
+// 测试环境变量覆盖配置文件中的配置项
+func TestLoadConfigEnv(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "config-env")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	configPath := filepath.Join(tmpdir, "config.yaml")
+	assert.NoError(t, ioutil.WriteFile(configPath, []byte("scanner:\n  confidenceThreshold: 0.9\nserver:\n  port: 8000\n  host: 0.0.0.0\n"), 0644))
+
+	t.Setenv("REMOVERY_SCANNER_PARALLEL", "true")
+	t.Setenv("REMOVERY_SCANNER_CONFIDENCE_THRESHOLD", "0.5")
+	t.Setenv("REMOVERY_SCANNER_EXCLUDE_PATTERNS", "vendor/**, *.min.js")
+	t.Setenv("REMOVERY_SERVER_PORT", "9000")
+
+	// 优先级：默认值 < 配置文件 < 环境变量
+	config, err := LoadConfig(configPath)
+	assert.NoError(t, err)
+	assert.True(t, config.Scanner.Parallel)
+	assert.Equal(t, 0.5, config.Scanner.ConfidenceThreshold)
+	assert.Equal(t, []string{"vendor/**", "*.min.js"}, config.Scanner.ExcludePatterns)
+	assert.Equal(t, 9000, config.Server.Port)
+	assert.Equal(t, "0.0.0.0", config.Server.Host)
+	assert.Equal(t, 8080, config.Web.Port)
+
+	// 未指定配置文件时环境变量覆盖默认值
+	config, err = LoadConfig("")
+	assert.NoError(t, err)
+	assert.Equal(t, 9000, config.Server.Port)
+	assert.Equal(t, "localhost", config.Server.Host)
+
+	// 无法解析的值返回包含变量名的错误
+	t.Setenv("REMOVERY_WEB_PORT", "eighty")
+	_, err = LoadConfig(configPath)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "REMOVERY_WEB_PORT")
+	}
+}
+
+// 测试合并配置时只覆盖非零值字段
+func TestConfigMerge(t *testing.T) {
+	config := NewConfig()
+	config.Scanner.DisabledRules = []string{"PY005"}
+
+	other := &Config{}
+	other.Scanner.Parallel = true
+	other.Scanner.ExcludePatterns = []string{"vendor/**"}
+	other.Server.Port = 9000
+	other.Web.Host = "0.0.0.0"
+	config.Merge(other)
+
+	assert.True(t, config.Scanner.Parallel)
+	assert.Equal(t, 0.7, config.Scanner.ConfidenceThreshold)
+	assert.Equal(t, []string{"vendor/**"}, config.Scanner.ExcludePatterns)
+	assert.Equal(t, []string{"PY005"}, config.Scanner.DisabledRules)
+	assert.Equal(t, 9000, config.Server.Port)
+	assert.Equal(t, "localhost", config.Server.Host)
+	assert.Equal(t, "0.0.0.0", config.Web.Host)
+	assert.Equal(t, 8080, config.Web.Port)
+
+	// 合并后的列表不与原配置共享
+	other.Scanner.ExcludePatterns[0] = "changed"
+	assert.Equal(t, []string{"vendor/**"}, config.Scanner.ExcludePatterns)
+
+	config.Merge(nil)
+	assert.Equal(t, 9000, config.Server.Port)
+}
+
+// 测试校验配置并列出所有问题
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, NewConfig().Validate())
+
+	tests := []struct {
+		name    string
+		modify  func(config *Config)
+		problem string
+	}{
+		{"置信度过高", func(c *Config) { c.Scanner.ConfidenceThreshold = 5.0 }, "scanner.confidenceThreshold"},
+		{"置信度为负", func(c *Config) { c.Scanner.ConfidenceThreshold = -0.1 }, "scanner.confidenceThreshold"},
+		{"排除模式无效", func(c *Config) { c.Scanner.ExcludePatterns = []string{"vendor/**", "src/[abc"} }, `"src/[abc"`},
+		{"Web端口为负", func(c *Config) { c.Web.Port = -1 }, "web.port"},
+		{"Web端口过大", func(c *Config) { c.Web.Port = 70000 }, "web.port"},
+		{"服务器端口为零", func(c *Config) { c.Server.Port = 0 }, "server.port"},
+		{"Web主机为空", func(c *Config) { c.Web.Host = "" }, "web.host"},
+		{"服务器主机为空", func(c *Config) { c.Server.Host = " " }, "server.host"},
+	}
+	for _, tt := range tests {
+		config := NewConfig()
+		tt.modify(config)
+		err := config.Validate()
+		if assert.Error(t, err, tt.name) {
+			assert.Contains(t, err.Error(), tt.problem, tt.name)
+			assert.Len(t, err.(*ConfigError).Problems, 1, tt.name)
+		}
+	}
+
+	// 同时报告所有问题
+	config := NewConfig()
+	config.Scanner.ConfidenceThreshold = 5.0
+	config.Server.Port = -1
+	config.Web.Host = ""
+	err := config.Validate()
+	if assert.Error(t, err) {
+		assert.Len(t, err.(*ConfigError).Problems, 3)
+	}
+
+	// 加载配置文件时校验
+	tmpdir, err := ioutil.TempDir("", "config-validate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	configPath := filepath.Join(tmpdir, "config.json")
+	assert.NoError(t, ioutil.WriteFile(configPath, []byte(`{"scanner": {"confidenceThreshold": 5.0}}`), 0644))
+	_, err = LoadConfig(configPath)
+	assert.IsType(t, &ConfigError{}, err)
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/analyzers"
+)
+
+// sniffedExtensions are the extensions of files whose language is inferred from their
+// content when no detector supports them by name or extension: scripts without an
+// extension and common extensions of included or misnamed code
+var sniffedExtensions = map[string]bool{
+	"":     true,
+	".inc": true,
+	".txt": true,
+	".cgi": true,
+}
+
+// languageExtensions maps the languages inferred from file content to the extension
+// that detectors list for them in SupportedLanguages
+var languageExtensions = map[string]string{
+	"python":     "py",
+	"javascript": "js",
+	"go":         "go",
+	"shell":      "sh",
+	"ruby":       "rb",
+	"perl":       "pl",
+	"php":        "php",
+}
+
+// contentLanguage returns the language of a file that no detector supports by its
+// name or extension, as inferred from its content, if a detector supports that
+// language. Otherwise, including for files selected by name or extension, it returns "".
+func (s *Scanner) contentLanguage(filePath string) string {
+	if !sniffedExtensions[strings.ToLower(filepath.Ext(filePath))] {
+		return ""
+	}
+	for _, detector := range s.detectors {
+		if supportsFile(detector, filePath) {
+			return ""
+		}
+	}
+
+	language := analyzers.GetFileLanguageFromContent(filePath)
+	for _, detector := range s.detectors {
+		if supportsLanguage(detector, language) {
+			return language
+		}
+	}
+	return ""
+}
+
+// supportsLanguage reports whether a detector supports a language inferred from file content
+func supportsLanguage(detector Detector, language string) bool {
+	for _, lang := range detector.SupportedLanguages() {
+		if lang == language || lang == languageExtensions[language] {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContent runs the detectors that support a language on the content of a file.
+// DetectCode is used as DetectFile skips files with extensions the detector does not support.
+func (s *Scanner) detectContent(filePath string, language string) ([]Match, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var detected []Match
+	for _, detector := range s.detectors {
+		if !supportsLanguage(detector, language) {
+			continue
+		}
+		matches, err := detector.DetectCode(string(content), filePath)
+		if err != nil {
+			return nil, err
+		}
+		detected = append(detected, matches...)
+	}
+	return detected, nil
+}
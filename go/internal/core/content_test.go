@@ -0,0 +1,56 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// extensionDetector 与真实检测器一样在DetectFile中检查扩展名
+type extensionDetector struct {
+	mockDetector
+}
+
+func (d *extensionDetector) DetectFile(filePath string) ([]Match, error) {
+	if filepath.Ext(filePath) != ".py" {
+		return nil, nil
+	}
+	return d.mockDetector.DetectFile(filePath)
+}
+
+// 测试按内容推断语言的文件被交给对应的检测器
+func TestScanDirectoryContentLanguage(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "content-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	files := map[string]string{
+		"deploy":     "#!/usr/bin/env python3\nimport os\n",
+		"helper.inc": "import os\n\ndef run(cmd):\n    os.system(cmd)\n",
+		"notes.txt":  "Remember to run the deploy script.\n",
+		"build":      "#!/bin/bash\nmake\n",
+		"app.py":     "print('hi')\n",
+	}
+	for name, content := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, name), []byte(content), 0644))
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&extensionDetector{})
+
+	results, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Contains(t, results, filepath.Join(tmpdir, "deploy"))
+	assert.Contains(t, results, filepath.Join(tmpdir, "helper.inc"))
+	assert.Contains(t, results, filepath.Join(tmpdir, "app.py"))
+
+	// 文件扫描同样按内容选择检测器
+	matches, err := scanner.ScanFile(filepath.Join(tmpdir, "deploy"))
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+	assert.False(t, scanner.SupportsFile(filepath.Join(tmpdir, "build")))
+}
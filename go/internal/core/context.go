@@ -0,0 +1,41 @@
+package core
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+// addContext records up to n source lines before and after each match. Lines outside
+// the file are left out, so matches near its start or end get less context.
+func addContext(filePath string, matches []Match, n int) error {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	for i := range matches {
+		lineNumber := matches[i].LineNumber
+		if lineNumber < 1 || lineNumber > len(lines) {
+			continue
+		}
+
+		start := lineNumber - 1 - n
+		if start < 0 {
+			start = 0
+		}
+		end := lineNumber + n
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		matches[i].ContextBefore = append([]string{}, lines[start:lineNumber-1]...)
+		matches[i].ContextAfter = append([]string{}, lines[lineNumber:end]...)
+	}
+
+	return nil
+}
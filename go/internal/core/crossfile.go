@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/analyzers"
+)
+
+// SetCrossFile sets whether directory scans annotate Go findings with the
+// definitions of the functions called on their lines. This parses every scanned
+// Go file a second time, so it is off by default.
+func (s *Scanner) SetCrossFile(crossFile bool) {
+	s.crossFile = crossFile
+}
+
+// IsCrossFile returns whether the cross-file pass is enabled
+func (s *Scanner) IsCrossFile() bool {
+	return s.crossFile
+}
+
+// linkDefinitions runs the cross-file pass over the results of a scan of files.
+// A match on a line that calls a function declared in the scanned files is
+// annotated with that declaration. Annotated matches are copied, so that slices
+// shared with the cache or the caller are left unchanged.
+func linkDefinitions(results map[string][]Match, files []string) {
+	var goFiles []string
+	for _, file := range files {
+		if strings.ToLower(filepath.Ext(file)) == ".go" {
+			goFiles = append(goFiles, file)
+		}
+	}
+	if len(goFiles) == 0 {
+		return
+	}
+	index := analyzers.NewCallIndex(goFiles)
+
+	for file, matches := range results {
+		var linked []Match
+		for i := range matches {
+			defs := index.CallsAt(file, matches[i].LineNumber)
+			if len(defs) == 0 {
+				continue
+			}
+			if linked == nil {
+				linked = append([]Match{}, matches...)
+			}
+			annotateDefinition(&linked[i], defs[0])
+		}
+		if linked != nil {
+			results[file] = linked
+		}
+	}
+}
+
+// annotateDefinition records the declaration of a function in a match's metadata
+func annotateDefinition(match *Match, def analyzers.Definition) {
+	metadata := make(map[string]string, len(match.Metadata)+2)
+	for key, value := range match.Metadata {
+		metadata[key] = value
+	}
+	metadata["function"] = def.Name
+	metadata["definition"] = fmt.Sprintf("%s:%d", def.File, def.Line)
+	match.Metadata = metadata
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试跨文件分析注释调用点且不修改原有的匹配结果
+func TestLinkDefinitions(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "crossfile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	shell := filepath.Join(tmpdir, "shell.go")
+	main := filepath.Join(tmpdir, "main.go")
+	assert.NoError(t, ioutil.WriteFile(shell, []byte(`package main
+
+import "os/exec"
+
+func runShell(command string) error {
+	return exec.Command("sh", "-c", command).Run()
+}
+`), 0644))
+	assert.NoError(t, ioutil.WriteFile(main, []byte(`package main
+
+import "os"
+
+func main() {
+	runShell(os.Args[1])
+}
+`), 0644))
+
+	cached := []Match{
+		{Signature: Signature{ID: "TEST001"}, FilePath: main, LineNumber: 5},
+		{Signature: Signature{ID: "TEST002"}, FilePath: main, LineNumber: 6},
+	}
+	results := map[string][]Match{main: cached}
+	linkDefinitions(results, []string{shell, main})
+
+	if assert.Len(t, results[main], 2) {
+		assert.Nil(t, results[main][0].Metadata)
+		assert.Equal(t, "runShell", results[main][1].Metadata["function"])
+		assert.Equal(t, shell+":5", results[main][1].Metadata["definition"])
+	}
+	assert.Nil(t, cached[1].Metadata)
+	assert.Empty(t, results[shell])
+}
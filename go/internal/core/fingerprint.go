@@ -0,0 +1,21 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+)
+
+// Fingerprint returns a stable identifier for a match. It is derived from the file path,
+// the signature ID and the matched code with surrounding whitespace removed, so the same
+// finding keeps its fingerprint when unrelated edits move it to another line.
+func Fingerprint(match Match) string {
+	hash := sha256.New()
+	hash.Write([]byte(filepath.ToSlash(match.FilePath)))
+	hash.Write([]byte{0})
+	hash.Write([]byte(match.Signature.ID))
+	hash.Write([]byte{0})
+	hash.Write([]byte(strings.TrimSpace(match.MatchedCode)))
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// matchPattern reports whether a path relative to the scan root matches a glob pattern.
+// Patterns without a slash are matched against the base name only, so "*.min.js"
+// matches at any depth. Patterns with a slash are matched against the whole relative
+// path, where "**" matches zero or more directories (e.g. "**/testdata/**").
+func matchPattern(pattern string, relPath string) bool {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	relPath = filepath.ToSlash(relPath)
+	if pattern == "" {
+		return false
+	}
+
+	// Backward compatible basename matching
+	if !strings.Contains(pattern, "/") && pattern != "**" {
+		matched, _ := path.Match(pattern, path.Base(relPath))
+		return matched
+	}
+
+	return matchSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(relPath, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, expanding "**"
+func matchSegments(pattern []string, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(rest, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+
+	return len(segments) == 0
+}
+
+// validatePattern returns an error if a glob pattern is malformed, e.g. has an unclosed "["
+func validatePattern(pattern string) error {
+	for _, segment := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchAny reports whether relPath matches any of the patterns
+func matchAny(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
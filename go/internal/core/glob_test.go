@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试相对路径的glob匹配
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		// 不含斜杠的模式仅匹配文件名
+		{"*.min.js", "app.min.js", true},
+		{"*.min.js", "static/js/app.min.js", true},
+		{"node_modules", "web/node_modules", true},
+		{"*.min.js", "app.js", false},
+		// 含斜杠的模式匹配完整的相对路径
+		{"vendor/*", "vendor/lib.go", true},
+		{"vendor/*", "src/vendor/lib.go", false},
+		{"src/generated", "src/generated", true},
+		{"src/generated", "generated", false},
+		{"./src/generated", "src/generated", true},
+		// ** 匹配零个或多个目录
+		{"**/testdata/**", "testdata", true},
+		{"**/testdata/**", "pkg/a/testdata/input.py", true},
+		{"**/testdata/**", "pkg/testdatax/input.py", false},
+		{"src/**/*.py", "src/app.py", true},
+		{"src/**/*.py", "src/a/b/app.py", true},
+		{"src/**/*.py", "lib/app.py", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchPattern(tt.pattern, tt.path), "pattern %q path %q", tt.pattern, tt.path)
+	}
+}
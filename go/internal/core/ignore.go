@@ -0,0 +1,134 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is the name of the ignore file read from the root of directory scans
+const IgnoreFileName = ".removeryignore"
+
+// ruleIDPattern matches the rule ID before the colon of a rule-scoped ignore entry
+var ruleIDPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// IgnoreRules are the entries of an ignore file
+type IgnoreRules struct {
+	// Paths are glob patterns of files and directories that are not scanned
+	Paths []string
+	// Rules suppress the matches of a signature in files matching a glob pattern
+	Rules []RuleIgnore
+}
+
+// RuleIgnore suppresses the matches of the signature RuleID in files matching Path
+type RuleIgnore struct {
+	RuleID string
+	Path   string
+}
+
+// ParseIgnoreFile parses an ignore file. Each line is either a glob pattern of paths
+// to skip, matched like exclude patterns, or ruleId:pathGlob to suppress the matches of
+// one signature in files matching the pattern. Blank lines and lines starting with #
+// are ignored.
+func ParseIgnoreFile(r io.Reader) (*IgnoreRules, error) {
+	rules := &IgnoreRules{}
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern := line
+		ruleID := ""
+		if i := strings.Index(line, ":"); i > 0 && ruleIDPattern.MatchString(strings.TrimSpace(line[:i])) {
+			ruleID = strings.ToUpper(strings.TrimSpace(line[:i]))
+			pattern = strings.TrimSpace(line[i+1:])
+			if pattern == "" {
+				return nil, fmt.Errorf("line %d: missing path pattern after %s:", lineNumber, ruleID)
+			}
+		}
+		if err := validatePattern(pattern); err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q: %v", lineNumber, pattern, err)
+		}
+
+		if ruleID != "" {
+			rules.Rules = append(rules.Rules, RuleIgnore{RuleID: ruleID, Path: pattern})
+		} else {
+			rules.Paths = append(rules.Paths, pattern)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// LoadIgnoreFile reads and parses an ignore file
+func LoadIgnoreFile(filePath string) (*IgnoreRules, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rules, err := ParseIgnoreFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filePath, err)
+	}
+	return rules, nil
+}
+
+// suppressed reports whether a match of the signature with the given ID in the file at
+// relPath, relative to the scan root, is suppressed by a rule-scoped entry
+func (r *IgnoreRules) suppressed(id string, relPath string) bool {
+	for _, rule := range r.Rules {
+		if strings.EqualFold(rule.RuleID, id) && matchPattern(rule.Path, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter drops the matches of a file that are suppressed by rule-scoped entries
+func (r *IgnoreRules) filter(root string, file string, matches []Match) []Match {
+	if len(r.Rules) == 0 || len(matches) == 0 {
+		return matches
+	}
+	relPath, err := filepath.Rel(root, file)
+	if err != nil {
+		return matches
+	}
+
+	var kept []Match
+	for _, match := range matches {
+		if !r.suppressed(match.Signature.ID, relPath) {
+			kept = append(kept, match)
+		}
+	}
+	return kept
+}
+
+// SetIgnoreRules sets the ignore entries applied to directory scans instead of the
+// IgnoreFileName file in the scan root. nil reads the file from the scan root again.
+func (s *Scanner) SetIgnoreRules(rules *IgnoreRules) {
+	s.ignoreRules = rules
+}
+
+// ignoreRulesFor returns the ignore entries for a directory scan of dirPath: those set
+// with SetIgnoreRules or else those of the ignore file in dirPath, if there is one
+func (s *Scanner) ignoreRulesFor(dirPath string) (*IgnoreRules, error) {
+	if s.ignoreRules != nil {
+		return s.ignoreRules, nil
+	}
+
+	rules, err := LoadIgnoreFile(filepath.Join(dirPath, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return &IgnoreRules{}, nil
+	}
+	return rules, err
+}
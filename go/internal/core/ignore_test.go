@@ -0,0 +1,78 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试解析忽略文件中的路径和规则条目
+func TestParseIgnoreFile(t *testing.T) {
+	rules, err := ParseIgnoreFile(strings.NewReader(`
+# 生成的代码
+vendor/**
+*.min.js
+
+py004: tests/**
+JS-LEGACY:legacy/*.js
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vendor/**", "*.min.js"}, rules.Paths)
+	assert.Equal(t, []RuleIgnore{
+		{RuleID: "PY004", Path: "tests/**"},
+		{RuleID: "JS-LEGACY", Path: "legacy/*.js"},
+	}, rules.Rules)
+
+	_, err = ParseIgnoreFile(strings.NewReader("PY004:\n"))
+	assert.Error(t, err)
+
+	_, err = ParseIgnoreFile(strings.NewReader("ok/*\nsrc/[a-\n"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "line 2")
+	}
+}
+
+// 测试扫描根目录下的忽略文件跳过路径并按路径抑制规则
+func TestScanDirectoryIgnoreFile(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "ignore-file")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	for _, name := range []string{"app.py", "vendor/lib.py", "tests/test_app.py"} {
+		path := filepath.Join(tmpdir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte("eval(input())\n"), 0644))
+	}
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, IgnoreFileName), []byte("vendor\nmock001:tests/**\n"), 0644))
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+
+	for _, parallel := range []bool{false, true} {
+		scanner.SetParallel(parallel)
+		results, err := scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Contains(t, results, filepath.Join(tmpdir, "app.py"))
+
+		// 被规则条目抑制的文件仍然被扫描
+		assert.Equal(t, 2, scanner.LastScanStats().FilesScanned)
+	}
+
+	// 显式设置的忽略条目代替扫描根目录下的文件
+	scanner.SetIgnoreRules(&IgnoreRules{Rules: []RuleIgnore{{RuleID: "MOCK001", Path: "app.py"}}})
+	results, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.NotContains(t, results, filepath.Join(tmpdir, "app.py"))
+
+	// 无效的忽略文件
+	scanner.SetIgnoreRules(nil)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, IgnoreFileName), []byte("src/[a-\n"), 0644))
+	_, err = scanner.ScanDirectory(tmpdir, nil)
+	assert.Error(t, err)
+}
@@ -1,7 +1,12 @@
 package core
 
 import (
-	"time"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
 )
 
 // Signature represents a vulnerability signature
@@ -12,6 +17,30 @@ type Signature struct {
 	Description  string   `json:"description"`
 	CodePatterns []string `json:"codePatterns"`
 	References   []string `json:"references"`
+
+	// Category groups related signatures in reports, e.g. injection, crypto, secrets
+	// or deserialization, and CWE is the signature's CWE identifier, e.g. CWE-89
+	Category string `json:"category,omitempty"`
+	CWE      string `json:"cwe,omitempty"`
+
+	// MinConfidence is the confidence a match of this signature needs to be reported.
+	// Zero means the scanner's global threshold is used.
+	MinConfidence float64 `json:"minConfidence,omitempty"`
+
+	// Multiline signatures are matched against the whole code instead of line by line,
+	// with . also matching newlines. Matches are reported at the line they start on.
+	Multiline bool `json:"multiline,omitempty"`
+
+	// Docs is optional extended documentation, shown by the explain command
+	Docs *SignatureDocs `json:"docs,omitempty"`
+}
+
+// SignatureDocs is the extended documentation of a signature: an example of the code it
+// reports, the same code written safely and how to fix it
+type SignatureDocs struct {
+	VulnerableExample string `json:"vulnerableExample,omitempty"`
+	SafeExample       string `json:"safeExample,omitempty"`
+	Remediation       string `json:"remediation,omitempty"`
 }
 
 // Match represents a vulnerability match
@@ -21,6 +50,48 @@ type Match struct {
 	LineNumber  int       `json:"lineNumber"`
 	MatchedCode string    `json:"matchedCode"`
 	Confidence  float64   `json:"confidence"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Explanation *Explanation      `json:"explanation,omitempty"`
+
+	// Source lines before and after the matched line, see Scanner.SetContextLines
+	ContextBefore []string `json:"contextBefore,omitempty"`
+	ContextAfter  []string `json:"contextAfter,omitempty"`
+}
+
+// Explanation records why a match was reported: the pattern or check that fired
+// and the factors that add up to the match's confidence
+type Explanation struct {
+	Pattern string             `json:"pattern"`
+	Factors []ConfidenceFactor `json:"factors"`
+}
+
+// Reason describes the explanation in one line for reports, e.g.
+// "matched eval\s*\(: base confidence +0.80, direct user input +0.10"
+func (e Explanation) Reason() string {
+	factors := make([]string, len(e.Factors))
+	for i, factor := range e.Factors {
+		factors[i] = fmt.Sprintf("%s %+.2f", factor.Reason, factor.Value)
+	}
+	reason := "matched " + e.Pattern
+	if len(factors) > 0 {
+		reason += ": " + strings.Join(factors, ", ")
+	}
+	return reason
+}
+
+// MarshalJSON encodes the explanation with its reason
+func (e Explanation) MarshalJSON() ([]byte, error) {
+	type explanation Explanation
+	return json.Marshal(struct {
+		explanation
+		Reason string `json:"reason"`
+	}{explanation(e), e.Reason()})
+}
+
+// ConfidenceFactor is one component of a match's confidence
+type ConfidenceFactor struct {
+	Reason string  `json:"reason"`
+	Value  float64 `json:"value"`
 }
 
 // Summary represents a summary of scan results
@@ -29,7 +100,102 @@ type Summary struct {
 	High       int            `json:"high"`
 	Medium     int            `json:"medium"`
 	Low        int            `json:"low"`
+	Unknown    int            `json:"unknown,omitempty"`
 	Vulnerabilities map[string]int `json:"vulnerabilities"`
+
+	// ByCategory counts findings by signature category, with uncategorized for
+	// signatures without one, and ByFile by file. TopFiles are the files with the most
+	// findings, most first.
+	ByCategory map[string]int `json:"byCategory,omitempty"`
+	ByFile     map[string]int `json:"byFile,omitempty"`
+	TopFiles   []FileCount    `json:"topFiles,omitempty"`
+}
+
+// FileCount is the number of findings in a file
+type FileCount struct {
+	FilePath string `json:"filePath"`
+	Count    int    `json:"count"`
+}
+
+// TopFilesLimit is the number of files listed in Summary.TopFiles
+const TopFilesLimit = 10
+
+// UncategorizedCategory is the category under which findings of signatures without a
+// category are counted
+const UncategorizedCategory = "uncategorized"
+
+// Total returns the number of findings of all severities, including unknown ones
+func (s Summary) Total() int {
+	return s.High + s.Medium + s.Low + s.Unknown
+}
+
+// severityAliases maps severities used by other tools to the canonical severities
+var severityAliases = map[string]string{
+	"critical":      "high",
+	"moderate":      "medium",
+	"info":          "low",
+	"informational": "low",
+}
+
+// NormalizeSeverity returns the canonical form of a severity: high, medium or low.
+// Case is ignored and aliases are mapped, e.g. critical to high and info to low.
+// Unknown severities return "".
+func NormalizeSeverity(severity string) string {
+	severity = strings.ToLower(strings.TrimSpace(severity))
+	if canonical, ok := severityAliases[severity]; ok {
+		return canonical
+	}
+	switch severity {
+	case "high", "medium", "low":
+		return severity
+	}
+	return ""
+}
+
+// SeverityRank returns the rank of a severity after normalizing it: high=3, medium=2,
+// low=1 and 0 for unknown severities
+func SeverityRank(severity string) int {
+	return severityRanks[NormalizeSeverity(severity)]
+}
+
+// ScanStats holds file counts from a directory scan
+type ScanStats struct {
+	FilesFound   int `json:"filesFound"`
+	FilesScanned int `json:"filesScanned"`
+	FilesFailed  int `json:"filesFailed"`
+}
+
+// ScanError is a file of a directory scan that could not be scanned. The scan continues
+// with the other files.
+type ScanError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the path and the error
+func (e ScanError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the error, e.g. ErrFileTimeout
+func (e ScanError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the error as {"path": ..., "error": ...}
+func (e ScanError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path  string `json:"path"`
+		Error string `json:"error"`
+	}{e.Path, e.Err.Error()})
+}
+
+// Coverage returns the percentage of found files that were scanned successfully
+func (s ScanStats) Coverage() float64 {
+	if s.FilesFound == 0 {
+		return 100
+	}
+	return float64(s.FilesScanned) * 100 / float64(s.FilesFound)
 }
 
 // ReportData represents data for a report
@@ -38,6 +204,56 @@ type ReportData struct {
 	Timestamp string                `json:"timestamp"`
 	Results   map[string][]Match    `json:"results"`
 	Summary   Summary               `json:"summary"`
+	Duration  float64               `json:"duration,omitempty"` // scan duration in seconds
+}
+
+// ForFile returns the report data of a single file: its matches, under the same path,
+// and the summary of those matches. The title, timestamp and duration are kept.
+func (d ReportData) ForFile(filePath string) ReportData {
+	results := map[string][]Match{filePath: d.Results[filePath]}
+	if results[filePath] == nil {
+		results[filePath] = []Match{}
+	}
+	return ReportData{
+		Title:     d.Title,
+		Timestamp: d.Timestamp,
+		Results:   results,
+		Summary:   GenerateSummary(results),
+		Duration:  d.Duration,
+	}
+}
+
+// FileResult holds the matches of one file
+type FileResult struct {
+	FilePath string  `json:"filePath"`
+	Matches  []Match `json:"matches"`
+}
+
+// SortedResults returns the results ordered by file path, with the matches of each
+// file ordered by line number and then signature ID, so that reports are the same
+// across runs. The results are not modified.
+func SortedResults(results map[string][]Match) []FileResult {
+	sorted := make([]FileResult, 0, len(results))
+	for filePath, matches := range results {
+		sorted = append(sorted, FileResult{
+			FilePath: filePath,
+			Matches:  append([]Match{}, matches...),
+		})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].FilePath < sorted[j].FilePath
+	})
+
+	for _, fileResult := range sorted {
+		matches := fileResult.Matches
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].LineNumber != matches[j].LineNumber {
+				return matches[i].LineNumber < matches[j].LineNumber
+			}
+			return matches[i].Signature.ID < matches[j].Signature.ID
+		})
+	}
+	return sorted
 }
 
 // Reporter is an interface for report generators
@@ -45,36 +261,117 @@ type Reporter interface {
 	GenerateReport(data ReportData, outputPath string) error
 }
 
-// Detector is an interface for vulnerability detectors
+// Detector is an interface for vulnerability detectors. Files are selected by
+// extension, using SupportedLanguages, or by name, using SupportedFilenames for
+// files such as Dockerfiles that have no extension. Names may be glob patterns and
+// are matched case-insensitively against the base name of a file. Signatures returns
+// every signature the detector can report. DetectReader detects vulnerabilities in
+// code read from r, such as a request body, like DetectCode does for a string.
 type Detector interface {
 	Name() string
 	SupportedLanguages() []string
+	SupportedFilenames() []string
+	Signatures() []Signature
 	DetectFile(filePath string) ([]Match, error)
 	DetectCode(code string, filePath string) ([]Match, error)
+	DetectReader(r io.Reader, filePath string) ([]Match, error)
+}
+
+// BaseDetector provides the defaults of optional Detector methods and can be
+// embedded by detectors that do not override them
+type BaseDetector struct{}
+
+// SupportedFilenames returns no filenames, so that files are selected by extension only
+func (BaseDetector) SupportedFilenames() []string {
+	return nil
+}
+
+// ReadAndDetect reads r to the end and detects vulnerabilities in the code with
+// DetectCode. It implements DetectReader for detectors that need the whole code at
+// once, such as those using AST analysis, rather than streaming it line by line.
+func ReadAndDetect(detector Detector, r io.Reader, filePath string) ([]Match, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return detector.DetectCode(string(content), filePath)
 }
 
 // GenerateSummary generates a summary from scan results
 func GenerateSummary(results map[string][]Match) Summary {
-	summary := Summary{
-		TotalFiles: len(results),
-		Vulnerabilities: make(map[string]int),
+	summary := newSummary()
+	summary.TotalFiles = len(results)
+
+	for filePath, matches := range results {
+		summary.addFile(filePath, matches)
 	}
+	summary.rankFiles()
 
-	for _, matches := range results {
-		for _, match := range matches {
-			switch match.Signature.Severity {
-			case "high":
-				summary.High++
-			case "medium":
-				summary.Medium++
-			case "low":
-				summary.Low++
-			}
+	return summary
+}
 
-			// Count vulnerabilities by name
-			summary.Vulnerabilities[match.Signature.Name]++
+// newSummary creates an empty summary
+func newSummary() Summary {
+	return Summary{
+		Vulnerabilities: make(map[string]int),
+		ByCategory:      make(map[string]int),
+		ByFile:          make(map[string]int),
+	}
+}
+
+// addFile counts the matches of a file in the summary. TruncatedSignature markers are
+// not findings, so they are not counted towards any severity, --fail-on or budget.
+func (s *Summary) addFile(filePath string, matches []Match) {
+	count := 0
+	for _, match := range matches {
+		if match.Signature.ID == TruncatedSignature.ID {
+			continue
 		}
+		s.addMatch(match)
+		count++
+	}
+	if count > 0 {
+		s.ByFile[filePath] += count
 	}
+}
 
-	return summary
+// addMatch counts a match in the summary by severity and name. Matches of unknown
+// severities are counted as unknown rather than dropped.
+func (s *Summary) addMatch(match Match) {
+	switch NormalizeSeverity(match.Signature.Severity) {
+	case "high":
+		s.High++
+	case "medium":
+		s.Medium++
+	case "low":
+		s.Low++
+	default:
+		s.Unknown++
+	}
+
+	// Count vulnerabilities by name and category
+	s.Vulnerabilities[match.Signature.Name]++
+	category := match.Signature.Category
+	if category == "" {
+		category = UncategorizedCategory
+	}
+	s.ByCategory[category]++
+}
+
+// rankFiles lists the files with the most findings in TopFiles, most first and then by
+// path, once all files are counted
+func (s *Summary) rankFiles() {
+	s.TopFiles = make([]FileCount, 0, len(s.ByFile))
+	for filePath, count := range s.ByFile {
+		s.TopFiles = append(s.TopFiles, FileCount{FilePath: filePath, Count: count})
+	}
+	sort.Slice(s.TopFiles, func(i, j int) bool {
+		if s.TopFiles[i].Count != s.TopFiles[j].Count {
+			return s.TopFiles[i].Count > s.TopFiles[j].Count
+		}
+		return s.TopFiles[i].FilePath < s.TopFiles[j].FilePath
+	})
+	if len(s.TopFiles) > TopFilesLimit {
+		s.TopFiles = s.TopFiles[:TopFilesLimit]
+	}
 } 
\ No newline at end of file
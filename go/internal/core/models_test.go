@@ -0,0 +1,210 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试结果按文件路径排序，同一文件内按行号和签名ID排序
+func TestSortedResults(t *testing.T) {
+	results := map[string][]Match{
+		"src/b.py": {
+			{Signature: Signature{ID: "PY002"}, LineNumber: 7},
+			{Signature: Signature{ID: "PY001"}, LineNumber: 3},
+			{Signature: Signature{ID: "PY001"}, LineNumber: 7},
+		},
+		"src/a.py": {
+			{Signature: Signature{ID: "PY003"}, LineNumber: 1},
+		},
+		"src/c.py": {},
+	}
+
+	sorted := SortedResults(results)
+	if assert.Len(t, sorted, 3) {
+		assert.Equal(t, "src/a.py", sorted[0].FilePath)
+		assert.Equal(t, "src/b.py", sorted[1].FilePath)
+		assert.Equal(t, "src/c.py", sorted[2].FilePath)
+		assert.Empty(t, sorted[2].Matches)
+
+		order := []string{}
+		for _, match := range sorted[1].Matches {
+			order = append(order, fmt.Sprintf("%d:%s", match.LineNumber, match.Signature.ID))
+		}
+		assert.Equal(t, []string{"3:PY001", "7:PY001", "7:PY002"}, order)
+	}
+
+	// 原结果不被修改
+	assert.Equal(t, "PY002", results["src/b.py"][0].Signature.ID)
+}
+
+// 测试并行扫描多次的结果排序后完全一致
+func TestSortedResultsStableAcrossScans(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "sorted-results")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	for _, name := range []string{"e.py", "a.py", "d.py", "b.py", "c.py"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, name), []byte("print('Hello')"), 0644))
+	}
+
+	detector := &fixedDetector{matches: []Match{
+		{Signature: Signature{ID: "B", Severity: "high"}, LineNumber: 2, Confidence: 0.9},
+		{Signature: Signature{ID: "A", Severity: "high"}, LineNumber: 2, Confidence: 0.9},
+		{Signature: Signature{ID: "C", Severity: "low"}, LineNumber: 1, Confidence: 0.9},
+	}}
+	scanner := NewScanner()
+	scanner.RegisterDetector(detector)
+	scanner.SetParallel(true)
+
+	var first []FileResult
+	for i := 0; i < 5; i++ {
+		results, err := scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+
+		sorted := SortedResults(results)
+		if first == nil {
+			first = sorted
+			continue
+		}
+		assert.Equal(t, first, sorted)
+	}
+
+	if assert.Len(t, first, 5) {
+		assert.Equal(t, filepath.Join(tmpdir, "a.py"), first[0].FilePath)
+		assert.Equal(t, "C", first[0].Matches[0].Signature.ID)
+		assert.Equal(t, "A", first[0].Matches[1].Signature.ID)
+	}
+}
+
+// 测试匹配原因汇总触发模式和置信度构成，并写入JSON
+func TestExplanationReason(t *testing.T) {
+	explanation := Explanation{
+		Pattern: `eval\(`,
+		Factors: []ConfidenceFactor{
+			{Reason: "base confidence", Value: 0.8},
+			{Reason: "test file", Value: -0.2},
+		},
+	}
+	assert.Equal(t, `matched eval\(: base confidence +0.80, test file -0.20`, explanation.Reason())
+	assert.Equal(t, "matched PY012", Explanation{Pattern: "PY012"}.Reason())
+
+	data, err := json.Marshal(Match{Explanation: &explanation})
+	assert.NoError(t, err)
+	var decoded struct {
+		Explanation map[string]interface{} `json:"explanation"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, `eval\(`, decoded.Explanation["pattern"])
+	assert.Equal(t, explanation.Reason(), decoded.Explanation["reason"])
+	assert.Len(t, decoded.Explanation["factors"], 2)
+}
+
+// 测试严重程度统一为小写的high、medium或low，并识别critical和info等别名
+func TestNormalizeSeverity(t *testing.T) {
+	tests := map[string]string{
+		"high":     "high",
+		"HIGH":     "high",
+		" Medium ": "medium",
+		"critical": "high",
+		"CRITICAL": "high",
+		"moderate": "medium",
+		"info":     "low",
+		"urgent":   "",
+		"":         "",
+	}
+	for severity, expected := range tests {
+		assert.Equal(t, expected, NormalizeSeverity(severity), severity)
+	}
+}
+
+// 测试摘要按规范化的严重程度计数，未知严重程度不被丢弃
+func TestGenerateSummarySeverities(t *testing.T) {
+	results := map[string][]Match{
+		"app.py": {
+			{Signature: Signature{Name: "A", Severity: "HIGH"}},
+			{Signature: Signature{Name: "B", Severity: "critical"}},
+			{Signature: Signature{Name: "C", Severity: "info"}},
+			{Signature: Signature{Name: "D", Severity: "urgent"}},
+		},
+	}
+
+	summary := GenerateSummary(results)
+	assert.Equal(t, 2, summary.High)
+	assert.Equal(t, 0, summary.Medium)
+	assert.Equal(t, 1, summary.Low)
+	assert.Equal(t, 1, summary.Unknown)
+	assert.Equal(t, 4, summary.Total())
+}
+
+// 测试摘要按类别和文件计数，并按问题数列出问题最多的文件
+func TestGenerateSummaryBreakdown(t *testing.T) {
+	injection := Signature{Name: "A", Severity: "high", Category: "injection"}
+	crypto := Signature{Name: "B", Severity: "medium", Category: "crypto"}
+	other := Signature{Name: "C", Severity: "low"}
+	results := map[string][]Match{
+		"app.py":   {{Signature: injection}, {Signature: crypto}, {Signature: other}},
+		"lib.py":   {{Signature: injection}, {Signature: injection}},
+		"util.py":  {{Signature: crypto}, {Signature: other}},
+		"clean.py": {},
+	}
+
+	summary := GenerateSummary(results)
+	assert.Equal(t, map[string]int{"injection": 3, "crypto": 2, UncategorizedCategory: 2}, summary.ByCategory)
+	assert.Equal(t, map[string]int{"app.py": 3, "lib.py": 2, "util.py": 2}, summary.ByFile)
+	assert.Equal(t, []FileCount{{"app.py", 3}, {"lib.py", 2}, {"util.py", 2}}, summary.TopFiles)
+	assert.Equal(t, 4, summary.TotalFiles)
+	assert.Equal(t, map[string]int{"A": 3, "B": 2, "C": 2}, summary.Vulnerabilities)
+
+	// 只列出问题最多的TopFilesLimit个文件
+	results = map[string][]Match{}
+	for i := 0; i < TopFilesLimit+5; i++ {
+		results[fmt.Sprintf("file%02d.py", i)] = make([]Match, i+1)
+	}
+	summary = GenerateSummary(results)
+	assert.Len(t, summary.ByFile, TopFilesLimit+5)
+	if assert.Len(t, summary.TopFiles, TopFilesLimit) {
+		assert.Equal(t, FileCount{fmt.Sprintf("file%02d.py", TopFilesLimit+4), TopFilesLimit + 5}, summary.TopFiles[0])
+	}
+
+	// 没有问题时新字段不出现在JSON中
+	data, err := json.Marshal(GenerateSummary(map[string][]Match{}))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "byFile")
+}
+
+// 测试单个文件的报告数据
+func TestReportDataForFile(t *testing.T) {
+	match := Match{Signature: Signature{ID: "PY001", Name: "eval", Severity: "high"}, FilePath: "a.py"}
+	data := ReportData{
+		Title:   "Report",
+		Results: map[string][]Match{"a.py": {match}, "b.py": {match, match}},
+		Summary: Summary{TotalFiles: 2, High: 3},
+	}
+
+	single := data.ForFile("a.py")
+	assert.Equal(t, "Report", single.Title)
+	assert.Len(t, single.Results, 1)
+	assert.Equal(t, 1, single.Summary.TotalFiles)
+	assert.Equal(t, 1, single.Summary.High)
+
+	// 没有问题的文件
+	clean := data.ForFile("c.py")
+	assert.Equal(t, []Match{}, clean.Results["c.py"])
+	assert.Equal(t, 0, clean.Summary.Total())
+}
+
+// 测试严重程度的排序忽略大小写并识别别名
+func TestSeverityRank(t *testing.T) {
+	assert.Equal(t, 3, SeverityRank("HIGH"))
+	assert.Equal(t, 3, SeverityRank("critical"))
+	assert.Equal(t, 2, SeverityRank("moderate"))
+	assert.Equal(t, 1, SeverityRank("info"))
+	assert.Equal(t, 0, SeverityRank("urgent"))
+	assert.Equal(t, 0, SeverityRank(""))
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"regexp"
+)
+
+// RedactedSecret replaces the sensitive parts of secret findings
+const RedactedSecret = "****"
+
+// SecretsCategory is the category of signatures whose matches contain credentials
+const SecretsCategory = "secrets"
+
+var (
+	// quotedValueRe matches string literals assigned or passed as values, e.g.
+	// password = "...", "key": "...", login(user, '...'); keys like "private_key" are kept
+	quotedValueRe = regexp.MustCompile("((?:=>|[=:(,])\\s*)(?:\"[^\"]*\"|'[^']*'|`[^`]*`)")
+	// bareValueRe matches unquoted values of assignments, e.g. ENV TOKEN=... or key: ...
+	bareValueRe = regexp.MustCompile("([=:]\\s*)[^\\s'\"`,;)=][^\\s'\"`,;)]*")
+	// tokenRe matches long runs of key material anywhere, e.g. AKIA... or ghp_...; runs
+	// without a digit are taken for identifiers like AWS_SECRET_ACCESS_KEY and kept
+	tokenRe = regexp.MustCompile(`[A-Za-z0-9+/_-]{20,}={0,2}`)
+	digitRe = regexp.MustCompile(`[0-9]`)
+)
+
+// IsSecret reports whether matches of the signature contain credentials
+func (s Signature) IsSecret() bool {
+	return s.Category == SecretsCategory
+}
+
+// SetRedactSecrets sets whether the scanner replaces the secrets in the matched code
+// and the context lines of secret findings with RedactedSecret. Redaction is on by
+// default, so that API responses, notifications and reports built from the matches
+// do not leak the secrets.
+func (s *Scanner) SetRedactSecrets(redact bool) {
+	s.keepSecrets = !redact
+}
+
+// redactMatches redacts the secret findings among the matches of a scan, unless the
+// scanner keeps secrets
+func (s *Scanner) redactMatches(matches []Match) []Match {
+	if s.keepSecrets {
+		return matches
+	}
+	return redactMatches(matches)
+}
+
+// RedactSecrets replaces the secrets in the matched code and the context lines of
+// secret findings with RedactedSecret, so that reports and CI logs do not leak them.
+// Other findings are left unchanged. The matches of each file are replaced by a
+// redacted copy, as they can be shared with the scanner's incremental cache.
+func RedactSecrets(results map[string][]Match) {
+	for filePath, matches := range results {
+		results[filePath] = redactMatches(matches)
+	}
+}
+
+// redactMatches returns matches with the secret findings redacted. The matches are
+// copied if any of them is redacted, so that matches is left unchanged.
+func redactMatches(matches []Match) []Match {
+	var redacted []Match
+	for i := range matches {
+		if !matches[i].Signature.IsSecret() {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]Match{}, matches...)
+		}
+		RedactMatch(&redacted[i])
+	}
+	if redacted == nil {
+		return matches
+	}
+	return redacted
+}
+
+// RedactMatch replaces the secrets in the matched code and the context lines of a
+// match with RedactedSecret. If no value can be told apart in the matched code, the
+// whole code is replaced.
+func RedactMatch(match *Match) {
+	code, redacted := redactLine(match.MatchedCode)
+	if !redacted && code != "" {
+		code = RedactedSecret
+	}
+	match.MatchedCode = code
+
+	match.ContextBefore = redactLines(match.ContextBefore)
+	match.ContextAfter = redactLines(match.ContextAfter)
+}
+
+// redactLines redacts the values and key material in each of lines
+func redactLines(lines []string) []string {
+	if len(lines) == 0 {
+		return lines
+	}
+	redacted := make([]string, len(lines))
+	for i, line := range lines {
+		redacted[i], _ = redactLine(line)
+	}
+	return redacted
+}
+
+// redactLine replaces assigned values and long runs of key material in a line and
+// reports whether anything was replaced
+func redactLine(line string) (string, bool) {
+	redacted := quotedValueRe.ReplaceAllStringFunc(line, func(value string) string {
+		prefix := quotedValueRe.FindStringSubmatch(value)[1]
+		quote := value[len(prefix) : len(prefix)+1]
+		return prefix + quote + RedactedSecret + quote
+	})
+	redacted = bareValueRe.ReplaceAllString(redacted, "${1}"+RedactedSecret)
+	redacted = tokenRe.ReplaceAllStringFunc(redacted, func(token string) string {
+		if !digitRe.MatchString(token) {
+			return token
+		}
+		return RedactedSecret
+	})
+	return redacted, redacted != line
+}
@@ -1,31 +1,91 @@
 package core
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/re-movery/re-movery/internal/utils"
 )
 
 // Scanner is a vulnerability scanner
 type Scanner struct {
-	detectors          []Detector
-	parallel           bool
-	incremental        bool
+	scannerSettings
+	detectors   []Detector
+	cache       *scanCache
+	lastStats   ScanStats
+	lastSummary Summary
+	lastErrors  []ScanError
+	statsMutex  sync.Mutex
+}
+
+// scannerSettings are the settings of a scanner, which Clone copies
+type scannerSettings struct {
+	parallel            bool
+	incremental         bool
 	confidenceThreshold float64
-	cache              map[string][]Match
-	cacheMutex         sync.RWMutex
+	precision           float64
+	chunkSize           int64
+	maxArchiveEntrySize int64
+	maxArchiveSize      int64
+	includePatterns     []string
+	crossFile           bool
+	workers             int
+	disabledRules       map[string]bool
+	contextLines        int
+	enabledRules        map[string]bool
+	minSeverityRank     int
+	summaryOnly         bool
+	progress            ProgressFunc
+	fileTimeout         time.Duration
+	ignoreRules         *IgnoreRules
+	keepClean           bool
+	maxFindingsPerFile  int
+	keepSecrets         bool
+}
+
+// scanCache is the incremental scan cache, which clones of a scanner share
+type scanCache struct {
+	mutex   sync.RWMutex
+	entries map[string]cacheEntry
 }
 
+// ProgressFunc is called after each file of a directory scan with the number of
+// files scanned so far, the number of files found so far and the file just scanned
+type ProgressFunc func(scanned, total int, currentFile string)
+
 // NewScanner creates a new scanner
 func NewScanner() *Scanner {
 	return &Scanner{
-		detectors:          []Detector{},
-		parallel:           false,
-		incremental:        false,
-		confidenceThreshold: 0.7,
-		cache:              make(map[string][]Match),
+		scannerSettings: scannerSettings{
+			parallel:            false,
+			incremental:         false,
+			confidenceThreshold: 0.7,
+			maxArchiveSize:      DefaultMaxArchiveSize,
+		},
+		detectors: []Detector{},
+		cache:     &scanCache{entries: make(map[string]cacheEntry)},
+	}
+}
+
+// Clone returns a scanner with the same detectors and settings that shares the
+// incremental cache of s. Changing the settings of either scanner does not affect the
+// other, so that scans with different settings, e.g. parallel or incremental, can run
+// at the same time.
+func (s *Scanner) Clone() *Scanner {
+	return &Scanner{
+		scannerSettings: s.scannerSettings,
+		detectors:       append([]Detector{}, s.detectors...),
+		cache:           s.cache,
 	}
 }
 
@@ -44,7 +104,32 @@ func (s *Scanner) IsParallel() bool {
 	return s.parallel
 }
 
-// SetIncremental sets whether to use incremental scanning
+// SetWorkers sets the number of files scanned concurrently by parallel directory
+// scans. A number of 0 or less uses one worker per CPU.
+func (s *Scanner) SetWorkers(workers int) {
+	s.workers = workers
+}
+
+// Workers returns the number of files scanned concurrently by parallel directory scans
+func (s *Scanner) Workers() int {
+	if s.workers <= 0 {
+		return runtime.NumCPU()
+	}
+	return s.workers
+}
+
+// SetProgressFunc sets the function called as a directory scan processes each file,
+// or removes it if fn is nil. In parallel scans fn is called by the workers as they
+// finish each file, so calls can overlap and arrive out of order, and fn must be safe
+// for concurrent use; it should return quickly, as the worker waits for it. Files are
+// scanned while the directory is still being walked, so the total grows until the
+// walk is complete.
+func (s *Scanner) SetProgressFunc(fn ProgressFunc) {
+	s.progress = fn
+}
+
+// SetIncremental sets whether to use incremental scanning.
+// Cached results are only reused while a file's content is unchanged.
 func (s *Scanner) SetIncremental(incremental bool) {
 	s.incremental = incremental
 }
@@ -59,6 +144,162 @@ func (s *Scanner) SetConfidenceThreshold(threshold float64) {
 	s.confidenceThreshold = threshold
 }
 
+// SetPrecision sets how strongly the confidence threshold is raised for lower-severity
+// findings, from 0 (the same threshold for all severities) to 1. See minConfidence.
+func (s *Scanner) SetPrecision(precision float64) {
+	s.precision = precision
+}
+
+// Precision returns the precision setting
+func (s *Scanner) Precision() float64 {
+	return s.precision
+}
+
+// severityWeights scale how much the precision setting raises the confidence
+// threshold of each severity; high-severity findings are barely affected
+var severityWeights = map[string]float64{
+	"high":   0.2,
+	"medium": 0.5,
+	"low":    1.0,
+}
+
+// minConfidence returns the confidence a match of the given severity needs to be reported.
+// The threshold moves from the confidence threshold towards 1 as precision increases,
+// faster for lower severities. Unknown severities are treated as low.
+func (s *Scanner) minConfidence(severity string) float64 {
+	weight, ok := severityWeights[NormalizeSeverity(severity)]
+	if !ok {
+		weight = severityWeights["low"]
+	}
+	return s.confidenceThreshold + s.precision*weight*(1-s.confidenceThreshold)
+}
+
+// signatureThreshold returns the confidence a match of the signature needs to be reported:
+// the signature's own MinConfidence if set, otherwise the threshold for its severity
+func (s *Scanner) signatureThreshold(signature Signature) float64 {
+	if signature.MinConfidence > 0 {
+		return signature.MinConfidence
+	}
+	return s.minConfidence(signature.Severity)
+}
+
+// severityRanks orders the severities from low to high
+var severityRanks = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+}
+
+// SetMinSeverity drops matches below a severity (high, medium or low) before scans
+// return or count them, so they take no memory and appear in no report. An empty
+// severity keeps all matches.
+func (s *Scanner) SetMinSeverity(severity string) error {
+	rank := severityRanks[NormalizeSeverity(severity)]
+	if severity != "" && rank == 0 {
+		return fmt.Errorf("invalid minimum severity: %s (expected high, medium or low)", severity)
+	}
+	s.minSeverityRank = rank
+	return nil
+}
+
+// SetIncludePatterns restricts directory scans to files matching at least one of the
+// glob patterns, relative to the scan root. Exclude patterns still apply to included
+// files. No patterns means every file is included.
+func (s *Scanner) SetIncludePatterns(patterns []string) {
+	s.includePatterns = patterns
+}
+
+// SetRuleFilter restricts the signatures whose matches are reported. Matches of
+// disabled signatures are dropped; if enableOnly is not empty, only matches of those
+// signatures are kept. Signature IDs are compared case-insensitively. Disabling rules
+// and enabling only some rules cannot be combined.
+func (s *Scanner) SetRuleFilter(disabled []string, enableOnly []string) error {
+	if len(disabled) > 0 && len(enableOnly) > 0 {
+		return fmt.Errorf("disabled rules and enable-only rules cannot be combined")
+	}
+	s.disabledRules = ruleSet(disabled)
+	s.enabledRules = ruleSet(enableOnly)
+	return nil
+}
+
+// ruleEnabled reports whether matches of the signature with the given ID are reported
+func (s *Scanner) ruleEnabled(id string) bool {
+	id = strings.ToUpper(id)
+	if len(s.enabledRules) > 0 {
+		return s.enabledRules[id]
+	}
+	return !s.disabledRules[id]
+}
+
+// ruleSet returns the set of upper-cased, non-empty signature IDs
+func ruleSet(ids []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, id := range ids {
+		if id = strings.ToUpper(strings.TrimSpace(id)); id != "" {
+			set[id] = true
+		}
+	}
+	return set
+}
+
+// SetKeepCleanFiles sets whether the results of directory scans also list the files
+// without matches, with an empty list of matches. Summary-only scans never list them.
+func (s *Scanner) SetKeepCleanFiles(keep bool) {
+	s.keepClean = keep
+}
+
+// SetContextLines sets the number of source lines before and after each match that
+// are recorded in its ContextBefore and ContextAfter fields. 0 records no context.
+func (s *Scanner) SetContextLines(lines int) {
+	s.contextLines = lines
+}
+
+// SetChunkSize sets the size in bytes above which a file is split into chunks
+// that are scanned in parallel. A size of 0 disables chunked scanning.
+func (s *Scanner) SetChunkSize(size int64) {
+	s.chunkSize = size
+}
+
+// ChunkSize returns the chunk size in bytes
+func (s *Scanner) ChunkSize() int64 {
+	return s.chunkSize
+}
+
+// SetSummaryOnly sets whether directory scans only count the matches instead of
+// returning them. In summary-only mode ScanDirectory returns an empty results map and
+// the counts are available from LastSummary, so memory use does not grow with the
+// number of matches. Cross-file linking is skipped as it needs the matches.
+func (s *Scanner) SetSummaryOnly(summaryOnly bool) {
+	s.summaryOnly = summaryOnly
+}
+
+// IsSummaryOnly returns whether directory scans only count the matches
+func (s *Scanner) IsSummaryOnly() bool {
+	return s.summaryOnly
+}
+
+// LastSummary returns the summary of the matches of the most recent directory scan
+func (s *Scanner) LastSummary() Summary {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	return s.lastSummary
+}
+
+// LastScanStats returns the file counts of the most recent directory scan
+func (s *Scanner) LastScanStats() ScanStats {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	return s.lastStats
+}
+
+// LastScanErrors returns the files of the most recent directory scan that could not be
+// scanned, sorted by path. They are also logged to stderr as the scan continues.
+func (s *Scanner) LastScanErrors() []ScanError {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	return append([]ScanError{}, s.lastErrors...)
+}
+
 // SupportedLanguages returns the list of supported languages
 func (s *Scanner) SupportedLanguages() []string {
 	languages := []string{}
@@ -68,59 +309,409 @@ func (s *Scanner) SupportedLanguages() []string {
 	return languages
 }
 
-// ScanFile scans a file for vulnerabilities
+// DetectorInfo describes a registered detector
+type DetectorInfo struct {
+	Name      string   `json:"name" yaml:"name"`
+	Languages []string `json:"languages" yaml:"languages"`
+}
+
+// Detectors returns the name and supported languages of the registered detectors,
+// in the order they were registered
+func (s *Scanner) Detectors() []DetectorInfo {
+	detectors := []DetectorInfo{}
+	for _, detector := range s.detectors {
+		detectors = append(detectors, DetectorInfo{
+			Name:      detector.Name(),
+			Languages: detector.SupportedLanguages(),
+		})
+	}
+	return detectors
+}
+
+// Signatures returns the signatures of the registered detectors, sorted by ID. If
+// language is not empty, only the detectors that support the language are included.
+func (s *Scanner) Signatures(language string) []Signature {
+	signatures := []Signature{}
+	seen := make(map[string]bool)
+	for _, detector := range s.detectors {
+		if language != "" && !supportsLanguage(detector, language) {
+			continue
+		}
+		for _, signature := range detector.Signatures() {
+			if !seen[signature.ID] {
+				seen[signature.ID] = true
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+	sort.Slice(signatures, func(i, j int) bool {
+		return signatures[i].ID < signatures[j].ID
+	})
+	return signatures
+}
+
+// SupportsFile reports whether any registered detector supports a file by its name or
+// extension or, for files without an extension or with a generic one such as .txt, by
+// the language of its content
+func (s *Scanner) SupportsFile(filePath string) bool {
+	for _, detector := range s.detectors {
+		if supportsFile(detector, filePath) {
+			return true
+		}
+	}
+	return s.contentLanguage(filePath) != ""
+}
+
+// ScanFile scans a file for vulnerabilities. If a file timeout is set, a file that
+// takes longer to scan is abandoned and ErrFileTimeout is returned. If a maximum number
+// of findings per file is set, matches beyond it are replaced by a marker match.
+// Secrets are redacted unless SetRedactSecrets(false) was called.
 func (s *Scanner) ScanFile(filePath string) ([]Match, error) {
+	var matches []Match
+	var err error
+	if s.fileTimeout > 0 {
+		matches, err = s.scanFileWithTimeout(filePath)
+	} else {
+		matches, err = s.scanFile(filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.redactMatches(s.truncateMatches(filePath, matches)), nil
+}
+
+// scanFile scans a file for vulnerabilities without a time limit
+func (s *Scanner) scanFile(filePath string) ([]Match, error) {
 	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	info, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
 		return nil, fmt.Errorf("file does not exist: %s", filePath)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Skip binary files, even with a source extension, before any detector reads them
+	if binary, err := isBinaryFile(filePath); err != nil {
+		return nil, err
+	} else if binary {
+		utils.GetLogger().Debugf("Skipping binary file %s", filePath)
+		return nil, nil
 	}
 
-	// Check if file is in cache
+	// Check if file is in cache and its content unchanged since it was scanned. The
+	// cache holds the matches of the detectors before they are filtered, so that they
+	// stay valid when the filter settings change between scans.
+	var hash string
+	var detected []Match
+	cached := false
 	if s.incremental {
-		s.cacheMutex.RLock()
-		if matches, ok := s.cache[filePath]; ok {
-			s.cacheMutex.RUnlock()
-			return matches, nil
+		hash, err = fileHash(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		s.cache.mutex.RLock()
+		entry, ok := s.cache.entries[filePath]
+		s.cache.mutex.RUnlock()
+		if ok && entry.isFresh(info.Size(), hash) {
+			detected, cached = entry.Matches, true
 		}
-		s.cacheMutex.RUnlock()
 	}
 
-	// Scan file with each detector
-	var allMatches []Match
-	for _, detector := range s.detectors {
-		matches, err := detector.DetectFile(filePath)
+	if !cached {
+		detected, err = s.detectFile(filePath, info.Size())
 		if err != nil {
 			return nil, err
 		}
 
-		// Filter matches by confidence threshold
-		for _, match := range matches {
-			if match.Confidence >= s.confidenceThreshold {
-				allMatches = append(allMatches, match)
+		// Update cache
+		if s.incremental {
+			s.cache.mutex.Lock()
+			s.cache.entries[filePath] = cacheEntry{
+				Size:    info.Size(),
+				Hash:    hash,
+				Matches: detected,
 			}
+			s.cache.mutex.Unlock()
 		}
 	}
 
-	// Update cache
-	if s.incremental {
-		s.cacheMutex.Lock()
-		s.cache[filePath] = allMatches
-		s.cacheMutex.Unlock()
+	// Filtering copies the matches, so the cached matches are not changed below
+	allMatches := s.filterMatches(detected)
+
+	// Record the surrounding source lines
+	if s.contextLines > 0 && len(allMatches) > 0 {
+		if err := addContext(filePath, allMatches, s.contextLines); err != nil {
+			return nil, err
+		}
 	}
 
 	return allMatches, nil
 }
 
+// detectFile scans a file of the given size with each detector, splitting large files
+// into chunks, and returns the matches before they are filtered
+func (s *Scanner) detectFile(filePath string, size int64) ([]Match, error) {
+	if s.chunkSize > 0 && size > s.chunkSize {
+		return s.scanChunks(filePath)
+	}
+	if language := s.contentLanguage(filePath); language != "" {
+		return s.detectContent(filePath, language)
+	}
+
+	var detected []Match
+	for _, detector := range s.detectors {
+		matches, err := detector.DetectFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		detected = append(detected, matches...)
+	}
+	return detected, nil
+}
+
+// ScanCode scans code with every registered detector, regardless of the languages
+// they support, e.g. for code whose language is not known. filePath is only used to
+// label the matches.
+func (s *Scanner) ScanCode(code string, filePath string) ([]Match, error) {
+	var detected []Match
+	for _, detector := range s.detectors {
+		matches, err := detector.DetectCode(code, filePath)
+		if err != nil {
+			return nil, err
+		}
+		detected = append(detected, matches...)
+	}
+
+	return s.redactMatches(s.filterMatches(detected)), nil
+}
+
+// ScanReader scans code read from r with the detectors that support filePath by its
+// name or extension, without writing it to disk. filePath is only used to select the
+// detectors and label the matches; the language is not inferred from the content.
+// Each detector reads r from the start if it is an io.ReadSeeker; otherwise r is read
+// into memory once when more than one detector supports the file.
+func (s *Scanner) ScanReader(r io.Reader, filePath string) ([]Match, error) {
+	var detectors []Detector
+	for _, detector := range s.detectors {
+		if supportsFile(detector, filePath) {
+			detectors = append(detectors, detector)
+		}
+	}
+
+	seeker, ok := r.(io.ReadSeeker)
+	if !ok && len(detectors) > 1 {
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		seeker = bytes.NewReader(content)
+	}
+
+	var detected []Match
+	for _, detector := range detectors {
+		if seeker != nil {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			r = seeker
+		}
+		matches, err := detector.DetectReader(r, filePath)
+		if err != nil {
+			return nil, err
+		}
+		detected = append(detected, matches...)
+	}
+
+	return s.redactMatches(s.filterMatches(detected)), nil
+}
+
+// filterMatches filters matches by the rule filter and the confidence threshold for their
+// signature. Several patterns of one signature can match the same line; only the most
+// confident match is kept.
+func (s *Scanner) filterMatches(detected []Match) []Match {
+	var allMatches []Match
+	seen := make(map[string]int)
+	for _, match := range detected {
+		if !s.ruleEnabled(match.Signature.ID) || match.Confidence < s.signatureThreshold(match.Signature) {
+			continue
+		}
+		if severityRanks[NormalizeSeverity(match.Signature.Severity)] < s.minSeverityRank {
+			continue
+		}
+
+		// Report known severities in their canonical form
+		if severity := NormalizeSeverity(match.Signature.Severity); severity != "" {
+			match.Signature.Severity = severity
+		}
+
+		key := fmt.Sprintf("%s\x00%s\x00%d", match.Signature.ID, match.FilePath, match.LineNumber)
+		if i, ok := seen[key]; ok {
+			if match.Confidence > allMatches[i].Confidence {
+				allMatches[i] = match
+			}
+			continue
+		}
+		seen[key] = len(allMatches)
+		allMatches = append(allMatches, match)
+	}
+	return allMatches
+}
+
 // ScanDirectory scans a directory for vulnerabilities
 func (s *Scanner) ScanDirectory(dirPath string, excludePatterns []string) (map[string][]Match, error) {
+	return s.ScanDirectoryContext(context.Background(), dirPath, excludePatterns)
+}
+
+// ScanDirectoryContext scans a directory for vulnerabilities. The context is checked
+// between files; once it is done, no further files are scanned and ctx.Err() is returned.
+func (s *Scanner) ScanDirectoryContext(ctx context.Context, dirPath string, excludePatterns []string) (map[string][]Match, error) {
+	results, _, err := s.ScanDirectoryErrors(ctx, dirPath, excludePatterns)
+	return results, err
+}
+
+// ScanDirectoryErrors scans a directory like ScanDirectoryContext and also returns the
+// files that could not be scanned, sorted by path. Unlike LastScanErrors, the errors
+// are those of this scan even if other scans run on the scanner at the same time.
+func (s *Scanner) ScanDirectoryErrors(ctx context.Context, dirPath string, excludePatterns []string) (map[string][]Match, []ScanError, error) {
+	results := newScanResults(s.summaryOnly)
+	results.keepClean = s.keepClean
+	if err := s.scanDirectory(ctx, dirPath, excludePatterns, results); err != nil {
+		return nil, nil, err
+	}
+	return results.matches, append([]ScanError{}, results.errors...), nil
+}
+
+// ScanDirectoryStream scans a directory like ScanDirectoryContext, but instead of
+// collecting the results it passes the matches of each file with findings to fn as
+// soon as the file is scanned, so that they can be sent on without keeping them in
+// memory. fn is never called concurrently. Cross-file analysis is not applied. It
+// returns the summary of the scan and, like ScanDirectoryErrors, the files that could
+// not be scanned.
+func (s *Scanner) ScanDirectoryStream(ctx context.Context, dirPath string, excludePatterns []string, fn func(filePath string, matches []Match)) (Summary, []ScanError, error) {
+	results := newScanResults(true)
+	results.stream = fn
+	if err := s.scanDirectory(ctx, dirPath, excludePatterns, results); err != nil {
+		return Summary{}, nil, err
+	}
+	return results.summary, append([]ScanError{}, results.errors...), nil
+}
+
+// scanDirectory scans the files of a directory into results and records the file
+// counts, the summary and the errors of the scan
+func (s *Scanner) scanDirectory(ctx context.Context, dirPath string, excludePatterns []string, results *scanResults) error {
 	// Check if directory exists
 	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("directory does not exist: %s", dirPath)
+		return fmt.Errorf("directory does not exist: %s", dirPath)
+	}
+
+	// Skip the paths of the ignore file like excluded paths, and drop the matches of
+	// its rule-scoped entries as files are added to the results
+	ignore, err := s.ignoreRulesFor(dirPath)
+	if err != nil {
+		return fmt.Errorf("reading ignore file: %v", err)
 	}
+	excludePatterns = append(excludePatterns[:len(excludePatterns):len(excludePatterns)], ignore.Paths...)
+	results.ignore = ignore
+	results.root = dirPath
 
-	// Collect files to scan
 	var filesToScan []string
-	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+	var scanErrors []ScanError
+	progress := &scanProgress{fn: s.progress}
+	if s.parallel {
+		// Feed files to the workers as the walk discovers them, so that slow
+		// directory listings overlap with scanning
+		files := make(chan string, s.Workers())
+		var walkErr error
+		go func() {
+			defer close(files)
+			walkErr = s.walkDirectory(ctx, dirPath, excludePatterns, func(path string) {
+				filesToScan = append(filesToScan, path)
+				progress.found()
+				files <- path
+			})
+		}()
+		scanErrors = s.scanFiles(ctx, files, results, progress)
+		if walkErr != nil {
+			return walkErr
+		}
+	} else {
+		// Collect files to scan
+		err := s.walkDirectory(ctx, dirPath, excludePatterns, func(path string) {
+			filesToScan = append(filesToScan, path)
+			progress.found()
+		})
+		if err != nil {
+			return err
+		}
+
+		// Sequential scanning
+		for _, file := range filesToScan {
+			if ctx.Err() != nil {
+				break
+			}
+
+			matches, err := s.ScanFile(file)
+			progress.done(file)
+			if err != nil {
+				// Log error but continue
+				logScanError(file, err)
+				scanErrors = append(scanErrors, ScanError{Path: file, Err: err})
+				continue
+			}
+
+			results.add(file, matches)
+		}
+	}
+
+	// Discard partial results of a cancelled scan
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Link findings across files
+	if s.crossFile && !results.summaryOnly {
+		linkDefinitions(results.matches, filesToScan)
+	}
+
+	// Record file counts, the summary and the errors of this scan
+	if results.summaryOnly {
+		results.summary.rankFiles()
+	}
+	summary := results.summary
+	if !results.summaryOnly {
+		summary = GenerateSummary(results.matches)
+	}
+	sort.Slice(scanErrors, func(i, j int) bool {
+		return scanErrors[i].Path < scanErrors[j].Path
+	})
+	s.statsMutex.Lock()
+	s.lastStats = ScanStats{
+		FilesFound:   len(filesToScan),
+		FilesScanned: len(filesToScan) - len(scanErrors),
+		FilesFailed:  len(scanErrors),
+	}
+	s.lastSummary = summary
+	s.lastErrors = scanErrors
+	s.statsMutex.Unlock()
+	results.errors = scanErrors
+
+	return nil
+}
+
+// walkDirectory walks a directory and calls found with every file that is not
+// excluded, is included and is supported by a detector, in lexical order
+func (s *Scanner) walkDirectory(ctx context.Context, dirPath string, excludePatterns []string, found func(path string)) error {
+	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Exclude patterns are matched against the path relative to the scan root
+		relPath, err := filepath.Rel(dirPath, path)
 		if err != nil {
 			return err
 		}
@@ -128,90 +719,147 @@ func (s *Scanner) ScanDirectory(dirPath string, excludePatterns []string) (map[s
 		// Skip directories
 		if info.IsDir() {
 			// Check if directory should be excluded
-			for _, pattern := range excludePatterns {
-				if matched, _ := filepath.Match(pattern, info.Name()); matched {
-					return filepath.SkipDir
-				}
+			if relPath != "." && matchAny(excludePatterns, relPath) {
+				return filepath.SkipDir
 			}
 			return nil
 		}
 
 		// Check if file should be excluded
-		for _, pattern := range excludePatterns {
-			if matched, _ := filepath.Match(pattern, info.Name()); matched {
-				return nil
-			}
+		if matchAny(excludePatterns, relPath) {
+			return nil
 		}
 
-		// Check if file extension is supported
-		ext := strings.ToLower(filepath.Ext(path))
-		if ext == "" {
+		// Check if file is included
+		if len(s.includePatterns) > 0 && !matchAny(s.includePatterns, relPath) {
 			return nil
 		}
 
-		// Remove the dot from the extension
-		ext = ext[1:]
-
-		// Check if any detector supports this file type
-		for _, detector := range s.detectors {
-			for _, lang := range detector.SupportedLanguages() {
-				if lang == ext {
-					filesToScan = append(filesToScan, path)
-					return nil
-				}
-			}
+		// Check if any detector supports this file type by its name or extension
+		if s.SupportsFile(path) {
+			found(path)
 		}
 
 		return nil
 	})
+}
 
-	if err != nil {
-		return nil, err
-	}
-
-	// Scan files
-	results := make(map[string][]Match)
-	if s.parallel {
-		// Parallel scanning
-		var wg sync.WaitGroup
-		resultsMutex := sync.Mutex{}
-
-		for _, file := range filesToScan {
-			wg.Add(1)
-			go func(file string) {
-				defer wg.Done()
+// scanFiles scans the files received from the channel with a fixed number of workers
+// until the channel is closed, adds the matches to results and returns the errors of
+// the files that failed. Each scanned file is reported to progress. Once the context is
+// done, the remaining files are drained without being scanned.
+func (s *Scanner) scanFiles(ctx context.Context, files <-chan string, results *scanResults, progress *scanProgress) []ScanError {
+	var scanErrors []ScanError
+	var failedMutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < s.Workers(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for file := range files {
+				if ctx.Err() != nil {
+					continue
+				}
 
 				matches, err := s.ScanFile(file)
+				progress.done(file)
 				if err != nil {
 					// Log error but continue
-					fmt.Fprintf(os.Stderr, "Error scanning file %s: %v\n", file, err)
-					return
+					logScanError(file, err)
+					failedMutex.Lock()
+					scanErrors = append(scanErrors, ScanError{Path: file, Err: err})
+					failedMutex.Unlock()
+					continue
 				}
 
-				if len(matches) > 0 {
-					resultsMutex.Lock()
-					results[file] = matches
-					resultsMutex.Unlock()
-				}
-			}(file)
-		}
-
-		wg.Wait()
-	} else {
-		// Sequential scanning
-		for _, file := range filesToScan {
-			matches, err := s.ScanFile(file)
-			if err != nil {
-				// Log error but continue
-				fmt.Fprintf(os.Stderr, "Error scanning file %s: %v\n", file, err)
-				continue
+				results.add(file, matches)
 			}
+		}()
+	}
 
-			if len(matches) > 0 {
-				results[file] = matches
-			}
+	wg.Wait()
+	return scanErrors
+}
+
+// scanResults collects the matches of a directory scan by file or, in summary-only
+// mode, only their counts. If stream is set, the matches of each file are also passed
+// to it.
+type scanResults struct {
+	mutex       sync.Mutex
+	summaryOnly bool
+	matches     map[string][]Match
+	summary     Summary
+	stream      func(filePath string, matches []Match)
+	ignore      *IgnoreRules
+	root        string
+	keepClean   bool
+	errors      []ScanError
+}
+
+// newScanResults creates an empty collection of scan results
+func newScanResults(summaryOnly bool) *scanResults {
+	return &scanResults{
+		summaryOnly: summaryOnly,
+		matches:     make(map[string][]Match),
+		summary:     newSummary(),
+	}
+}
+
+// add records the matches of a file that are not suppressed by the ignore file; files
+// without matches are left out unless clean files are kept
+func (r *scanResults) add(file string, matches []Match) {
+	if r.ignore != nil {
+		matches = r.ignore.filter(r.root, file, matches)
+	}
+	if len(matches) == 0 && (!r.keepClean || r.summaryOnly) {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if !r.summaryOnly {
+		if matches == nil {
+			matches = []Match{}
 		}
+		r.matches[file] = matches
+		return
 	}
 
-	return results, nil
-} 
\ No newline at end of file
+	// Count the matches the same way GenerateSummary does
+	r.summary.TotalFiles++
+	r.summary.addFile(file, matches)
+	if r.stream != nil {
+		r.stream(file, matches)
+	}
+}
+
+// scanProgress counts the files of a directory scan and reports them to a ProgressFunc
+type scanProgress struct {
+	mutex   sync.Mutex
+	fn      ProgressFunc
+	scanned int
+	total   int
+}
+
+// found records a file found by the walk
+func (p *scanProgress) found() {
+	p.mutex.Lock()
+	p.total++
+	p.mutex.Unlock()
+}
+
+// done records a scanned file and reports the progress, if a function is set. The
+// function is called without holding the lock, so that a slow function only holds up
+// the worker that calls it.
+func (p *scanProgress) done(file string) {
+	p.mutex.Lock()
+	p.scanned++
+	scanned, total := p.scanned, p.total
+	p.mutex.Unlock()
+
+	if p.fn != nil {
+		p.fn(scanned, total, file)
+	}
+}
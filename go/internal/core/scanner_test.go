@@ -1,10 +1,18 @@
 package core
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -17,6 +25,35 @@ func TestNewScanner(t *testing.T) {
 	assert.False(t, scanner.IsIncremental())
 }
 
+// 测试克隆的扫描器设置互不影响，但共享增量扫描缓存
+func TestScannerClone(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "clone")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	filePath := filepath.Join(tmpdir, "test.py")
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte("eval(input())\n"), 0644))
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	scanner.SetWorkers(3)
+
+	clone := scanner.Clone()
+	clone.SetParallel(true)
+	clone.SetIncremental(true)
+	clone.RegisterDetector(&mockDetector{})
+	assert.False(t, scanner.IsParallel())
+	assert.False(t, scanner.IsIncremental())
+	assert.Equal(t, 3, clone.Workers())
+	assert.Len(t, scanner.Detectors(), 1)
+	assert.Len(t, clone.Detectors(), 2)
+
+	_, err = clone.ScanFile(filePath)
+	assert.NoError(t, err)
+	scanner.cache.mutex.RLock()
+	assert.Contains(t, scanner.cache.entries, filePath)
+	scanner.cache.mutex.RUnlock()
+}
+
 // 测试设置并行处理
 func TestSetParallel(t *testing.T) {
 	scanner := NewScanner()
@@ -187,7 +224,9 @@ func TestGenerateSummary(t *testing.T) {
 }
 
 // 模拟检测器
-type mockDetector struct{}
+type mockDetector struct {
+	BaseDetector
+}
 
 func (d *mockDetector) Name() string {
 	return "mock"
@@ -197,6 +236,17 @@ func (d *mockDetector) SupportedLanguages() []string {
 	return []string{"mock", "py", "python"}
 }
 
+func (d *mockDetector) Signatures() []Signature {
+	return []Signature{
+		{
+			ID:          "MOCK001",
+			Name:        "Mock vulnerability",
+			Severity:    "high",
+			Description: "This is a mock vulnerability",
+		},
+	}
+}
+
 func (d *mockDetector) DetectFile(filePath string) ([]Match, error) {
 	return []Match{
 		{
@@ -229,4 +279,743 @@ func (d *mockDetector) DetectCode(code string, filePath string) ([]Match, error)
 			Confidence:  0.9,
 		},
 	}, nil
-} 
\ No newline at end of file
+}
+
+func (d *mockDetector) DetectReader(r io.Reader, filePath string) ([]Match, error) {
+	return ReadAndDetect(d, r, filePath)
+} 
+// 测试从读取器扫描代码，每个支持该文件的检测器都读到完整的代码
+func TestScanReader(t *testing.T) {
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	scanner.RegisterDetector(&patternDetector{})
+
+	code := "x = TOKEN\nBEGIN\nEND\n"
+	readers := []io.Reader{
+		strings.NewReader(code),
+		io.MultiReader(strings.NewReader("x = TOKEN\n"), strings.NewReader("BEGIN\nEND\n")),
+	}
+	for _, r := range readers {
+		matches, err := scanner.ScanReader(r, "app.py")
+		assert.NoError(t, err)
+		assert.Len(t, matches, 3)
+		for _, match := range matches {
+			assert.Equal(t, "app.py", match.FilePath)
+			if match.Signature.ID == "MOCK001" {
+				assert.Equal(t, code, match.MatchedCode)
+			}
+		}
+	}
+
+	// 没有检测器支持的文件不扫描
+	matches, err := scanner.ScanReader(strings.NewReader(code), "app.rb")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+// 测试按语言列出已注册检测器的签名
+func TestScannerSignatures(t *testing.T) {
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	scanner.RegisterDetector(&patternDetector{})
+
+	ids := func(signatures []Signature) []string {
+		ids := []string{}
+		for _, signature := range signatures {
+			ids = append(ids, signature.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"MOCK001", "MULTI", "SINGLE"}, ids(scanner.Signatures("")))
+	assert.Equal(t, []string{"MOCK001", "MULTI", "SINGLE"}, ids(scanner.Signatures("python")))
+	assert.Equal(t, []string{"MOCK001"}, ids(scanner.Signatures("mock")))
+	assert.Empty(t, scanner.Signatures("go"))
+}
+
+// 测试按相对路径排除嵌套目录
+func TestScanDirectoryExcludeRelativePaths(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "exclude")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	files := []string{
+		"main.py",
+		"vendor/lib.py",
+		"src/generated/models.py",
+		"src/app.py",
+		"pkg/a/testdata/input.py",
+		"static/app.min.py",
+	}
+	for _, file := range files {
+		path := filepath.Join(tmpdir, filepath.FromSlash(file))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte("print(eval('1+1'))"), 0644))
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+
+	results, err := scanner.ScanDirectory(tmpdir, []string{"vendor/*", "src/generated", "**/testdata/**", "*.min.py"})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Contains(t, results, filepath.Join(tmpdir, "main.py"))
+	assert.Contains(t, results, filepath.Join(tmpdir, "src", "app.py"))
+}
+
+// 扫描时取消上下文的检测器
+type cancellingDetector struct {
+	mockDetector
+	cancel context.CancelFunc
+	calls  int32
+}
+
+func (d *cancellingDetector) DetectFile(filePath string) ([]Match, error) {
+	atomic.AddInt32(&d.calls, 1)
+	d.cancel()
+	return d.mockDetector.DetectFile(filePath)
+}
+
+// 测试取消目录扫描
+func TestScanDirectoryContextCancel(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "cancel")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	for i := 0; i < 5; i++ {
+		path := filepath.Join(tmpdir, fmt.Sprintf("test%d.py", i))
+		assert.NoError(t, ioutil.WriteFile(path, []byte("print('Hello')"), 0644))
+	}
+
+	// 顺序扫描在第一个文件后停止
+	ctx, cancel := context.WithCancel(context.Background())
+	detector := &cancellingDetector{cancel: cancel}
+	scanner := NewScanner()
+	scanner.RegisterDetector(detector)
+
+	results, err := scanner.ScanDirectoryContext(ctx, tmpdir, nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, results)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&detector.calls))
+
+	// 已取消的上下文不会启动并行扫描
+	detector = &cancellingDetector{cancel: func() {}}
+	scanner = NewScanner()
+	scanner.RegisterDetector(detector)
+	scanner.SetParallel(true)
+
+	_, err = scanner.ScanDirectoryContext(ctx, tmpdir, nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&detector.calls))
+}
+
+// 返回固定匹配结果的检测器
+type fixedDetector struct {
+	mockDetector
+	matches []Match
+}
+
+func (d *fixedDetector) DetectFile(filePath string) ([]Match, error) {
+	matches := make([]Match, len(d.matches))
+	for i, match := range d.matches {
+		match.FilePath = filePath
+		matches[i] = match
+	}
+	return matches, nil
+}
+
+// 测试精度设置优先过滤低严重程度的匹配
+func TestScanFilePrecision(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "precision")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	filePath := filepath.Join(tmpdir, "test.py")
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte("print('Hello')"), 0644))
+
+	detector := &fixedDetector{}
+	for _, severity := range []string{"high", "medium", "low"} {
+		detector.matches = append(detector.matches, Match{
+			Signature:  Signature{ID: severity, Severity: severity},
+			Confidence: 0.8,
+		})
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(detector)
+
+	reported := func(precision float64) []string {
+		scanner.SetPrecision(precision)
+		matches, err := scanner.ScanFile(filePath)
+		assert.NoError(t, err)
+		ids := []string{}
+		for _, match := range matches {
+			ids = append(ids, match.Signature.ID)
+		}
+		return ids
+	}
+
+	// 随着精度提高，先过滤低危，再过滤中危，高危始终保留
+	assert.Equal(t, []string{"high", "medium", "low"}, reported(0))
+	assert.Equal(t, []string{"high", "medium"}, reported(0.5))
+	assert.Equal(t, []string{"high"}, reported(1))
+
+	// 阈值随精度单调递增
+	previous := 0.0
+	for _, precision := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		scanner.SetPrecision(precision)
+		assert.True(t, scanner.minConfidence("high") <= scanner.minConfidence("medium"))
+		assert.True(t, scanner.minConfidence("medium") <= scanner.minConfidence("low"))
+		assert.True(t, scanner.minConfidence("low") >= previous)
+		previous = scanner.minConfidence("low")
+	}
+}
+
+// 测试扫描时丢弃低于最低严重程度的匹配
+func TestScanFileMinSeverity(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "min-severity")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	filePath := filepath.Join(tmpdir, "test.py")
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte("print('Hello')"), 0644))
+
+	detector := &fixedDetector{}
+	for _, severity := range []string{"high", "medium", "low", "info"} {
+		detector.matches = append(detector.matches, Match{
+			Signature:  Signature{ID: severity, Severity: severity},
+			Confidence: 0.8,
+		})
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(detector)
+
+	reported := func(severity string) []string {
+		assert.NoError(t, scanner.SetMinSeverity(severity))
+		matches, err := scanner.ScanFile(filePath)
+		assert.NoError(t, err)
+		ids := []string{}
+		for _, match := range matches {
+			ids = append(ids, match.Signature.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"high", "medium", "low", "info"}, reported(""))
+	assert.Equal(t, []string{"high", "medium", "low", "info"}, reported("low"))
+	assert.Equal(t, []string{"high", "medium"}, reported("Medium"))
+	assert.Equal(t, []string{"high"}, reported("high"))
+	assert.Equal(t, []string{"high"}, reported("critical"))
+
+	assert.Error(t, scanner.SetMinSeverity("urgent"))
+}
+
+// 测试同一签名的多个模式匹配同一行时只保留一个匹配
+func TestScanFileDeduplicatesMatches(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "dedup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	filePath := filepath.Join(tmpdir, "test.py")
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte("Function(x)\nFunction(y)\n"), 0644))
+
+	signature := Signature{ID: "JS002", Severity: "high"}
+	detector := &fixedDetector{
+		matches: []Match{
+			{Signature: signature, LineNumber: 1, MatchedCode: "Function(x)", Confidence: 0.8},
+			{Signature: signature, LineNumber: 1, MatchedCode: "Function(x)", Confidence: 0.9},
+			{Signature: signature, LineNumber: 2, MatchedCode: "Function(y)", Confidence: 0.8},
+		},
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(detector)
+
+	matches, err := scanner.ScanFile(filePath)
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, 1, matches[0].LineNumber)
+		assert.Equal(t, 0.9, matches[0].Confidence)
+		assert.Equal(t, 2, matches[1].LineNumber)
+	}
+}
+
+// 测试只扫描匹配包含模式的文件
+func TestScanDirectoryIncludePatterns(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "include")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	files := []string{
+		"main.py",
+		"src/app.py",
+		"src/pkg/models.py",
+		"src/generated/schema.py",
+		"lib/util.py",
+	}
+	for _, file := range files {
+		path := filepath.Join(tmpdir, filepath.FromSlash(file))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte("print(eval('1+1'))"), 0644))
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	scanner.SetIncludePatterns([]string{"src/**/*.py"})
+
+	// 排除模式优先于包含模式
+	results, err := scanner.ScanDirectory(tmpdir, []string{"src/generated"})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Contains(t, results, filepath.Join(tmpdir, "src", "app.py"))
+	assert.Contains(t, results, filepath.Join(tmpdir, "src", "pkg", "models.py"))
+
+	// 不设置包含模式时扫描所有文件
+	scanner.SetIncludePatterns(nil)
+	results, err = scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, len(files))
+}
+
+// 测试并行扫描在遍历目录的同时扫描文件，结果与顺序扫描一致
+func TestScanDirectoryParallelPipeline(t *testing.T) {
+	tmpdir := createBenchmarkTree(t, 200)
+	defer os.RemoveAll(tmpdir)
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	sequential, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Len(t, sequential, 200)
+
+	scanner.SetParallel(true)
+	for _, workers := range []int{1, 3, 0} {
+		scanner.SetWorkers(workers)
+		parallel, err := scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, sequential, parallel)
+		assert.Equal(t, 200, scanner.LastScanStats().FilesScanned)
+	}
+}
+
+// 创建包含多层目录的合成文件树
+func createBenchmarkTree(tb testing.TB, files int) string {
+	tmpdir, err := ioutil.TempDir("", "scan-tree")
+	assert.NoError(tb, err)
+
+	for i := 0; i < files; i++ {
+		dir := filepath.Join(tmpdir, fmt.Sprintf("pkg%d", i%20), fmt.Sprintf("sub%d", i%7))
+		assert.NoError(tb, os.MkdirAll(dir, 0755))
+		assert.NoError(tb, ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d.py", i)), []byte("print('Hello')\n"), 0644))
+	}
+
+	return tmpdir
+}
+
+// 基准测试：边遍历边扫描
+func BenchmarkScanDirectoryPipelined(b *testing.B) {
+	tmpdir := createBenchmarkTree(b, 5000)
+	defer os.RemoveAll(tmpdir)
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	scanner.SetParallel(true)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := scanner.ScanDirectory(tmpdir, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// 基准测试：先完整遍历目录再扫描
+func BenchmarkScanDirectoryTwoPhase(b *testing.B) {
+	tmpdir := createBenchmarkTree(b, 5000)
+	defer os.RemoveAll(tmpdir)
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var filesToScan []string
+		if err := scanner.walkDirectory(ctx, tmpdir, nil, func(path string) {
+			filesToScan = append(filesToScan, path)
+		}); err != nil {
+			b.Fatal(err)
+		}
+
+		files := make(chan string, len(filesToScan))
+		for _, file := range filesToScan {
+			files <- file
+		}
+		close(files)
+		scanner.scanFiles(ctx, files, newScanResults(false), &scanProgress{})
+	}
+}
+
+// 测试只统计摘要的模式不返回匹配结果，摘要与完整结果一致
+func TestScanDirectorySummaryOnly(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "summary-only")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	for _, name := range []string{"a.py", "b.py", "c.py", "readme.md"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, name), []byte("eval(x)\n"), 0644))
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	results, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	expected := GenerateSummary(results)
+	assert.Equal(t, expected, scanner.LastSummary())
+	assert.Equal(t, 3, expected.High)
+
+	for _, parallel := range []bool{false, true} {
+		scanner.SetSummaryOnly(true)
+		scanner.SetParallel(parallel)
+		results, err := scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+		assert.Equal(t, expected, scanner.LastSummary())
+	}
+}
+
+// 测试按需在目录扫描结果中保留没有问题的文件
+func TestScanDirectoryKeepCleanFiles(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "keep-clean")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	for _, name := range []string{"a.py", "b.py"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, name), []byte("print(x)\n"), 0644))
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&fixedDetector{})
+	results, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	scanner.SetKeepCleanFiles(true)
+	for _, parallel := range []bool{false, true} {
+		scanner.SetParallel(parallel)
+		results, err := scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]Match{
+			filepath.Join(tmpdir, "a.py"): {},
+			filepath.Join(tmpdir, "b.py"): {},
+		}, results)
+	}
+}
+
+// 测试目录扫描收集无法扫描的文件及其错误，并继续扫描其他文件
+func TestScanDirectoryErrors(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "scan-errors")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	// 指向不存在文件的符号链接在扫描时无法读取
+	okPath := filepath.Join(tmpdir, "ok.py")
+	assert.NoError(t, ioutil.WriteFile(okPath, []byte("print(x)\n"), 0644))
+	for _, name := range []string{"b.py", "a.py"} {
+		assert.NoError(t, os.Symlink(filepath.Join(tmpdir, "missing"), filepath.Join(tmpdir, name)))
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	for _, parallel := range []bool{false, true} {
+		scanner.SetParallel(parallel)
+		results, scanErrors, err := scanner.ScanDirectoryErrors(context.Background(), tmpdir, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, results, okPath)
+		if assert.Len(t, scanErrors, 2) {
+			assert.Equal(t, filepath.Join(tmpdir, "a.py"), scanErrors[0].Path)
+			assert.Equal(t, filepath.Join(tmpdir, "b.py"), scanErrors[1].Path)
+			assert.Contains(t, scanErrors[0].Error(), "file does not exist")
+		}
+		assert.Equal(t, scanErrors, scanner.LastScanErrors())
+		assert.Equal(t, 2, scanner.LastScanStats().FilesFailed)
+	}
+
+	// 错误以路径和错误信息编码为JSON
+	data, err := json.Marshal(ScanError{Path: "a.py", Err: ErrFileTimeout})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"path": "a.py", "error": "scan exceeded the file timeout"}`, string(data))
+
+	// 没有错误时为空列表
+	assert.NoError(t, os.Remove(filepath.Join(tmpdir, "a.py")))
+	assert.NoError(t, os.Remove(filepath.Join(tmpdir, "b.py")))
+	_, scanErrors, err := scanner.ScanDirectoryErrors(context.Background(), tmpdir, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, scanErrors)
+	assert.Empty(t, scanErrors)
+	assert.Empty(t, scanner.LastScanErrors())
+}
+
+// 测试流式扫描逐个文件回调匹配结果，摘要与完整结果一致
+func TestScanDirectoryStream(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "stream")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	for _, name := range []string{"a.py", "b.py", "c.py", "readme.md"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, name), []byte("eval(x)\n"), 0644))
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	results, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+
+	for _, parallel := range []bool{false, true} {
+		scanner.SetParallel(parallel)
+		streamed := make(map[string][]Match)
+		summary, scanErrors, err := scanner.ScanDirectoryStream(context.Background(), tmpdir, nil, func(filePath string, matches []Match) {
+			streamed[filePath] = matches
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, results, streamed)
+		assert.Equal(t, GenerateSummary(results), summary)
+		assert.Empty(t, scanErrors)
+	}
+
+	// 返回无法扫描的文件
+	assert.NoError(t, os.Symlink(filepath.Join(tmpdir, "missing"), filepath.Join(tmpdir, "broken.py")))
+	_, scanErrors, err := scanner.ScanDirectoryStream(context.Background(), tmpdir, nil, func(string, []Match) {})
+	assert.NoError(t, err)
+	if assert.Len(t, scanErrors, 1) {
+		assert.Equal(t, filepath.Join(tmpdir, "broken.py"), scanErrors[0].Path)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = scanner.ScanDirectoryStream(ctx, tmpdir, nil, func(string, []Match) {})
+	assert.Equal(t, context.Canceled, err)
+}
+
+// 测试签名自身的置信度阈值优先于全局阈值
+func TestScanFileSignatureMinConfidence(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "min-confidence")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	filePath := filepath.Join(tmpdir, "test.js")
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte("console.log(x)"), 0644))
+
+	detector := &fixedDetector{matches: []Match{
+		{Signature: Signature{ID: "JS011", Severity: "low", MinConfidence: 0.9}, Confidence: 0.85, LineNumber: 1},
+		{Signature: Signature{ID: "JS003", Severity: "high", MinConfidence: 0.5}, Confidence: 0.6, LineNumber: 1},
+		{Signature: Signature{ID: "JS001", Severity: "high"}, Confidence: 0.6, LineNumber: 1},
+	}}
+	scanner := NewScanner()
+	scanner.RegisterDetector(detector)
+	scanner.SetConfidenceThreshold(0.7)
+
+	matches, err := scanner.ScanFile(filePath)
+	assert.NoError(t, err)
+	ids := []string{}
+	for _, match := range matches {
+		ids = append(ids, match.Signature.ID)
+	}
+	assert.Equal(t, []string{"JS003"}, ids)
+}
+
+// 测试记录匹配行前后的源代码，包括文件开头和结尾附近的匹配
+func TestScanFileContextLines(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "context")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	filePath := filepath.Join(tmpdir, "test.py")
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte("line1\nline2\nline3\nline4\nline5\n"), 0644))
+
+	detector := &fixedDetector{}
+	for _, line := range []int{1, 3, 5, 9} {
+		detector.matches = append(detector.matches, Match{
+			Signature:  Signature{ID: fmt.Sprintf("L%d", line), Severity: "high"},
+			LineNumber: line,
+			Confidence: 0.9,
+		})
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(detector)
+
+	// 默认不记录上下文
+	matches, err := scanner.ScanFile(filePath)
+	assert.NoError(t, err)
+	assert.Nil(t, matches[1].ContextBefore)
+
+	scanner.SetContextLines(2)
+	matches, err = scanner.ScanFile(filePath)
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 4) {
+		assert.Equal(t, []string{}, matches[0].ContextBefore)
+		assert.Equal(t, []string{"line2", "line3"}, matches[0].ContextAfter)
+		assert.Equal(t, []string{"line1", "line2"}, matches[1].ContextBefore)
+		assert.Equal(t, []string{"line4", "line5"}, matches[1].ContextAfter)
+		assert.Equal(t, []string{"line3", "line4"}, matches[2].ContextBefore)
+		assert.Equal(t, []string{}, matches[2].ContextAfter)
+
+		// 超出文件范围的行号不会导致panic
+		assert.Nil(t, matches[3].ContextBefore)
+		assert.Nil(t, matches[3].ContextAfter)
+	}
+}
+
+// 按文件名支持文件的模拟检测器
+type filenameDetector struct {
+	mockDetector
+}
+
+func (d *filenameDetector) SupportedFilenames() []string {
+	return []string{"Dockerfile", "Dockerfile.*"}
+}
+
+// 测试扫描目录时按检测器提供的文件名匹配没有扩展名的文件
+func TestScanDirectorySupportedFilenames(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "filenames")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	for _, file := range []string{"Dockerfile", "dockerfile", "Dockerfile.prod", "Makefile", "README"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, file), []byte("FROM alpine"), 0644))
+	}
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	results, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	scanner.RegisterDetector(&filenameDetector{})
+	results, err = scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Contains(t, results, filepath.Join(tmpdir, "Dockerfile"))
+	assert.Contains(t, results, filepath.Join(tmpdir, "dockerfile"))
+	assert.Contains(t, results, filepath.Join(tmpdir, "Dockerfile.prod"))
+}
+
+// 记录同时打开的文件数的检测器
+type concurrencyDetector struct {
+	mockDetector
+	open    int32
+	maxOpen int32
+}
+
+func (d *concurrencyDetector) DetectFile(filePath string) ([]Match, error) {
+	open := atomic.AddInt32(&d.open, 1)
+	defer atomic.AddInt32(&d.open, -1)
+	for {
+		current := atomic.LoadInt32(&d.maxOpen)
+		if open <= current || atomic.CompareAndSwapInt32(&d.maxOpen, current, open) {
+			break
+		}
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	time.Sleep(time.Millisecond)
+
+	return d.mockDetector.DetectFile(filePath)
+}
+
+// 测试并行扫描时同时打开的文件数不超过工作协程数
+func TestScanDirectoryWorkerLimit(t *testing.T) {
+	tmpdir := createBenchmarkTree(t, 500)
+	defer os.RemoveAll(tmpdir)
+
+	for _, workers := range []int{1, 4} {
+		detector := &concurrencyDetector{}
+		scanner := NewScanner()
+		scanner.RegisterDetector(detector)
+		scanner.SetParallel(true)
+		scanner.SetWorkers(workers)
+
+		results, err := scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+		assert.Len(t, results, 500)
+		assert.LessOrEqual(t, int(detector.maxOpen), workers)
+		assert.Equal(t, int32(0), detector.open)
+	}
+}
+
+// 测试顺序和并行扫描目录时报告每个文件的进度
+func TestScanDirectoryProgress(t *testing.T) {
+	tmpdir := createBenchmarkTree(t, 50)
+	defer os.RemoveAll(tmpdir)
+
+	for _, parallel := range []bool{false, true} {
+		scanner := NewScanner()
+		scanner.RegisterDetector(&mockDetector{})
+		scanner.SetParallel(parallel)
+		scanner.SetWorkers(4)
+
+		// 并行扫描时回调可能并发调用，每个进度值只报告一次
+		var mutex sync.Mutex
+		var calls int
+		counts := make(map[int]bool)
+		files := make(map[string]bool)
+		scanner.SetProgressFunc(func(scanned, total int, currentFile string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			calls++
+			assert.True(t, scanned <= total)
+			counts[scanned] = true
+			files[currentFile] = true
+		})
+
+		_, err := scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 50, calls, "parallel=%v", parallel)
+		assert.Len(t, counts, 50, "parallel=%v", parallel)
+		assert.True(t, counts[1] && counts[50])
+		assert.Len(t, files, 50, "parallel=%v", parallel)
+
+		// 未设置回调时正常扫描
+		scanner.SetProgressFunc(nil)
+		_, err = scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 50, calls)
+	}
+}
+
+// 测试阻塞的进度回调不会阻塞其他扫描工作线程
+func TestScanDirectoryProgressSlowCallback(t *testing.T) {
+	tmpdir := createBenchmarkTree(t, 10)
+	defer os.RemoveAll(tmpdir)
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&mockDetector{})
+	scanner.SetParallel(true)
+	scanner.SetWorkers(2)
+
+	// 第一次回调一直阻塞，直到其他文件都扫描完成
+	release := make(chan struct{})
+	var calls int32
+	scanner.SetProgressFunc(func(scanned, total int, currentFile string) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			select {
+			case <-release:
+			case <-time.After(5 * time.Second):
+				t.Error("progress callback blocked the other workers")
+			}
+		case 10:
+			close(release)
+		}
+	})
+
+	_, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultFileTimeout is the default time a single file may take to scan
+const DefaultFileTimeout = 30 * time.Second
+
+// ErrFileTimeout is returned by ScanFile for a file whose scan took longer than
+// the file timeout
+var ErrFileTimeout = errors.New("scan exceeded the file timeout")
+
+// SetFileTimeout sets the time a single file may take to scan. A file that takes
+// longer is abandoned and ScanFile returns ErrFileTimeout, so that one file, e.g. one
+// that makes a detector backtrack, cannot hang a whole scan. A timeout of 0 or less
+// disables the limit.
+func (s *Scanner) SetFileTimeout(timeout time.Duration) {
+	s.fileTimeout = timeout
+}
+
+// FileTimeout returns the time a single file may take to scan, or 0 if it is unlimited
+func (s *Scanner) FileTimeout() time.Duration {
+	return s.fileTimeout
+}
+
+// scanFileWithTimeout scans a file like scanFile, giving up after the file timeout.
+// Detectors cannot be interrupted, so the scan of an abandoned file keeps running in
+// the background; its result is discarded.
+func (s *Scanner) scanFileWithTimeout(filePath string) ([]Match, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.fileTimeout)
+	defer cancel()
+
+	type result struct {
+		matches []Match
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		matches, err := s.scanFile(filePath)
+		done <- result{matches, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.matches, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w of %s", ErrFileTimeout, s.fileTimeout)
+	}
+}
+
+// logScanError reports a file that could not be scanned. Files abandoned after the
+// file timeout are only a warning; the scan continues either way.
+func logScanError(filePath string, err error) {
+	if errors.Is(err, ErrFileTimeout) {
+		fmt.Fprintf(os.Stderr, "Warning: Abandoned %s: %v\n", filePath, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error scanning file %s: %v\n", filePath, err)
+}
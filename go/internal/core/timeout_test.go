@@ -0,0 +1,70 @@
+package core
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hangingDetector 在文件内容包含hang时一直阻塞，模拟病态正则在特殊文件上卡住
+type hangingDetector struct {
+	mockDetector
+	release chan struct{}
+}
+
+func (d *hangingDetector) DetectFile(filePath string) ([]Match, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(content), "hang") {
+		<-d.release
+	}
+	return d.mockDetector.DetectFile(filePath)
+}
+
+// 测试超过单文件时限的文件被放弃，扫描继续处理其他文件
+func TestScanFileTimeout(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "file-timeout")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	hangPath := filepath.Join(tmpdir, "hang.py")
+	okPath := filepath.Join(tmpdir, "ok.py")
+	assert.NoError(t, ioutil.WriteFile(hangPath, []byte("eval(input())  # hang\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(okPath, []byte("eval(input())\n"), 0644))
+
+	detector := &hangingDetector{release: make(chan struct{})}
+	defer close(detector.release)
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(detector)
+	scanner.SetFileTimeout(50 * time.Millisecond)
+	assert.Equal(t, 50*time.Millisecond, scanner.FileTimeout())
+
+	start := time.Now()
+	_, err = scanner.ScanFile(hangPath)
+	assert.True(t, errors.Is(err, ErrFileTimeout))
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+
+	for _, parallel := range []bool{false, true} {
+		scanner.SetParallel(parallel)
+		results, err := scanner.ScanDirectory(tmpdir, nil)
+		assert.NoError(t, err)
+		assert.NotContains(t, results, hangPath)
+		assert.Contains(t, results, okPath)
+		assert.Equal(t, 1, scanner.LastScanStats().FilesFailed)
+	}
+
+	// 时限为0时不限制扫描时间
+	scanner.SetFileTimeout(0)
+	matches, err := scanner.ScanFile(okPath)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, matches)
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TruncatedSignature is the signature of the marker match that ScanFile appends to the
+// matches of a file with more findings than the maximum per file
+var TruncatedSignature = Signature{
+	ID:          "TRUNCATED",
+	Name:        "Findings truncated",
+	Severity:    "low",
+	Description: "The file has more findings than the maximum per file; the least severe were not reported",
+}
+
+// SetMaxFindingsPerFile sets the maximum number of matches reported per file, so that
+// one file, e.g. a generated bundle, cannot blow up a report. Matches of files with
+// more are cut to the most severe, followed by a TruncatedSignature marker match that
+// records how many were dropped. A maximum of 0 or less disables the limit.
+func (s *Scanner) SetMaxFindingsPerFile(max int) {
+	s.maxFindingsPerFile = max
+}
+
+// MaxFindingsPerFile returns the maximum number of matches reported per file, or 0 if
+// it is unlimited
+func (s *Scanner) MaxFindingsPerFile() int {
+	return s.maxFindingsPerFile
+}
+
+// truncateMatches cuts the matches of a file to the maximum per file, keeping the most
+// severe, and earliest among equally severe ones, in line order. The marker match is
+// reported at the first line of the dropped matches.
+func (s *Scanner) truncateMatches(filePath string, matches []Match) []Match {
+	if s.maxFindingsPerFile <= 0 || len(matches) <= s.maxFindingsPerFile {
+		return matches
+	}
+
+	sorted := append([]Match{}, matches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri := severityRanks[NormalizeSeverity(sorted[i].Signature.Severity)]
+		rj := severityRanks[NormalizeSeverity(sorted[j].Signature.Severity)]
+		if ri != rj {
+			return ri > rj
+		}
+		return sorted[i].LineNumber < sorted[j].LineNumber
+	})
+
+	kept := sorted[:s.maxFindingsPerFile]
+	dropped := sorted[s.maxFindingsPerFile:]
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].LineNumber < kept[j].LineNumber
+	})
+
+	firstLine := dropped[0].LineNumber
+	for _, match := range dropped {
+		if match.LineNumber < firstLine {
+			firstLine = match.LineNumber
+		}
+	}
+
+	return append(kept, Match{
+		Signature:   TruncatedSignature,
+		FilePath:    filePath,
+		LineNumber:  firstLine,
+		MatchedCode: fmt.Sprintf("%d findings truncated", len(dropped)),
+		Confidence:  1,
+	})
+}
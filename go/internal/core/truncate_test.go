@@ -0,0 +1,103 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// severityDetector 对包含log的行报告低危问题，对包含eval的行报告高危问题
+type severityDetector struct {
+	mockDetector
+}
+
+func (d *severityDetector) DetectFile(filePath string) ([]Match, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []Match{}
+	for i, line := range strings.Split(string(content), "\n") {
+		signature := Signature{}
+		switch {
+		case strings.Contains(line, "eval"):
+			signature = Signature{ID: "HIGH", Severity: "high"}
+		case strings.Contains(line, "log"):
+			signature = Signature{ID: "LOW", Severity: "low"}
+		default:
+			continue
+		}
+		matches = append(matches, Match{Signature: signature, FilePath: filePath, LineNumber: i + 1, Confidence: 0.9})
+	}
+	return matches, nil
+}
+
+// 测试超过单文件问题上限时只保留最严重的问题，并追加截断标记
+func TestScanFileMaxFindingsPerFile(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "max-findings")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	// 10行低危问题之后是一行高危问题
+	filePath := filepath.Join(tmpdir, "bundle.py")
+	content := strings.Repeat("console.log(x)\n", 10) + "eval(input())\n"
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte(content), 0644))
+
+	scanner := NewScanner()
+	scanner.RegisterDetector(&severityDetector{})
+
+	// 默认不限制
+	assert.Equal(t, 0, scanner.MaxFindingsPerFile())
+	matches, err := scanner.ScanFile(filePath)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 11)
+
+	scanner.SetMaxFindingsPerFile(3)
+	matches, err = scanner.ScanFile(filePath)
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 4) {
+		lines := []int{}
+		for _, match := range matches[:3] {
+			lines = append(lines, match.LineNumber)
+		}
+		assert.Equal(t, []int{1, 2, 11}, lines)
+		assert.Equal(t, "HIGH", matches[2].Signature.ID)
+
+		marker := matches[3]
+		assert.Equal(t, TruncatedSignature.ID, marker.Signature.ID)
+		assert.Equal(t, "8 findings truncated", marker.MatchedCode)
+		assert.Equal(t, 3, marker.LineNumber)
+		assert.Equal(t, filePath, marker.FilePath)
+	}
+
+	// 目录扫描同样截断
+	results, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results[filePath], 4)
+
+	// 截断标记不计入摘要
+	summary := GenerateSummary(results)
+	assert.Equal(t, 3, summary.Total())
+	assert.Equal(t, 2, summary.Low)
+	assert.Equal(t, 3, summary.ByFile[filePath])
+	assert.Zero(t, summary.Vulnerabilities[TruncatedSignature.Name])
+
+	// 未超过上限的文件不受影响
+	scanner.SetMaxFindingsPerFile(11)
+	matches, err = scanner.ScanFile(filePath)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 11)
+
+	// 配置中的上限不能为负数
+	config := NewConfig()
+	config.Scanner.MaxFindingsPerFile = -1
+	assert.Error(t, config.Validate())
+	config.Scanner.MaxFindingsPerFile = 5
+	assert.NoError(t, config.ApplyToScanner(scanner))
+	assert.Equal(t, 5, scanner.MaxFindingsPerFile())
+}
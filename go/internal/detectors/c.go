@@ -0,0 +1,286 @@
+package detectors
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// CDetector is a detector for memory-safety and injection issues in C and C++ code
+type CDetector struct {
+	core.BaseDetector
+	signatures []core.Signature
+}
+
+// NewCDetector creates a new C/C++ detector
+func NewCDetector() *CDetector {
+	detector := &CDetector{}
+	detector.loadSignatures()
+	return detector
+}
+
+// Name returns the name of the detector
+func (d *CDetector) Name() string {
+	return "c"
+}
+
+// SupportedLanguages returns the list of supported languages
+func (d *CDetector) SupportedLanguages() []string {
+	return []string{"c", "cpp", "cc", "h"}
+}
+
+// Signatures returns every signature the detector can report
+func (d *CDetector) Signatures() []core.Signature {
+	return d.signatures
+}
+
+// DetectFile detects vulnerabilities in a file
+func (d *CDetector) DetectFile(filePath string) ([]core.Match, error) {
+	// Check if file is a C or C++ file
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext != ".c" && ext != ".cpp" && ext != ".cc" && ext != ".h" {
+		return nil, nil
+	}
+
+	// Read file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectCode(string(content), filePath)
+}
+
+// cFormatArg is the index of the format string argument of the printf-like functions
+var cFormatArg = map[string]int{
+	"printf":    0,
+	"vprintf":   0,
+	"fprintf":   1,
+	"vfprintf":  1,
+	"dprintf":   1,
+	"sprintf":   1,
+	"vsprintf":  1,
+	"syslog":    1,
+	"snprintf":  2,
+	"vsnprintf": 2,
+}
+
+var (
+	cCallRe     = regexp.MustCompile(`(^|[^\w.>])([A-Za-z_]\w*)\s*\(`)
+	cConstantRe = regexp.MustCompile(`^([0-9][\w.]*|[A-Z][A-Z0-9_]*|sizeof\b.*)$`)
+	cLiteralRe  = regexp.MustCompile(`^(u8|[uUL])?["']`)
+)
+
+// DetectReader detects vulnerabilities in code read from r
+func (d *CDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return core.ReadAndDetect(d, r, filePath)
+}
+
+// DetectCode detects vulnerabilities in code
+func (d *CDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches := []core.Match{}
+
+	inComment := false
+	for i, line := range strings.Split(code, "\n") {
+		lineNumber := i + 1
+
+		// Skip comments
+		trimmed := strings.TrimSpace(line)
+		if inComment {
+			if strings.Contains(trimmed, "*/") {
+				inComment = false
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "/*") {
+			inComment = !strings.Contains(trimmed, "*/")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		for _, loc := range cCallRe.FindAllStringSubmatchIndex(line, -1) {
+			name := line[loc[4]:loc[5]]
+			args := cCallArgs(line[loc[1]:])
+
+			switch name {
+			case "strcpy", "strcat", "wcscpy", "wcscat", "sprintf", "vsprintf":
+				// Copying a literal into the buffer has a known length
+				if len(args) >= 2 {
+					matches = append(matches, d.newMatch(0, filePath, lineNumber, line, isCLiteral(args[1]) && !strings.Contains(args[1], "%s")))
+				}
+			case "gets":
+				matches = append(matches, d.newMatch(1, filePath, lineNumber, line, false))
+			case "system", "popen":
+				// A literal command cannot be injected into
+				if len(args) >= 1 {
+					matches = append(matches, d.newMatch(2, filePath, lineNumber, line, isCLiteral(args[0])))
+				}
+			case "memcpy", "memmove":
+				// Lengths from sizeof or constants are bounded by the code
+				if len(args) == 3 && !cConstantRe.MatchString(args[2]) {
+					matches = append(matches, d.newMatch(3, filePath, lineNumber, line, isCLiteral(args[1])))
+				}
+			}
+
+			// A format string that is not a literal or a constant can contain conversions
+			if index, ok := cFormatArg[name]; ok && len(args) > index {
+				format := args[index]
+				if !isCLiteral(format) && !cConstantRe.MatchString(format) {
+					matches = append(matches, d.newMatch(4, filePath, lineNumber, line, false))
+				}
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// loadSignatures loads the signatures for C and C++ code
+func (d *CDetector) loadSignatures() {
+	d.signatures = []core.Signature{
+		{
+			ID:          "C001",
+			Name:        "Unbounded string copy",
+			Severity:    "high",
+			Description: "strcpy, strcat and sprintf do not check the size of the destination buffer and can overflow it; use strncpy, strncat or snprintf",
+			CodePatterns: []string{
+				`\b(strcpy|strcat|wcscpy|wcscat|v?sprintf)\s*\(`,
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/120.html",
+			},
+		},
+		{
+			ID:          "C002",
+			Name:        "Use of gets()",
+			Severity:    "high",
+			Description: "gets() cannot limit the length of its input and always allows a buffer overflow; use fgets()",
+			CodePatterns: []string{
+				`\bgets\s*\(`,
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/242.html",
+			},
+		},
+		{
+			ID:          "C003",
+			Name:        "Command execution",
+			Severity:    "high",
+			Description: "system() and popen() run their argument through the shell, so commands built from input allow command injection; use the exec family",
+			CodePatterns: []string{
+				`\b(system|popen)\s*\(`,
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/78.html",
+			},
+		},
+		{
+			ID:          "C004",
+			Name:        "memcpy with unchecked length",
+			Severity:    "medium",
+			Description: "Copying a length that does not come from sizeof or a constant overflows the destination if the length is attacker-controlled; check it against the destination size",
+			CodePatterns: []string{
+				`\b(memcpy|memmove)\s*\(`,
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/805.html",
+			},
+		},
+		{
+			ID:          "C005",
+			Name:        "Format string vulnerability",
+			Severity:    "high",
+			Description: "Passing a variable as the format string of printf-like functions lets input read or write memory with %x and %n; use a literal format such as printf(\"%s\", s)",
+			CodePatterns: []string{
+				`\b(v?f?printf|dprintf|v?sn?printf|syslog)\s*\(`,
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/134.html",
+			},
+		},
+	}
+}
+
+// calculateConfidence calculates the confidence of a match and the factors it is made of
+func (d *CDetector) calculateConfidence(literal bool) (float64, []core.ConfidenceFactor) {
+	// Base confidence
+	factors := confidenceFactors{{Reason: "base confidence", Value: 0.85}}
+
+	// Literal arguments have a known length and content
+	if literal {
+		factors.add("argument is a string literal", -0.35)
+	}
+
+	// Ensure confidence is between 0 and 1
+	confidence := factors.total()
+
+	return confidence, factors
+}
+
+// newMatch creates a match of the signature at index for a line
+func (d *CDetector) newMatch(index int, filePath string, lineNumber int, line string, literal bool) core.Match {
+	signature := d.signatures[index]
+	confidence, factors := d.calculateConfidence(literal)
+	return core.Match{
+		Signature:   signature,
+		FilePath:    filePath,
+		LineNumber:  lineNumber,
+		MatchedCode: strings.TrimSpace(line),
+		Confidence:  confidence,
+		Explanation: &core.Explanation{
+			Pattern: signature.CodePatterns[0],
+			Factors: factors,
+		},
+	}
+}
+
+// cCallArgs splits the arguments of a call, given the code after its opening
+// parenthesis, at the top-level commas. Arguments are trimmed; calls that do not
+// end on the same line return the arguments seen so far.
+func cCallArgs(code string) []string {
+	args := []string{}
+	depth := 0
+	start := 0
+	var quote byte
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			if depth == 0 {
+				if arg := strings.TrimSpace(code[start:i]); arg != "" || len(args) > 0 {
+					args = append(args, arg)
+				}
+				return args
+			}
+			depth--
+		case c == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(code[start:i]))
+			start = i + 1
+		}
+	}
+	if arg := strings.TrimSpace(code[start:]); arg != "" {
+		args = append(args, arg)
+	}
+	return args
+}
+
+// isCLiteral reports whether an argument is a string or character literal
+func isCLiteral(arg string) bool {
+	return cLiteralRe.MatchString(arg)
+}
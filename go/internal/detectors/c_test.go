@@ -0,0 +1,76 @@
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试C/C++内存安全规则
+func TestCDetector(t *testing.T) {
+	detector := NewCDetector()
+
+	code := `#include <stdio.h>
+
+void handle(char *input, size_t len) {
+    char buf[64];
+    strcpy(buf, input);
+    gets(buf);
+    system(input);
+    memcpy(buf, input, len);
+    printf(input);
+    fprintf(stderr, input);
+}
+`
+	matches, err := detector.DetectCode(code, "handler.c")
+	assert.NoError(t, err)
+
+	for id, line := range map[string]int{"C001": 5, "C002": 6, "C003": 7, "C004": 8, "C005": 9} {
+		match := findSignature(matches, id)
+		if assert.NotNil(t, match, id) {
+			assert.Equal(t, line, match.LineNumber, id)
+			assert.Equal(t, 0.85, match.Confidence, id)
+		}
+	}
+
+	count := 0
+	for _, m := range matches {
+		if m.Signature.ID == "C005" {
+			count++
+		}
+	}
+	assert.Equal(t, 2, count)
+}
+
+// 测试字面量参数降低置信度，安全用法不触发
+func TestCDetectorLiterals(t *testing.T) {
+	detector := NewCDetector()
+
+	matches, err := detector.DetectCode(`strcpy(buf, "default");`, "main.cpp")
+	assert.NoError(t, err)
+	match := findSignature(matches, "C001")
+	if assert.NotNil(t, match) {
+		assert.InDelta(t, 0.5, match.Confidence, 0.0001)
+	}
+
+	matches, err = detector.DetectCode(`std::system("ls -l");`, "main.cc")
+	assert.NoError(t, err)
+	match = findSignature(matches, "C003")
+	if assert.NotNil(t, match) {
+		assert.InDelta(t, 0.5, match.Confidence, 0.0001)
+	}
+
+	for _, safe := range []string{
+		`printf("%s\n", input);`,
+		`snprintf(buf, sizeof(buf), "%d", n);`,
+		`memcpy(&hdr, data, sizeof(hdr));`,
+		`memcpy(buf, data, HEADER_SIZE);`,
+		`// strcpy(buf, input);`,
+		`obj.system(cmd);`,
+		`fgets(buf, sizeof(buf), stdin);`,
+	} {
+		matches, err = detector.DetectCode(safe, "main.c")
+		assert.NoError(t, err)
+		assert.Empty(t, matches, safe)
+	}
+}
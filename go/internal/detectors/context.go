@@ -0,0 +1,106 @@
+package detectors
+
+import (
+	"regexp"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// contextLines is the number of lines before and after a match whose code adjusts
+// the match's confidence
+const contextLines = 3
+
+var (
+	// literalArgRe matches a call whose only argument is a string or number literal
+	literalArgRe = regexp.MustCompile("\\(\\s*([rRbBuUfF]{0,2}'[^']*'|[rRbBuUfF]{0,2}\"[^\"]*\"|`[^`$]*`|-?\\d+(\\.\\d+)?)\\s*\\)")
+
+	// inputNameRe matches names of variables that usually hold request or user input,
+	// on their own, as part of a snake_case name or at the end of a camelCase name
+	inputNameRe = regexp.MustCompile(`(^|[^A-Za-z0-9])(req|request|input|argv|params)([^a-z0-9]|$)|[a-z](Req|Request|Input|Argv|Params)([^a-z0-9]|$)`)
+)
+
+// addContext adjusts the confidence of a match by its arguments and the code around
+// it: a literal argument cannot be controlled by an attacker, while request or input
+// variables nearby make it likely that one can
+func (f *confidenceFactors) addContext(matchedCode string, context []string) {
+	if literalArgRe.MatchString(matchedCode) {
+		f.add("argument is a literal", -0.25)
+		return
+	}
+
+	for _, line := range append([]string{matchedCode}, context...) {
+		if inputNameRe.MatchString(line) {
+			f.add("request or input variable nearby", 0.1)
+			return
+		}
+	}
+}
+
+// contextBuffer holds the matches of a line until the lines after it have been read,
+// so that the confidence of matches found while streaming a file can depend on the
+// lines around them
+type contextBuffer struct {
+	calculateConfidence func(matchedCode string, pattern string, context []string) (float64, []core.ConfidenceFactor)
+	before              []string
+	pending             []pendingMatch
+	matches             []core.Match
+}
+
+// pendingMatch is a match waiting for the lines after it
+type pendingMatch struct {
+	match   core.Match
+	context []string
+	after   int
+}
+
+// newContextBuffer creates a buffer that scores matches with calculateConfidence
+func newContextBuffer(calculateConfidence func(matchedCode string, pattern string, context []string) (float64, []core.ConfidenceFactor)) *contextBuffer {
+	return &contextBuffer{calculateConfidence: calculateConfidence}
+}
+
+// add records a line and the matches found on it. The match's explanation must
+// record its pattern.
+func (b *contextBuffer) add(line string, matches []core.Match) {
+	// The line follows the pending matches
+	remaining := b.pending[:0]
+	for _, p := range b.pending {
+		p.context = append(p.context, line)
+		p.after++
+		if p.after == contextLines {
+			b.score(p)
+		} else {
+			remaining = append(remaining, p)
+		}
+	}
+	b.pending = remaining
+
+	for _, match := range matches {
+		b.pending = append(b.pending, pendingMatch{
+			match:   match,
+			context: append([]string{}, b.before...),
+		})
+	}
+
+	b.before = append(b.before, line)
+	if len(b.before) > contextLines {
+		b.before = b.before[1:]
+	}
+}
+
+// close scores the remaining matches and returns all matches in line order
+func (b *contextBuffer) close() []core.Match {
+	for _, p := range b.pending {
+		b.score(p)
+	}
+	b.pending = nil
+	return b.matches
+}
+
+// score calculates the confidence of a match from its context
+func (b *contextBuffer) score(p pendingMatch) {
+	match := p.match
+	confidence, factors := b.calculateConfidence(match.MatchedCode, match.Explanation.Pattern, p.context)
+	match.Confidence = confidence
+	match.Explanation.Factors = factors
+	b.matches = append(b.matches, match)
+}
@@ -0,0 +1,61 @@
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 检测代码并返回指定规则第一个匹配的置信度
+func confidenceOf(t *testing.T, detector core.Detector, code string, filePath string, id string) float64 {
+	matches, err := detector.DetectCode(code, filePath)
+	assert.NoError(t, err)
+	match := findSignature(matches, id)
+	if !assert.NotNil(t, match, code) {
+		return 0
+	}
+	return match.Confidence
+}
+
+// 测试字面量参数的置信度低于变量参数，附近有请求或输入变量时置信度更高
+func TestContextConfidence(t *testing.T) {
+	tests := []struct {
+		detector core.Detector
+		filePath string
+		id       string
+		literal  string
+		variable string
+		input    string
+	}{
+		{NewPythonDetector(), "app.py", "PY001", `result = eval("1 + 1")`, "result = eval(expression)", "result = eval(expression)\nprint(request.args)"},
+		{NewJavaScriptDetector(), "app.js", "JS001", "const result = eval('1 + 1');", "const result = eval(expression);", "const expression = req.query.expr;\nconst result = eval(expression);"},
+	}
+
+	for _, tt := range tests {
+		literal := confidenceOf(t, tt.detector, tt.literal, tt.filePath, tt.id)
+		variable := confidenceOf(t, tt.detector, tt.variable, tt.filePath, tt.id)
+		input := confidenceOf(t, tt.detector, tt.input, tt.filePath, tt.id)
+		assert.Less(t, literal, variable, tt.filePath)
+		assert.Less(t, variable, input, tt.filePath)
+	}
+}
+
+// 测试只有匹配行前后contextLines行内的输入变量会提高置信度
+func TestContextConfidenceDistance(t *testing.T) {
+	detector := NewPythonDetector()
+
+	near := confidenceOf(t, detector, "result = eval(expression)\n\n\nvalue = sys.argv[1]", "app.py", "PY001")
+	far := confidenceOf(t, detector, "result = eval(expression)\n\n\n\nvalue = sys.argv[1]", "app.py", "PY001")
+	assert.Greater(t, near, far)
+
+	// 蛇形和驼峰命名中的输入变量
+	for _, code := range []string{"eval(user_input)", "eval(userInput)", "eval(params['x'])"} {
+		assert.Greater(t, confidenceOf(t, detector, code, "app.py", "PY001"), far, code)
+	}
+	// 名称中只是包含input等单词的变量不算
+	for _, code := range []string{"eval(inputs)", "eval(requests_sent)"} {
+		assert.Equal(t, far, confidenceOf(t, detector, code, "app.py", "PY001"), code)
+	}
+}
@@ -0,0 +1,330 @@
+package detectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// CustomSignature is a signature loaded from a signature file. Languages are the
+// extensions of the files it applies to, e.g. py or js. Override lets it replace a
+// signature with the same ID loaded from an earlier file.
+type CustomSignature struct {
+	core.Signature
+	Languages []string `json:"languages"`
+	Override  bool     `json:"override,omitempty"`
+}
+
+// signatureFile is the format of JSON and YAML signature files
+type signatureFile struct {
+	Signatures []CustomSignature `json:"signatures"`
+}
+
+// SignatureFileError is an error in one signature file
+type SignatureFileError struct {
+	File string
+	Err  error
+}
+
+// Error returns the file name and the error
+func (e *SignatureFileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+// SignatureDirError lists the errors of every invalid file in a signatures directory
+type SignatureDirError struct {
+	Errors []*SignatureFileError
+}
+
+// Error returns the errors of all files
+func (e *SignatureDirError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// CustomDetector is a detector for the signatures in user-provided signature files
+type CustomDetector struct {
+	core.BaseDetector
+	signatures []CustomSignature
+	patterns   map[string][]*pattern
+}
+
+// NewCustomDetector creates a detector without signatures
+func NewCustomDetector() *CustomDetector {
+	return &CustomDetector{
+		patterns: make(map[string][]*pattern),
+	}
+}
+
+// Name returns the name of the detector
+func (d *CustomDetector) Name() string {
+	return "custom"
+}
+
+// SupportedLanguages returns the languages of all loaded signatures
+func (d *CustomDetector) SupportedLanguages() []string {
+	seen := make(map[string]bool)
+	languages := []string{}
+	for _, signature := range d.signatures {
+		for _, language := range signature.Languages {
+			if !seen[language] {
+				seen[language] = true
+				languages = append(languages, language)
+			}
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Signatures returns every signature the detector can report
+func (d *CustomDetector) Signatures() []core.Signature {
+	signatures := make([]core.Signature, len(d.signatures))
+	for i, signature := range d.signatures {
+		signatures[i] = signature.Signature
+	}
+	return signatures
+}
+
+// LoadSignaturesDir loads every .json, .yaml and .yml signature file in a directory, in
+// order of their names, and merges their signatures. A signature whose ID is already
+// defined is an error unless it sets override, in which case it replaces the earlier
+// one. Invalid files are reported together in a SignatureDirError and none of their
+// signatures are loaded.
+func (d *CustomDetector) LoadSignaturesDir(dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	dirErr := &SignatureDirError{}
+	definedIn := make(map[string]string)
+	for _, signature := range d.signatures {
+		definedIn[signature.ID] = "an earlier file"
+	}
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		signatures, err := readSignatureFile(path)
+		if err == nil {
+			err = checkSignatureIDs(signatures, definedIn)
+		}
+		if err != nil {
+			dirErr.Errors = append(dirErr.Errors, &SignatureFileError{File: path, Err: err})
+			continue
+		}
+
+		for _, signature := range signatures {
+			d.addSignature(signature)
+			definedIn[signature.ID] = path
+		}
+	}
+
+	if len(dirErr.Errors) > 0 {
+		return dirErr
+	}
+	return nil
+}
+
+// readSignatureFile reads and validates the signatures in a JSON or YAML file
+func readSignatureFile(path string) ([]CustomSignature, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML files are decoded with the JSON field names by converting them to JSON
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %v", err)
+		}
+		data, err = json.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %v", err)
+		}
+	}
+
+	var file signatureFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid signature file: %v", err)
+	}
+
+	for i := range file.Signatures {
+		if err := validateSignature(&file.Signatures[i]); err != nil {
+			return nil, err
+		}
+	}
+	return file.Signatures, nil
+}
+
+// validateSignature checks the fields of a signature and normalizes its severity and languages
+func validateSignature(signature *CustomSignature) error {
+	if signature.ID == "" {
+		return fmt.Errorf("signature %q has no id", signature.Name)
+	}
+	if signature.Name == "" {
+		return fmt.Errorf("signature %s has no name", signature.ID)
+	}
+	// Severities are stored in their canonical form, e.g. CRITICAL as high
+	severity := core.NormalizeSeverity(signature.Severity)
+	if severity == "" {
+		return fmt.Errorf("signature %s has invalid severity %q (expected high, medium or low; critical and info are accepted as aliases)", signature.ID, signature.Severity)
+	}
+	signature.Severity = severity
+	if len(signature.CodePatterns) == 0 {
+		return fmt.Errorf("signature %s has no codePatterns", signature.ID)
+	}
+	for _, pattern := range signature.CodePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("signature %s has invalid pattern %q: %v", signature.ID, pattern, err)
+		}
+	}
+	if len(signature.Languages) == 0 {
+		return fmt.Errorf("signature %s has no languages", signature.ID)
+	}
+	for i, language := range signature.Languages {
+		signature.Languages[i] = strings.TrimPrefix(strings.ToLower(language), ".")
+	}
+	return nil
+}
+
+// checkSignatureIDs returns an error if a signature ID is defined twice in a file, or
+// was defined in an earlier file and the signature does not override it
+func checkSignatureIDs(signatures []CustomSignature, definedIn map[string]string) error {
+	inFile := make(map[string]bool)
+	for _, signature := range signatures {
+		if inFile[signature.ID] {
+			return fmt.Errorf("signature %s is defined twice", signature.ID)
+		}
+		inFile[signature.ID] = true
+
+		if previous, ok := definedIn[signature.ID]; ok && !signature.Override {
+			return fmt.Errorf("signature %s conflicts with the one in %s (set override: true to replace it)", signature.ID, previous)
+		}
+	}
+	return nil
+}
+
+// addSignature adds a signature, replacing the one with the same ID
+func (d *CustomDetector) addSignature(signature CustomSignature) {
+	// The patterns were validated when the file was read
+	var patterns []*pattern
+	for _, expr := range signature.CodePatterns {
+		compiled, _ := compilePattern(expr)
+		patterns = append(patterns, compiled)
+	}
+	d.patterns[signature.ID] = patterns
+
+	for i, existing := range d.signatures {
+		if existing.ID == signature.ID {
+			d.signatures[i] = signature
+			return
+		}
+	}
+	d.signatures = append(d.signatures, signature)
+}
+
+// DetectFile detects vulnerabilities in a file
+func (d *CustomDetector) DetectFile(filePath string) ([]core.Match, error) {
+	if len(d.signaturesFor(filePath)) == 0 {
+		return nil, nil
+	}
+
+	// Read file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectCode(string(content), filePath)
+}
+
+// DetectReader detects vulnerabilities in code read from r
+func (d *CustomDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return core.ReadAndDetect(d, r, filePath)
+}
+
+// DetectCode detects vulnerabilities in code
+func (d *CustomDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches := []core.Match{}
+	signatures := d.signaturesFor(filePath)
+	if len(signatures) == 0 {
+		return matches, nil
+	}
+
+	lines := strings.Split(code, "\n")
+	multiline := []core.Signature{}
+	for _, signature := range signatures {
+		if signature.Multiline {
+			multiline = append(multiline, signature.Signature)
+			continue
+		}
+
+		for i, line := range lines {
+			for j, re := range d.patterns[signature.ID] {
+				if re.MatchString(line) {
+					confidence, factors := d.calculateConfidence()
+					matches = append(matches, core.Match{
+						Signature:   signature.Signature,
+						FilePath:    filePath,
+						LineNumber:  i + 1,
+						MatchedCode: line,
+						Confidence:  confidence,
+						Explanation: &core.Explanation{
+							Pattern: signature.CodePatterns[j],
+							Factors: factors,
+						},
+					})
+					break
+				}
+			}
+		}
+	}
+
+	matches = append(matches, matchMultiline(multiline, code, filePath, func(matchedCode string, pattern string) (float64, []core.ConfidenceFactor) {
+		return d.calculateConfidence()
+	})...)
+
+	return matches, nil
+}
+
+// signaturesFor returns the signatures that apply to a file by its extension
+func (d *CustomDetector) signaturesFor(filePath string) []CustomSignature {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), ".")
+	signatures := []CustomSignature{}
+	for _, signature := range d.signatures {
+		for _, language := range signature.Languages {
+			if language == ext {
+				signatures = append(signatures, signature)
+				break
+			}
+		}
+	}
+	return signatures
+}
+
+// calculateConfidence calculates the confidence of a match. Custom signatures have
+// no detector-specific checks, so every match has the same confidence.
+func (d *CustomDetector) calculateConfidence() (float64, []core.ConfidenceFactor) {
+	factors := confidenceFactors{{Reason: "base confidence", Value: 0.8}}
+	factors.add("custom signature pattern matched", 0.1)
+	return factors.total(), factors
+}
@@ -0,0 +1,102 @@
+package detectors
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 创建包含指定签名文件的临时目录
+func createSignaturesDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "signatures-dir-test")
+	assert.NoError(t, err)
+	for name, content := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+// 测试合并目录中的JSON和YAML签名文件，后面的文件可以覆盖同ID的签名
+func TestLoadSignaturesDir(t *testing.T) {
+	dir := createSignaturesDir(t, map[string]string{
+		"01-base.json": `{"signatures": [
+			{"id": "ORG001", "name": "Internal debug endpoint", "severity": "low", "codePatterns": ["/debug/"], "languages": ["py", "js"]},
+			{"id": "ORG002", "name": "Legacy crypto helper", "severity": "medium", "codePatterns": ["legacy_encrypt\\("], "languages": ["py"]}
+		]}`,
+		"02-overrides.yaml": `signatures:
+  - id: ORG001
+    name: Internal debug endpoint
+    severity: high
+    category: exposure
+    codePatterns: ["/debug/"]
+    languages: [py]
+    override: true
+`,
+		"README.md": "not a signature file",
+	})
+	defer os.RemoveAll(dir)
+
+	detector := NewCustomDetector()
+	assert.NoError(t, detector.LoadSignaturesDir(dir))
+	assert.Equal(t, []string{"py"}, detector.SupportedLanguages())
+
+	signatures := detector.Signatures()
+	if assert.Len(t, signatures, 2) {
+		assert.Equal(t, "ORG001", signatures[0].ID)
+		assert.Equal(t, "high", signatures[0].Severity)
+		assert.Equal(t, "exposure", signatures[0].Category)
+	}
+
+	matches, err := detector.DetectCode("app.route('/debug/vars')\ndata = legacy_encrypt(value)\n", "app.py")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, signatureLines(matches, "ORG001"))
+	assert.Equal(t, []int{2}, signatureLines(matches, "ORG002"))
+
+	// 覆盖后的签名不再适用于JavaScript
+	matches, err = detector.DetectCode("app.get('/debug/vars')", "app.js")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+// 测试同ID签名没有override时冲突，并逐个文件报告错误
+func TestLoadSignaturesDirErrors(t *testing.T) {
+	dir := createSignaturesDir(t, map[string]string{
+		"a.json":       `{"signatures": [{"id": "ORG001", "name": "Debug endpoint", "severity": "low", "codePatterns": ["/debug/"], "languages": ["py"]}]}`,
+		"b.json":       `{"signatures": [{"id": "ORG001", "name": "Debug endpoint", "severity": "high", "codePatterns": ["/debug/"], "languages": ["py"]}]}`,
+		"c.yml":        "signatures: [",
+		"d.json":       `{"signatures": [{"id": "ORG003", "name": "Bad pattern", "severity": "low", "codePatterns": ["("], "languages": ["py"]}]}`,
+		"e.json":       `{"signatures": [{"id": "ORG004", "name": "Bad severity", "severity": "urgent", "codePatterns": ["x"], "languages": ["py"]}]}`,
+		"f-valid.json": `{"signatures": [{"id": "ORG005", "name": "Valid", "severity": "CRITICAL", "codePatterns": ["x"], "languages": [".PY"]}]}`,
+	})
+	defer os.RemoveAll(dir)
+
+	detector := NewCustomDetector()
+	err := detector.LoadSignaturesDir(dir)
+	dirErr, ok := err.(*SignatureDirError)
+	if !assert.True(t, ok, "%v", err) {
+		return
+	}
+
+	files := []string{}
+	for _, fileErr := range dirErr.Errors {
+		files = append(files, filepath.Base(fileErr.File))
+	}
+	assert.Equal(t, []string{"b.json", "c.yml", "d.json", "e.json"}, files)
+	assert.Contains(t, dirErr.Errors[0].Error(), "conflicts with the one in "+filepath.Join(dir, "a.json"))
+	assert.Contains(t, dirErr.Errors[2].Error(), "invalid pattern")
+	assert.Contains(t, dirErr.Errors[3].Error(), "invalid severity")
+
+	// 有效文件的签名仍被加载，语言统一为小写且不带点，严重程度统一为规范形式
+	assert.Equal(t, []string{"py"}, detector.SupportedLanguages())
+	assert.Len(t, detector.Signatures(), 2)
+	for _, signature := range detector.Signatures() {
+		if signature.ID == "ORG005" {
+			assert.Equal(t, "high", signature.Severity)
+		}
+	}
+
+	assert.Error(t, NewCustomDetector().LoadSignaturesDir(filepath.Join(dir, "missing")))
+}
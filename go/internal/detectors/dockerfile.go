@@ -0,0 +1,276 @@
+package detectors
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// DockerfileDetector is a detector for secrets and misconfigurations in Dockerfiles
+type DockerfileDetector struct {
+	signatures []core.Signature
+}
+
+// NewDockerfileDetector creates a new Dockerfile detector
+func NewDockerfileDetector() *DockerfileDetector {
+	detector := &DockerfileDetector{}
+	detector.loadSignatures()
+	return detector
+}
+
+// Name returns the name of the detector
+func (d *DockerfileDetector) Name() string {
+	return "dockerfile"
+}
+
+// SupportedLanguages returns the list of supported languages
+func (d *DockerfileDetector) SupportedLanguages() []string {
+	return []string{"dockerfile"}
+}
+
+// Signatures returns every signature the detector can report
+func (d *DockerfileDetector) Signatures() []core.Signature {
+	return d.signatures
+}
+
+// SupportedFilenames returns the names of the files supported regardless of their extension
+func (d *DockerfileDetector) SupportedFilenames() []string {
+	return []string{"Dockerfile", "Dockerfile.*"}
+}
+
+// DetectFile detects vulnerabilities in a file
+func (d *DockerfileDetector) DetectFile(filePath string) ([]core.Match, error) {
+	// Check if file is a Dockerfile
+	if !isDockerfile(filePath) {
+		return nil, nil
+	}
+
+	// Read file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectCode(string(content), filePath)
+}
+
+// DetectReader detects vulnerabilities in code read from r
+func (d *DockerfileDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return core.ReadAndDetect(d, r, filePath)
+}
+
+// DetectCode detects vulnerabilities in code
+func (d *DockerfileDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches := []core.Match{}
+	instructions := parseDockerfile(code)
+
+	for _, inst := range instructions {
+		switch inst.keyword {
+		case "ADD":
+			if dockerRemoteAddRe.MatchString(inst.args) {
+				matches = append(matches, d.newMatch(0, filePath, inst, 0.9))
+			}
+		case "ENV", "ARG":
+			if m := dockerSecretEnvRe.FindStringSubmatch(inst.args); m != nil {
+				// Do not repeat the secret in the report
+				match := d.newMatch(1, filePath, inst, 0.8)
+				match.MatchedCode = strings.Replace(match.MatchedCode, m[2], redactSecret(m[2]), 1)
+				matches = append(matches, match)
+			}
+		case "RUN":
+			if dockerPipeToShellRe.MatchString(inst.args) {
+				matches = append(matches, d.newMatch(3, filePath, inst, 0.9))
+			}
+			if hasUnpinnedAptPackages(inst.args) {
+				matches = append(matches, d.newMatch(4, filePath, inst, 0.7))
+			}
+		}
+	}
+
+	matches = append(matches, d.checkUser(instructions, filePath)...)
+
+	return matches, nil
+}
+
+// loadSignatures loads the signatures for Dockerfiles
+func (d *DockerfileDetector) loadSignatures() {
+	d.signatures = []core.Signature{
+		{
+			ID:          "DOCKER001",
+			Name:        "ADD of a remote URL",
+			Severity:    "medium",
+			Description: "ADD downloads remote files without verifying their integrity; download them with a checksum in a RUN step or use COPY",
+			References: []string{
+				"https://docs.docker.com/develop/develop-images/dockerfile_best-practices/#add-or-copy",
+			},
+		},
+		{
+			ID:          "DOCKER002",
+			Name:        "Secret in ENV or ARG",
+			Severity:    "high",
+			Category:    "secrets",
+			Description: "Values set with ENV or ARG are stored in the image and its history; pass secrets with build secrets or at runtime",
+			References: []string{
+				"https://docs.docker.com/build/building/secrets/",
+			},
+		},
+		{
+			ID:          "DOCKER003",
+			Name:        "Container runs as root",
+			Severity:    "medium",
+			Description: "The final stage runs as root, either explicitly or because it sets no USER, which gives an attacker who escapes the application full control of the container",
+			References: []string{
+				"https://docs.docker.com/develop/develop-images/dockerfile_best-practices/#user",
+			},
+		},
+		{
+			ID:          "DOCKER004",
+			Name:        "Remote script piped to a shell",
+			Severity:    "high",
+			Description: "Piping a downloaded script into a shell runs whatever the server returns without verification",
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/494.html",
+			},
+		},
+		{
+			ID:          "DOCKER005",
+			Name:        "apt-get install without pinned versions",
+			Severity:    "low",
+			Description: "Installing packages without pinned versions makes builds unreproducible and can silently pull in vulnerable releases",
+			References: []string{
+				"https://docs.docker.com/develop/develop-images/dockerfile_best-practices/#apt-get",
+			},
+		},
+	}
+}
+
+var (
+	dockerRemoteAddRe   = regexp.MustCompile(`(?i)^(--\S+\s+)*["\[]*\s*"?(https?|ftp)://`)
+	dockerSecretEnvRe   = regexp.MustCompile(`(?i)(?:^|\s)\w*(?:passw(?:or)?d|secret|token|api_?key|access_?key|private_?key|credentials?)\w*\s*[= ]\s*(["']?)([^\s"'$/][^\s"']*)`)
+	dockerPipeToShellRe = regexp.MustCompile(`(?i)\b(curl|wget)\b[^|;&]*\|\s*(sudo\s+)?(\S*/)?(ba|da|z|k)?sh\b`)
+	dockerAptInstallRe  = regexp.MustCompile(`\bapt-get\s+(-\S+\s+)*install\b`)
+	dockerRootUserRe    = regexp.MustCompile(`^(root|0)(:\S*)?$`)
+)
+
+// dockerInstruction is an instruction of a Dockerfile with its continuation lines joined
+type dockerInstruction struct {
+	keyword string
+	args    string
+	line    int    // line number of the first line of the instruction
+	text    string // first line of the instruction
+}
+
+// parseDockerfile splits a Dockerfile into instructions, skipping comments and
+// joining lines continued with a trailing backslash
+func parseDockerfile(code string) []dockerInstruction {
+	instructions := []dockerInstruction{}
+
+	var current *dockerInstruction
+	var args []string
+	for i, line := range strings.Split(code, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		continued := strings.HasSuffix(trimmed, "\\")
+		trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, "\\"))
+
+		if current == nil {
+			fields := strings.SplitN(trimmed, " ", 2)
+			current = &dockerInstruction{
+				keyword: strings.ToUpper(fields[0]),
+				line:    i + 1,
+				text:    strings.TrimSpace(line),
+			}
+			args = nil
+			if len(fields) == 2 {
+				trimmed = fields[1]
+			} else {
+				trimmed = ""
+			}
+		}
+		args = append(args, strings.TrimSpace(trimmed))
+
+		if !continued {
+			current.args = strings.TrimSpace(strings.Join(args, " "))
+			instructions = append(instructions, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		current.args = strings.TrimSpace(strings.Join(args, " "))
+		instructions = append(instructions, *current)
+	}
+
+	return instructions
+}
+
+// checkUser flags a final stage that runs as root, at its last USER instruction or,
+// if it has none, at its FROM instruction
+func (d *DockerfileDetector) checkUser(instructions []dockerInstruction, filePath string) []core.Match {
+	var from, user *dockerInstruction
+	for i := range instructions {
+		switch instructions[i].keyword {
+		case "FROM":
+			from, user = &instructions[i], nil
+		case "USER":
+			user = &instructions[i]
+		}
+	}
+
+	switch {
+	case user != nil:
+		if dockerRootUserRe.MatchString(strings.ToLower(user.args)) {
+			return []core.Match{d.newMatch(2, filePath, *user, 0.9)}
+		}
+	case from != nil:
+		// The base image may set a user of its own
+		return []core.Match{d.newMatch(2, filePath, *from, 0.6)}
+	}
+	return nil
+}
+
+// hasUnpinnedAptPackages reports whether a RUN command installs apt packages without
+// a version, i.e. without the package=version form
+func hasUnpinnedAptPackages(command string) bool {
+	for _, loc := range dockerAptInstallRe.FindAllStringIndex(command, -1) {
+		for _, arg := range strings.Fields(command[loc[1]:]) {
+			if arg == "&&" || arg == "||" || arg == ";" || arg == "|" {
+				break
+			}
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+			pkg := strings.TrimRight(arg, ";")
+			if pkg != "" && !strings.Contains(pkg, "=") {
+				return true
+			}
+			if strings.HasSuffix(arg, ";") {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// newMatch creates a match of the signature at index for an instruction
+func (d *DockerfileDetector) newMatch(index int, filePath string, inst dockerInstruction, confidence float64) core.Match {
+	return core.Match{
+		Signature:   d.signatures[index],
+		FilePath:    filePath,
+		LineNumber:  inst.line,
+		MatchedCode: inst.text,
+		Confidence:  confidence,
+	}
+}
+
+// isDockerfile reports whether a file is a Dockerfile by its name or extension
+func isDockerfile(filePath string) bool {
+	base := strings.ToLower(filepath.Base(filePath))
+	return base == "dockerfile" || strings.HasPrefix(base, "dockerfile.") || strings.HasSuffix(base, ".dockerfile")
+}
@@ -0,0 +1,136 @@
+package detectors
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试Dockerfile规则
+func TestDockerfileDetector(t *testing.T) {
+	detector := NewDockerfileDetector()
+
+	code := `FROM golang:1.17 AS build
+ARG GITHUB_TOKEN=ghp_abcdef1234567890
+ENV APP_ENV=production \
+    DB_PASSWORD=hunter2secret
+RUN curl -fsSL https://example.com/install.sh | sh
+RUN apt-get update && apt-get install -y \
+    curl=7.68.0-1 \
+    git
+ADD https://example.com/app.tar.gz /app/
+
+FROM alpine:3.14
+# 以root身份运行
+USER root
+`
+	matches, err := detector.DetectCode(code, "Dockerfile")
+	assert.NoError(t, err)
+
+	add := findSignature(matches, "DOCKER001")
+	if assert.NotNil(t, add) {
+		assert.Equal(t, 9, add.LineNumber)
+	}
+
+	count := 0
+	for _, m := range matches {
+		if m.Signature.ID == "DOCKER002" {
+			count++
+			assert.NotContains(t, m.MatchedCode, "ghp_abcdef1234567890")
+		}
+	}
+	assert.Equal(t, 2, count)
+
+	pipe := findSignature(matches, "DOCKER004")
+	if assert.NotNil(t, pipe) {
+		assert.Equal(t, 5, pipe.LineNumber)
+	}
+
+	apt := findSignature(matches, "DOCKER005")
+	if assert.NotNil(t, apt) {
+		assert.Equal(t, 6, apt.LineNumber)
+	}
+
+	root := findSignature(matches, "DOCKER003")
+	if assert.NotNil(t, root) {
+		assert.Equal(t, 13, root.LineNumber)
+	}
+}
+
+// 测试安全的Dockerfile不触发规则
+func TestDockerfileDetectorSafe(t *testing.T) {
+	detector := NewDockerfileDetector()
+
+	code := `FROM debian:bullseye AS build
+USER root
+ARG GITHUB_TOKEN
+ENV TOKEN_FILE=/run/secrets/token PASSWORD=$DB_PASSWORD
+RUN apt-get update && apt-get install -y --no-install-recommends curl=7.74.0-1.3 ca-certificates=20210119
+RUN curl -fsSL -o install.sh https://example.com/install.sh && sha256sum -c install.sh.sha256
+ADD app.tar.gz /app/
+
+FROM debian:bullseye-slim
+COPY --from=build /app /app
+USER app:app
+`
+	matches, err := detector.DetectCode(code, "Dockerfile")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+
+	// 最终阶段没有USER指令时以root身份运行
+	matches, err = detector.DetectCode("FROM alpine\nUSER app\nFROM alpine\nRUN echo hi\n", "Dockerfile")
+	assert.NoError(t, err)
+	root := findSignature(matches, "DOCKER003")
+	if assert.NotNil(t, root) {
+		assert.Equal(t, 3, root.LineNumber)
+	}
+}
+
+// 测试只检测Dockerfile文件
+func TestDockerfileDetectFile(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "dockerfile")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	detector := NewDockerfileDetector()
+	for file, want := range map[string]bool{
+		"Dockerfile":     true,
+		"Dockerfile.dev": true,
+		"app.dockerfile": true,
+		"main.py":        false,
+	} {
+		path := filepath.Join(tmpdir, file)
+		assert.NoError(t, ioutil.WriteFile(path, []byte("FROM alpine\nUSER 0\n"), 0644))
+		matches, err := detector.DetectFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, want, hasSignature(matches, "DOCKER003"), file)
+	}
+}
+
+// 测试扫描目录时选中没有扩展名的Dockerfile
+func TestDockerfileScanDirectory(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "dockerfile-scan")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpdir, "build"), 0755))
+	dockerfile := filepath.Join(tmpdir, "build", "Dockerfile")
+	assert.NoError(t, ioutil.WriteFile(dockerfile, []byte("FROM alpine\nUSER root\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "Makefile"), []byte("USER root\n"), 0644))
+
+	// 默认不按文件名选择文件的检测器
+	assert.Nil(t, NewPythonDetector().SupportedFilenames())
+
+	scanner := core.NewScanner()
+	scanner.RegisterDetector(NewPythonDetector())
+	scanner.RegisterDetector(NewDockerfileDetector())
+	results, err := scanner.ScanDirectory(tmpdir, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.True(t, hasSignature(results[dockerfile], "DOCKER003"))
+}
@@ -0,0 +1,150 @@
+package detectors
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+const (
+	// DefaultEntropyThreshold is the default Shannon entropy in bits per character
+	// above which a string literal is reported as a likely secret
+	DefaultEntropyThreshold = 4.0
+
+	// DefaultMinSecretLength is the default minimum length of a high-entropy literal
+	DefaultMinSecretLength = 20
+)
+
+// secretSignature is the signature reported for known key formats and high-entropy literals
+var secretSignature = core.Signature{
+	ID:          "SEC001",
+	Name:        "Hardcoded secret",
+	Severity:    "high",
+	Category:    "secrets",
+	Description: "A string that looks like an API key or token is hardcoded; secrets should be loaded from the environment or a secret manager",
+	References: []string{
+		"https://owasp.org/www-community/vulnerabilities/Use_of_hard-coded_credentials",
+	},
+}
+
+// knownKeyFormats are token formats that identify a provider regardless of the variable
+// name. AWS access key IDs have a rule of their own, SEC002.
+var knownKeyFormats = []struct {
+	provider string
+	re       *regexp.Regexp
+}{
+	{"gcp", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{"github", regexp.MustCompile(`\bgh[pousr]_[0-9A-Za-z]{36}\b`)},
+	{"slack", regexp.MustCompile(`\bxox[abprs]-[0-9A-Za-z\-]{10,}`)},
+	{"stripe", regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+}
+
+// secretCandidateRe matches quoted literals and unquoted assignment values made of token characters
+var secretCandidateRe = regexp.MustCompile("['\"`]([A-Za-z0-9+/=_\\-]+)['\"`]|[:=]\\s*([A-Za-z0-9+/=_\\-]+)\\s*$")
+
+// integrityHashRe matches subresource integrity hashes
+var integrityHashRe = regexp.MustCompile(`^sha(1|256|384|512)-`)
+
+// SetEntropyThreshold sets the Shannon entropy in bits per character above which a literal is reported
+func (d *SecretsDetector) SetEntropyThreshold(threshold float64) {
+	d.entropyThreshold = threshold
+}
+
+// SetMinSecretLength sets the minimum length of a literal for the entropy check
+func (d *SecretsDetector) SetMinSecretLength(length int) {
+	d.minSecretLength = length
+}
+
+// findSecret reports a line containing a known key format or a high-entropy literal.
+// The offending tokens are redacted in the matched code.
+func (d *SecretsDetector) findSecret(line string, lineNumber int, filePath string) (core.Match, bool) {
+	provider := ""
+	tokens := []string{}
+
+	// Known key formats identify their provider
+	for _, format := range knownKeyFormats {
+		for _, token := range format.re.FindAllString(line, -1) {
+			if provider == "" {
+				provider = format.provider
+			}
+			tokens = append(tokens, token)
+		}
+	}
+
+	// High-entropy literals that mix letters and digits
+	for _, m := range secretCandidateRe.FindAllStringSubmatch(line, -1) {
+		token := m[1]
+		if token == "" {
+			token = m[2]
+		}
+		if len(token) < d.minSecretLength || containsAny(tokens, token) || !hasLettersAndDigits(token) {
+			continue
+		}
+		if integrityHashRe.MatchString(token) {
+			// Subresource integrity hashes, e.g. in package-lock.json, are not secrets
+			continue
+		}
+		if shannonEntropy(token) > d.entropyThreshold {
+			tokens = append(tokens, token)
+		}
+	}
+
+	if len(tokens) == 0 {
+		return core.Match{}, false
+	}
+
+	redacted := line
+	for _, token := range tokens {
+		redacted = strings.Replace(redacted, token, redactSecret(token), -1)
+	}
+
+	return newSecretMatch(secretSignature, provider, filePath, lineNumber, redacted, 0.8), true
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// redactSecret keeps the first four characters of a token and masks the rest
+func redactSecret(token string) string {
+	if len(token) <= 4 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:4] + strings.Repeat("*", len(token)-4)
+}
+
+// hasLettersAndDigits reports whether s contains both a letter and a digit
+func hasLettersAndDigits(s string) bool {
+	letter := strings.IndexFunc(s, func(r rune) bool { return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') }) >= 0
+	digit := strings.IndexFunc(s, func(r rune) bool { return r >= '0' && r <= '9' }) >= 0
+	return letter && digit
+}
+
+// containsAny reports whether s contains any of the tokens
+func containsAny(tokens []string, s string) bool {
+	for _, token := range tokens {
+		if strings.Contains(s, token) {
+			return true
+		}
+	}
+	return false
+}
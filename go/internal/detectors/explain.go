@@ -0,0 +1,27 @@
+package detectors
+
+import "github.com/re-movery/re-movery/internal/core"
+
+// confidenceFactors accumulates the components of a match's confidence
+type confidenceFactors []core.ConfidenceFactor
+
+// add records a factor that adjusts the confidence
+func (f *confidenceFactors) add(reason string, value float64) {
+	*f = append(*f, core.ConfidenceFactor{Reason: reason, Value: value})
+}
+
+// total returns the sum of the factors, capped at 1.0.
+// The cap is recorded as a factor so the factors always add up to the total.
+func (f *confidenceFactors) total() float64 {
+	confidence := 0.0
+	for _, factor := range *f {
+		confidence += factor.Value
+	}
+
+	if confidence > 1.0 {
+		f.add("capped at 1.0", 1.0-confidence)
+		confidence = 1.0
+	}
+
+	return confidence
+}
@@ -0,0 +1,496 @@
+package detectors
+
+import (
+	"bufio"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// GoDetector is a detector for Go code
+type GoDetector struct {
+	core.BaseDetector
+	signatures []core.Signature
+}
+
+// NewGoDetector creates a new Go detector
+func NewGoDetector() *GoDetector {
+	detector := &GoDetector{}
+	detector.loadSignatures()
+	return detector
+}
+
+// Name returns the name of the detector
+func (d *GoDetector) Name() string {
+	return "go"
+}
+
+// SupportedLanguages returns the list of supported languages
+func (d *GoDetector) SupportedLanguages() []string {
+	return []string{"go"}
+}
+
+// Signatures returns every signature the detector can report
+func (d *GoDetector) Signatures() []core.Signature {
+	return append(append([]core.Signature{}, d.signatures...), goRedirectSignature, goServeSignature, goOriginSignature, goRaceSignature, goPermissionSignature, goSeedSignature, goIdentifierSignature)
+}
+
+// DetectFile detects vulnerabilities in a file
+func (d *GoDetector) DetectFile(filePath string) ([]core.Match, error) {
+	// Check if file is a Go file
+	if filepath.Ext(filePath) != ".go" {
+		return nil, nil
+	}
+
+	// Read file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectCode(string(content), filePath)
+}
+
+// DetectReader detects vulnerabilities in code read from r. The code is read into
+// memory, as it is parsed and type-checked as a whole.
+func (d *GoDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return core.ReadAndDetect(d, r, filePath)
+}
+
+// DetectCode detects vulnerabilities in code
+func (d *GoDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches := []core.Match{}
+
+	// Scan code line by line
+	scanner := bufio.NewScanner(strings.NewReader(code))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		// Check each single-line signature
+		for _, signature := range d.signatures {
+			if signature.Multiline {
+				continue
+			}
+			for _, pattern := range signature.CodePatterns {
+				re, err := compilePattern(pattern)
+				if err != nil {
+					continue
+				}
+
+				if re.MatchString(line) {
+					confidence, factors := d.calculateConfidence(line, pattern)
+					match := core.Match{
+						Signature:   signature,
+						FilePath:    filePath,
+						LineNumber:  lineNumber,
+						MatchedCode: line,
+						Confidence:  confidence,
+						Explanation: &core.Explanation{
+							Pattern: pattern,
+							Factors: factors,
+						},
+					}
+					matches = append(matches, match)
+				}
+			}
+		}
+	}
+
+	// Match multi-line signatures against the whole code
+	matches = append(matches, matchMultiline(d.signatures, code, filePath, d.calculateConfidence)...)
+
+	// Perform additional Go-specific checks
+	matches = append(matches, d.checkGoSpecificIssues(code, filePath)...)
+
+	return matches, nil
+}
+
+// loadSignatures loads the signatures for Go code
+func (d *GoDetector) loadSignatures() {
+	d.signatures = []core.Signature{
+		{
+			ID:          "GO001",
+			Name:        "SQL Injection in GORM raw query",
+			Severity:    "high",
+			Description: "Building GORM Raw()/Exec() queries with fmt.Sprintf or concatenation bypasses placeholder binding",
+			CodePatterns: []string{
+				`\.(Raw|Exec)\s*\(\s*fmt\.Sprintf\s*\(`,
+				`\.(Raw|Exec)\s*\(\s*"[^"]*"\s*\+`,
+			},
+			References: []string{
+				"https://gorm.io/docs/security.html#SQL-injection-Methods",
+			},
+			Docs: &core.SignatureDocs{
+				VulnerableExample: `db.Raw(fmt.Sprintf("SELECT * FROM users WHERE id = %s", id)).Scan(&user)`,
+				SafeExample:       `db.Raw("SELECT * FROM users WHERE id = ?", id).Scan(&user)`,
+				Remediation:       "Pass values as arguments for ? placeholders so that GORM binds them, instead of formatting them into the query.",
+			},
+		},
+		{
+			ID:          "GO002",
+			Name:        "Internal errors exposed to clients",
+			Severity:    "medium",
+			Description: "Writing err.Error() to an HTTP response leaks internal details to clients",
+			CodePatterns: []string{
+				`http\.Error\s*\(\s*\w+\s*,\s*err\.Error\s*\(\s*\)`,
+				`\.Write\s*\(\s*\[\]byte\s*\(\s*err\.Error\s*\(\s*\)`,
+				`fmt\.Fprint(f|ln)?\s*\(\s*w\s*,.*\berr\.Error\s*\(\s*\)`,
+				`\bc\.(String|JSON|AbortWithStatusJSON)\s*\(.*\berr\.Error\s*\(\s*\)`,
+			},
+			References: []string{
+				"https://owasp.org/www-community/Improper_Error_Handling",
+			},
+		},
+		{
+			ID:          "GO006",
+			Name:        "Unencrypted WebSocket connection",
+			Severity:    "medium",
+			Description: "WebSocket connections over ws:// are sent in plaintext; use wss:// instead",
+			CodePatterns: []string{
+				`\.Dial(Context)?\s*\((\s*\w+\s*,)?\s*"ws://`,
+				`websocket\.Dial\s*\(\s*"ws://`,
+			},
+			References: []string{
+				"https://owasp.org/www-project-web-security-testing-guide/latest/4-Web_Application_Security_Testing/11-Client-side_Testing/10-Testing_WebSockets",
+			},
+		},
+	}
+}
+
+// calculateConfidence calculates the confidence of a match and the factors it is made of
+func (d *GoDetector) calculateConfidence(matchedCode string, pattern string) (float64, []core.ConfidenceFactor) {
+	// Base confidence
+	factors := confidenceFactors{{Reason: "base confidence", Value: 0.8}}
+
+	// Adjust based on match length
+	if len(matchedCode) > 10 {
+		factors.add("matched code is longer than 10 characters", 0.05)
+	}
+
+	// Adjust based on pattern specificity
+	if len(pattern) > 20 {
+		factors.add("pattern is longer than 20 characters", 0.05)
+	}
+
+	// Adjust based on function call parameters
+	if strings.Contains(matchedCode, "(") && strings.Contains(matchedCode, ")") {
+		factors.add("matched code contains a function call", 0.05)
+	}
+
+	// Ensure confidence is between 0 and 1
+	confidence := factors.total()
+
+	return confidence, factors
+}
+
+// Signatures of the checks on the AST
+var (
+	goRedirectSignature = core.Signature{
+		ID:          "GO003",
+		Name:        "Unvalidated redirect",
+		Severity:    "medium",
+		Description: "Redirecting to a URL taken from the request allows open redirects to attacker-controlled sites",
+		References: []string{
+			"https://cheatsheetseries.owasp.org/cheatsheets/Unvalidated_Redirects_and_Forwards_Cheat_Sheet.html",
+		},
+	}
+	goServeSignature = core.Signature{
+		ID:          "GO004",
+		Name:        "Path traversal in file serving",
+		Severity:    "high",
+		Description: "Serving files from a path taken from the request allows reading arbitrary files",
+		References: []string{
+			"https://owasp.org/www-community/attacks/Path_Traversal",
+		},
+	}
+	goOriginSignature = core.Signature{
+		ID:          "GO005",
+		Name:        "WebSocket accepts any origin",
+		Severity:    "medium",
+		Description: "A CheckOrigin function that always returns true allows cross-site WebSocket hijacking",
+		References: []string{
+			"https://pkg.go.dev/github.com/gorilla/websocket#hdr-Origin_Considerations",
+			"https://owasp.org/www-project-web-security-testing-guide/latest/4-Web_Application_Security_Testing/11-Client-side_Testing/10-Testing_WebSockets",
+		},
+	}
+	goPermissionSignature = core.Signature{
+		ID:          "GO008",
+		Name:        "Insecure file permissions",
+		Severity:    "medium",
+		Description: "Files and directories created world-writable, or sensitive files created world-readable, can be tampered with or read by other local users",
+		References: []string{
+			"https://cwe.mitre.org/data/definitions/732.html",
+		},
+	}
+	goSeedSignature = core.Signature{
+		ID:          "GO010",
+		Name:        "Predictable random seed",
+		Severity:    "medium",
+		Description: "Seeding math/rand with a constant makes every value it produces predictable",
+		References: []string{
+			"https://cwe.mitre.org/data/definitions/336.html",
+		},
+	}
+	goIdentifierSignature = core.Signature{
+		ID:          "GO011",
+		Name:        "Predictable identifier from math/rand",
+		Severity:    "medium",
+		Description: "Tokens, keys and IDs generated with math/rand are predictable, whether or not it is seeded; use crypto/rand instead",
+		References: []string{
+			"https://pkg.go.dev/crypto/rand",
+		},
+	}
+	goRaceSignature = toctouSignature("GO007")
+)
+
+// checkGoSpecificIssues performs additional Go-specific checks on the AST
+func (d *GoDetector) checkGoSpecificIssues(code string, filePath string) []core.Match {
+	matches := []core.Match{}
+
+	// Code that does not parse is only checked line by line
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, code, 0)
+	if err != nil {
+		return matches
+	}
+
+	lines := strings.Split(code, "\n")
+	report := func(signature core.Signature, node ast.Node) {
+		lineNumber := fset.Position(node.Pos()).Line
+		matchedCode := ""
+		if lineNumber > 0 && lineNumber <= len(lines) {
+			matchedCode = strings.TrimSpace(lines[lineNumber-1])
+		}
+		matches = append(matches, core.Match{
+			Signature:   signature,
+			FilePath:    filePath,
+			LineNumber:  lineNumber,
+			MatchedCode: matchedCode,
+			Confidence:  0.85,
+			Explanation: &core.Explanation{
+				Pattern: "Go AST check for " + signature.Name,
+				Factors: []core.ConfidenceFactor{{Reason: "fixed confidence of AST checks", Value: 0.85}},
+			},
+		})
+	}
+
+	randName := importName(file, "math/rand")
+
+	// Track request-derived identifiers separately for each top-level declaration
+	for _, decl := range file.Decls {
+		tainted := make(map[string]bool)
+		statted := make(map[string]bool)
+		generatesIdentifier := false
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			generatesIdentifier = identifierFuncRe.MatchString(fn.Name.Name)
+		}
+
+		ast.Inspect(decl, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.FuncType:
+				// *http.Request parameters are the taint sources
+				if n.Params != nil {
+					for _, field := range n.Params.List {
+						if isHTTPRequestType(field.Type) {
+							for _, name := range field.Names {
+								tainted[name.Name] = true
+							}
+						}
+					}
+				}
+			case *ast.AssignStmt:
+				// Values derived from a tainted identifier are tainted too
+				for i, lhs := range n.Lhs {
+					rhs := n.Rhs[0]
+					if len(n.Rhs) == len(n.Lhs) {
+						rhs = n.Rhs[i]
+					}
+					if sel, ok := lhs.(*ast.SelectorExpr); ok && sel.Sel.Name == "CheckOrigin" && isAlwaysTrueFunc(rhs) {
+						report(goOriginSignature, n)
+					}
+					ident, ok := lhs.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					if refersTo(rhs, tainted) {
+						tainted[ident.Name] = true
+					}
+				}
+			case *ast.KeyValueExpr:
+				// websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+				if key, ok := n.Key.(*ast.Ident); ok && key.Name == "CheckOrigin" && isAlwaysTrueFunc(n.Value) {
+					report(goOriginSignature, n)
+				}
+			case *ast.ValueSpec:
+				for i, name := range n.Names {
+					if i < len(n.Values) && refersTo(n.Values[i], tainted) {
+						tainted[name.Name] = true
+					}
+				}
+			case *ast.CallExpr:
+				switch {
+				case isPkgCall(n, "http", "Redirect") && len(n.Args) >= 3:
+					if refersTo(n.Args[2], tainted) {
+						report(goRedirectSignature, n)
+					}
+				case isPkgCall(n, "http", "ServeFile") && len(n.Args) >= 3:
+					if refersTo(n.Args[2], tainted) {
+						report(goServeSignature, n)
+					}
+				case isPkgCall(n, "http", "FileServer") && len(n.Args) >= 1:
+					if refersTo(n.Args[0], tainted) {
+						report(goServeSignature, n)
+					}
+				case (isPkgCall(n, "os", "Mkdir") || isPkgCall(n, "os", "MkdirAll")) && len(n.Args) >= 2:
+					if mode, ok := fileMode(n.Args[1]); ok && mode&0002 != 0 {
+						report(goPermissionSignature, n)
+					}
+				case (isPkgCall(n, "os", "WriteFile") || isPkgCall(n, "ioutil", "WriteFile") || isPkgCall(n, "os", "OpenFile")) && len(n.Args) >= 3:
+					// World-writable files, or world-readable files with sensitive names
+					if mode, ok := fileMode(n.Args[2]); ok {
+						if mode&0002 != 0 || (mode&0004 != 0 && sensitivePathRe.MatchString(types.ExprString(n.Args[0]))) {
+							report(goPermissionSignature, n)
+						}
+					}
+				case randName != "" && (isPkgCall(n, randName, "Seed") || isPkgCall(n, randName, "NewSource")) && len(n.Args) == 1:
+					if allLiterals(n.Args) {
+						report(goSeedSignature, n)
+					}
+				case randName != "" && generatesIdentifier && isRandValueCall(n, randName):
+					report(goIdentifierSignature, n)
+				case (isPkgCall(n, "os", "Stat") || isPkgCall(n, "os", "Lstat")) && len(n.Args) >= 1:
+					// Paths are compared by their source text
+					statted[types.ExprString(n.Args[0])] = true
+				case (isPkgCall(n, "os", "Open") || isPkgCall(n, "os", "OpenFile")) && len(n.Args) >= 1:
+					if statted[types.ExprString(n.Args[0])] {
+						report(goRaceSignature, n)
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	return matches
+}
+
+// isHTTPRequestType reports whether an expression is the type *http.Request
+func isHTTPRequestType(expr ast.Expr) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "http" && sel.Sel.Name == "Request"
+}
+
+// sensitivePathRe matches file paths that suggest secrets or credentials
+var sensitivePathRe = regexp.MustCompile(`(?i)(secret|passw(or)?d|credential|token|private|\.pem|\.key|id_rsa|\.env)`)
+
+// fileMode returns the value of a literal file mode argument, such as 0644 or os.FileMode(0644)
+func fileMode(expr ast.Expr) (int64, bool) {
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 && isPkgCall(call, "os", "FileMode") {
+		expr = call.Args[0]
+	}
+
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+
+	// Base 0 reads a leading 0 or 0o as octal, as Go does
+	mode, err := strconv.ParseInt(strings.Replace(lit.Value, "_", "", -1), 0, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return mode, true
+}
+
+// isAlwaysTrueFunc reports whether an expression is a function literal that only returns true
+func isAlwaysTrueFunc(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.FuncLit)
+	if !ok || len(lit.Body.List) != 1 {
+		return false
+	}
+	ret, ok := lit.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return false
+	}
+	ident, ok := ret.Results[0].(*ast.Ident)
+	return ok && ident.Name == "true"
+}
+
+// isPkgCall reports whether a call is pkg.name(...)
+func isPkgCall(call *ast.CallExpr, pkg string, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg && sel.Sel.Name == name
+}
+
+// identifierFuncRe matches names of functions that generate tokens, keys or IDs
+var identifierFuncRe = regexp.MustCompile(`(^(token|secret|session|nonce|salt|password|key|uuid|id)|Token|Secret|Session|Nonce|Salt|Password|Key|UUID|ID|Id)s?$`)
+
+// importName returns the name under which a file imports a package, or "" if it does not
+func importName(file *ast.File, path string) string {
+	for _, imp := range file.Imports {
+		if imported, err := strconv.Unquote(imp.Path.Value); err != nil || imported != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	return ""
+}
+
+// isRandValueCall reports whether a call draws a value from the math/rand package imported as pkg
+func isRandValueCall(call *ast.CallExpr, pkg string) bool {
+	for _, name := range []string{"Int", "Intn", "Int31", "Int31n", "Int63", "Int63n", "Uint32", "Uint64", "Float32", "Float64", "Perm", "Read", "Shuffle"} {
+		if isPkgCall(call, pkg, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// allLiterals reports whether all expressions are basic literals
+func allLiterals(exprs []ast.Expr) bool {
+	for _, expr := range exprs {
+		if _, ok := expr.(*ast.BasicLit); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// refersTo reports whether an expression uses any of the given identifiers
+func refersTo(expr ast.Expr, idents map[string]bool) bool {
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && idents[ident.Name] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,309 @@
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试GORM Raw()查询的SQL注入检测
+func TestGoGORMRaw(t *testing.T) {
+	detector := NewGoDetector()
+
+	safe := `db.Raw("SELECT * FROM users WHERE id = ?", id).Scan(&user)`
+	matches, err := detector.DetectCode(safe, "repo.go")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "GO001"))
+
+	unsafe := `db.Raw(fmt.Sprintf("SELECT * FROM users WHERE id = %s", id)).Scan(&user)`
+	matches, err = detector.DetectCode(unsafe, "repo.go")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "GO001"))
+}
+
+// 测试Go处理函数向响应写入内部错误的检测
+func TestGoErrorExposure(t *testing.T) {
+	detector := NewGoDetector()
+
+	matches, err := detector.DetectCode(`http.Error(w, err.Error(), http.StatusInternalServerError)`, "handler.go")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "GO002"))
+
+	matches, err = detector.DetectCode(`http.Error(w, "internal error", http.StatusInternalServerError)`, "handler.go")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "GO002"))
+}
+
+// 测试Go处理函数中未经验证的重定向检测
+func TestGoUnvalidatedRedirect(t *testing.T) {
+	detector := NewGoDetector()
+
+	constant := `package main
+
+import "net/http"
+
+func login(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, "/dashboard", http.StatusFound)
+}
+`
+	matches, err := detector.DetectCode(constant, "handler.go")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "GO003"))
+
+	derived := `package main
+
+import "net/http"
+
+func login(w http.ResponseWriter, req *http.Request) {
+	next := req.URL.Query().Get("next")
+	target := next
+	http.Redirect(w, req, target, http.StatusFound)
+}
+`
+	matches, err = detector.DetectCode(derived, "handler.go")
+	assert.NoError(t, err)
+	match := findSignature(matches, "GO003")
+	if assert.NotNil(t, match) {
+		assert.Equal(t, 8, match.LineNumber)
+	}
+}
+
+// 测试Go文件服务中的路径遍历检测
+func TestGoServeFileTraversal(t *testing.T) {
+	detector := NewGoDetector()
+
+	code := `package main
+
+import (
+	"net/http"
+	"path/filepath"
+)
+
+func download(w http.ResponseWriter, r *http.Request) {
+	http.ServeFile(w, r, filepath.Join("/srv/files", r.URL.Query().Get("name")))
+}
+
+func static(w http.ResponseWriter, r *http.Request) {
+	http.ServeFile(w, r, "/srv/files/index.html")
+}
+
+func main() {
+	http.Handle("/assets/", http.FileServer(http.Dir("./assets")))
+}
+`
+	matches, err := detector.DetectCode(code, "server.go")
+	assert.NoError(t, err)
+
+	lines := []int{}
+	for _, match := range matches {
+		if match.Signature.ID == "GO004" {
+			lines = append(lines, match.LineNumber)
+		}
+	}
+	assert.Equal(t, []int{9}, lines)
+}
+
+// 测试接受任意来源的WebSocket升级检测
+func TestGoWebSocketCheckOrigin(t *testing.T) {
+	detector := NewGoDetector()
+
+	unsafe := `package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func init() {
+	other := websocket.Upgrader{}
+	other.CheckOrigin = func(r *http.Request) bool {
+		return true
+	}
+}
+`
+	matches, err := detector.DetectCode(unsafe, "ws.go")
+	assert.NoError(t, err)
+	lines := []int{}
+	for _, match := range matches {
+		if match.Signature.ID == "GO005" {
+			lines = append(lines, match.LineNumber)
+		}
+	}
+	assert.Equal(t, []int{10, 15}, lines)
+
+	safe := `package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return r.Header.Get("Origin") == "https://example.com" },
+}
+`
+	matches, err = detector.DetectCode(safe, "ws.go")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "GO005"))
+}
+
+// 测试未加密的WebSocket连接检测
+func TestGoWebSocketPlaintext(t *testing.T) {
+	detector := NewGoDetector()
+
+	matches, err := detector.DetectCode(`conn, _, err := websocket.DefaultDialer.Dial("ws://example.com/feed", nil)`, "client.go")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "GO006"))
+
+	matches, err = detector.DetectCode(`conn, _, err := websocket.DefaultDialer.Dial("wss://example.com/feed", nil)`, "client.go")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "GO006"))
+}
+
+// 测试先检查后打开文件的竞态条件检测
+func TestGoTOCTOU(t *testing.T) {
+	detector := NewGoDetector()
+
+	unsafe := `package main
+
+import "os"
+
+func read(path string) (*os.File, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, err
+	}
+	return os.Open(path)
+}
+`
+	matches, err := detector.DetectCode(unsafe, "read.go")
+	assert.NoError(t, err)
+	match := findSignature(matches, "GO007")
+	if assert.NotNil(t, match) {
+		assert.Equal(t, 9, match.LineNumber)
+	}
+
+	// 直接打开文件并处理错误
+	safe := `package main
+
+import "os"
+
+func read(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDONLY, 0)
+}
+
+func other(path string, name string) {
+	os.Stat(name)
+	os.Open(path)
+}
+`
+	matches, err = detector.DetectCode(safe, "read.go")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "GO007"))
+}
+
+// 测试创建文件和目录时的不安全权限检测
+func TestGoInsecurePermissions(t *testing.T) {
+	detector := NewGoDetector()
+
+	unsafe := `package main
+
+import (
+	"io/ioutil"
+	"os"
+)
+
+func setup(p string, data []byte) {
+	os.MkdirAll(p, 0777)
+	ioutil.WriteFile("cache.txt", data, 0666)
+	os.WriteFile("secrets/token.txt", data, 0644)
+	os.Mkdir(p, os.FileMode(0o777))
+}
+`
+	matches, err := detector.DetectCode(unsafe, "setup.go")
+	assert.NoError(t, err)
+	lines := []int{}
+	for _, match := range matches {
+		if match.Signature.ID == "GO008" {
+			lines = append(lines, match.LineNumber)
+		}
+	}
+	assert.Equal(t, []int{9, 10, 11, 12}, lines)
+
+	safe := `package main
+
+import "os"
+
+func setup(p string, data []byte) {
+	os.MkdirAll(p, 0700)
+	os.WriteFile("report.txt", data, 0644)
+	os.WriteFile("secrets/token.txt", data, 0600)
+}
+`
+	matches, err = detector.DetectCode(safe, "setup.go")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "GO008"))
+}
+
+// 测试可预测的随机数种子和标识符检测
+func TestGoPredictableRandomness(t *testing.T) {
+	detector := NewGoDetector()
+
+	code := `package main
+
+import (
+	"encoding/hex"
+	"math/rand"
+)
+
+func newToken() string {
+	rand.Seed(1)
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func sessionID() int64 {
+	return rand.Int63()
+}
+
+func shuffle(items []int) {
+	rand.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+}
+`
+	matches, err := detector.DetectCode(code, "token.go")
+	assert.NoError(t, err)
+	seeds := []int{}
+	identifiers := []int{}
+	for _, match := range matches {
+		switch match.Signature.ID {
+		case "GO010":
+			seeds = append(seeds, match.LineNumber)
+		case "GO011":
+			identifiers = append(identifiers, match.LineNumber)
+		}
+	}
+	assert.Equal(t, []int{9}, seeds)
+	assert.Equal(t, []int{11, 16}, identifiers)
+
+	// crypto/rand不会触发
+	safe := `package main
+
+import "crypto/rand"
+
+func newToken() []byte {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return b
+}
+`
+	matches, err = detector.DetectCode(safe, "token.go")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "GO010"))
+	assert.False(t, hasSignature(matches, "GO011"))
+}
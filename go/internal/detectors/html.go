@@ -0,0 +1,147 @@
+package detectors
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// HTMLDetector is a detector for HTML markup, including Vue and JSX templates
+type HTMLDetector struct {
+	core.BaseDetector
+	signatures []core.Signature
+}
+
+// NewHTMLDetector creates a new HTML detector
+func NewHTMLDetector() *HTMLDetector {
+	detector := &HTMLDetector{}
+	detector.loadSignatures()
+	return detector
+}
+
+// Name returns the name of the detector
+func (d *HTMLDetector) Name() string {
+	return "html"
+}
+
+// SupportedLanguages returns the list of supported languages
+func (d *HTMLDetector) SupportedLanguages() []string {
+	return []string{"html", "htm", "vue", "jsx"}
+}
+
+// Signatures returns every signature the detector can report
+func (d *HTMLDetector) Signatures() []core.Signature {
+	return d.signatures
+}
+
+// DetectFile detects vulnerabilities in a file
+func (d *HTMLDetector) DetectFile(filePath string) ([]core.Match, error) {
+	// Check if file is an HTML file
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext != ".html" && ext != ".htm" && ext != ".vue" && ext != ".jsx" {
+		return nil, nil
+	}
+
+	// Read file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectCode(string(content), filePath)
+}
+
+// DetectReader detects vulnerabilities in code read from r
+func (d *HTMLDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return core.ReadAndDetect(d, r, filePath)
+}
+
+// DetectCode detects vulnerabilities in code
+func (d *HTMLDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches := []core.Match{}
+
+	// Tags may span several lines, so match them against the whole code
+	matches = append(matches, d.checkSubresourceIntegrity(code, filePath)...)
+
+	return matches, nil
+}
+
+// loadSignatures loads the signatures for HTML markup
+func (d *HTMLDetector) loadSignatures() {
+	d.signatures = []core.Signature{
+		{
+			ID:          "HTML001",
+			Name:        "External resource without integrity check",
+			Severity:    "medium",
+			Description: "External <script> and <link> tags without an integrity attribute load whatever the CDN serves, which is a supply-chain risk",
+			CodePatterns: []string{
+				`<script\b[^>]*\bsrc\s*=\s*['\"]?(https?:)?//`,
+				`<link\b[^>]*\bhref\s*=\s*['\"]?(https?:)?//`,
+			},
+			References: []string{
+				"https://developer.mozilla.org/en-US/docs/Web/Security/Subresource_Integrity",
+			},
+		},
+	}
+}
+
+var (
+	htmlTagRe       = regexp.MustCompile(`(?is)<(script|link)\b[^>]*>`)
+	htmlSrcRe       = regexp.MustCompile(`(?is)\b(src|href)\s*=\s*\{?\s*['\"]?\s*((https?:)?//[^'\"\s>]+)`)
+	htmlRelRe       = regexp.MustCompile(`(?is)\brel\s*=\s*['\"]?\s*([a-z\s]+)`)
+	htmlIntegrityRe = regexp.MustCompile(`(?is)\bintegrity\s*=`)
+)
+
+// checkSubresourceIntegrity flags external scripts and stylesheets without an integrity attribute
+func (d *HTMLDetector) checkSubresourceIntegrity(code string, filePath string) []core.Match {
+	matches := []core.Match{}
+
+	for _, loc := range htmlTagRe.FindAllStringSubmatchIndex(code, -1) {
+		tag := code[loc[0]:loc[1]]
+		tagName := strings.ToLower(code[loc[2]:loc[3]])
+
+		// Only resources loaded from another origin need an integrity check
+		src := htmlSrcRe.FindStringSubmatch(tag)
+		if src == nil {
+			continue
+		}
+		if tagName == "script" && strings.ToLower(src[1]) != "src" {
+			continue
+		}
+		if tagName == "link" {
+			rel := htmlRelRe.FindStringSubmatch(tag)
+			if rel == nil || !isSubresourceRel(rel[1]) {
+				continue
+			}
+		}
+
+		if htmlIntegrityRe.MatchString(tag) {
+			continue
+		}
+
+		matches = append(matches, core.Match{
+			Signature:   d.signatures[0],
+			FilePath:    filePath,
+			LineNumber:  1 + strings.Count(code[:loc[0]], "\n"),
+			MatchedCode: tag,
+			Confidence:  0.85,
+		})
+	}
+
+	return matches
+}
+
+// isSubresourceRel reports whether a link rel value loads a subresource that supports integrity
+func isSubresourceRel(rel string) bool {
+	for _, value := range strings.Fields(strings.ToLower(rel)) {
+		switch value {
+		case "stylesheet", "preload", "modulepreload":
+			return true
+		}
+	}
+	return false
+}
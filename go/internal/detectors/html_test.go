@@ -0,0 +1,43 @@
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试缺少SRI的外部脚本检测
+func TestHTMLSubresourceIntegrity(t *testing.T) {
+	detector := NewHTMLDetector()
+
+	unsafe := `<html>
+<head>
+  <script src="https://cdn.jsdelivr.net/npm/chart.js"></script>
+</head>
+</html>`
+	matches, err := detector.DetectCode(unsafe, "index.html")
+	assert.NoError(t, err)
+	match := findSignature(matches, "HTML001")
+	if assert.NotNil(t, match) {
+		assert.Equal(t, 3, match.LineNumber)
+	}
+
+	safe := `<script src="https://cdn.jsdelivr.net/npm/chart.js"
+  integrity="sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC"
+  crossorigin="anonymous"></script>`
+	matches, err = detector.DetectCode(safe, "index.html")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "HTML001"))
+
+	// 本地脚本和非样式表链接不需要SRI
+	local := `<script src="/static/app.js"></script>
+<link rel="icon" href="https://example.com/favicon.ico">`
+	matches, err = detector.DetectCode(local, "index.html")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "HTML001"))
+
+	// 外部样式表同样需要SRI
+	matches, err = detector.DetectCode(`<link rel="stylesheet" href="//cdn.example.com/app.css">`, "App.vue")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "HTML001"))
+}
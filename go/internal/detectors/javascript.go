@@ -1,8 +1,7 @@
 package detectors
 
 import (
-	"bufio"
-	"io/ioutil"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -11,16 +10,35 @@ import (
 	"github.com/re-movery/re-movery/internal/core"
 )
 
-// JavaScriptDetector is a detector for JavaScript code
+// JavaScriptDetector is a detector for JavaScript and TypeScript code. The TypeScript
+// signatures (TS001 and up) only apply to .ts and .tsx files.
 type JavaScriptDetector struct {
-	signatures []core.Signature
+	core.BaseDetector
+	streamLimits
+	signatures   []core.Signature
+	tsSignatures []core.Signature
+
+	// builtinChecks enables the checks that are not signatures, e.g. JS011
+	builtinChecks bool
 }
 
 // NewJavaScriptDetector creates a new JavaScript detector
 func NewJavaScriptDetector() *JavaScriptDetector {
-	detector := &JavaScriptDetector{}
-	detector.loadSignatures()
-	return detector
+	return &JavaScriptDetector{
+		streamLimits:  defaultStreamLimits(),
+		signatures:    javaScriptSignatures(),
+		tsSignatures:  typeScriptSignatures(),
+		builtinChecks: true,
+	}
+}
+
+// ClearSignatures removes the built-in JavaScript and TypeScript signatures and
+// checks, so that the detector no longer reports anything. It is used when only
+// custom signatures should run.
+func (d *JavaScriptDetector) ClearSignatures() {
+	d.signatures = nil
+	d.tsSignatures = nil
+	d.builtinChecks = false
 }
 
 // Name returns the name of the detector
@@ -33,6 +51,16 @@ func (d *JavaScriptDetector) SupportedLanguages() []string {
 	return []string{"javascript", "js", "jsx", "ts", "tsx"}
 }
 
+// Signatures returns every signature the detector can report, including the
+// TypeScript signatures that only apply to .ts and .tsx files
+func (d *JavaScriptDetector) Signatures() []core.Signature {
+	if !d.builtinChecks {
+		return append(append([]core.Signature{}, d.signatures...), d.tsSignatures...)
+	}
+	signatures := append(append([]core.Signature{}, d.signatures...), jsConsoleLogSignature, jsAlertSignature, jsWebSocketOriginSignature, jsTOCTOUSignature)
+	return append(append(signatures, tsJSONParseEvalSignature), d.tsSignatures...)
+}
+
 // DetectFile detects vulnerabilities in a file
 func (d *JavaScriptDetector) DetectFile(filePath string) ([]core.Match, error) {
 	// Check if file is a JavaScript file
@@ -41,74 +69,132 @@ func (d *JavaScriptDetector) DetectFile(filePath string) ([]core.Match, error) {
 		return nil, nil
 	}
 
-	// Read file
-	content, err := ioutil.ReadFile(filePath)
+	// Skip files above the size limit
+	if skip, err := d.skipLargeFile(filePath); skip || err != nil {
+		return nil, err
+	}
+
+	// Open file
+	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 
-	return d.DetectCode(string(content), filePath)
+	return d.detectReader(file, filePath)
+}
+
+// DetectReader detects vulnerabilities in code streamed line by line from r
+func (d *JavaScriptDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return d.detectReader(r, filePath)
 }
 
 // DetectCode detects vulnerabilities in code
 func (d *JavaScriptDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	return d.detectReader(strings.NewReader(code), filePath)
+}
+
+// detectReader detects vulnerabilities in code streamed line by line,
+// so that memory use is bounded by the line size instead of the file size. Only
+// multi-line signatures, which are matched against the whole code, keep every line.
+func (d *JavaScriptDetector) detectReader(r io.Reader, filePath string) ([]core.Match, error) {
 	matches := []core.Match{}
 
+	// TypeScript signatures and checks only apply to .ts and .tsx files
+	signatures := d.signaturesFor(filePath)
+	typeScript := isTypeScript(filePath)
+
+	// Multi-line checks only see a bounded window of lines
+	window := newLineWindow(filePath, func(code string, filePath string) []core.Match {
+		matches := []core.Match{}
+		if d.builtinChecks {
+			matches = d.checkJavaScriptSpecificIssues(code, filePath)
+		}
+		if typeScript && d.builtinChecks {
+			matches = append(matches, d.checkTypeScriptSpecificIssues(code, filePath)...)
+		}
+		return matches
+	})
+
+	// Multi-line signatures are matched once against the whole code
+	multiline := newMultilineBuffer(signatures, filePath, d.calculateConfidence)
+
+	// Single-line matches are scored once the lines around them have been read
+	buffer := newContextBuffer(d.calculateContextConfidence)
+
 	// Scan code line by line
-	scanner := bufio.NewScanner(strings.NewReader(code))
-	lineNumber := 0
-	for scanner.Scan() {
-		lineNumber++
-		line := scanner.Text()
-
-		// Check each signature
-		for _, signature := range d.signatures {
+	err := d.scanLines(r, func(lineNumber int, line string) {
+		// Check each single-line signature
+		lineMatches := []core.Match{}
+		for _, signature := range signatures {
+			if signature.Multiline {
+				continue
+			}
 			for _, pattern := range signature.CodePatterns {
-				re, err := regexp.Compile(pattern)
+				re, err := compilePattern(pattern)
 				if err != nil {
 					continue
 				}
 
 				if re.MatchString(line) {
-					match := core.Match{
+					lineMatches = append(lineMatches, core.Match{
 						Signature:   signature,
 						FilePath:    filePath,
 						LineNumber:  lineNumber,
 						MatchedCode: line,
-						Confidence:  d.calculateConfidence(line, pattern),
-					}
-					matches = append(matches, match)
+						Explanation: &core.Explanation{
+							Pattern: pattern,
+						},
+					})
 				}
 			}
 		}
+
+		buffer.add(line, lineMatches)
+		window.add(line)
+		multiline.add(line)
+	})
+	if err != nil {
+		return nil, err
 	}
+	matches = append(matches, buffer.close()...)
+	matches = append(matches, multiline.close()...)
 
 	// Perform additional JavaScript-specific checks
-	matches = append(matches, d.checkJavaScriptSpecificIssues(code, filePath)...)
+	matches = append(matches, window.close()...)
 
 	return matches, nil
 }
 
-// loadSignatures loads the signatures for JavaScript code
-func (d *JavaScriptDetector) loadSignatures() {
-	d.signatures = []core.Signature{
+// javaScriptSignatures returns the built-in signatures for JavaScript code
+func javaScriptSignatures() []core.Signature {
+	return []core.Signature{
 		{
 			ID:          "JS001",
 			Name:        "Dangerous eval() usage",
 			Severity:    "high",
 			Description: "Using eval() can execute arbitrary code and is a security risk",
+			Category:    "injection",
+			CWE:         "CWE-95",
 			CodePatterns: []string{
 				`eval\s*\([^)]*\)`,
 			},
 			References: []string{
 				"https://developer.mozilla.org/en-US/docs/Web/JavaScript/Reference/Global_Objects/eval",
 			},
+			Docs: &core.SignatureDocs{
+				VulnerableExample: "const value = eval(req.query.value);",
+				SafeExample:       "const value = JSON.parse(req.query.value);",
+				Remediation:       "Parse data with JSON.parse, or look the allowed inputs up in a table, instead of evaluating input as code.",
+			},
 		},
 		{
 			ID:          "JS002",
 			Name:        "Dangerous Function() constructor",
 			Severity:    "high",
 			Description: "Using Function() constructor can execute arbitrary code and is a security risk",
+			Category:    "injection",
+			CWE:         "CWE-95",
 			CodePatterns: []string{
 				`new\s+Function\s*\([^)]*\)`,
 				`Function\s*\([^)]*\)`,
@@ -122,6 +208,8 @@ func (d *JavaScriptDetector) loadSignatures() {
 			Name:        "DOM-based XSS risk",
 			Severity:    "high",
 			Description: "Manipulating innerHTML with user input can lead to XSS",
+			Category:    "injection",
+			CWE:         "CWE-79",
 			CodePatterns: []string{
 				`\.innerHTML\s*=`,
 				`\.outerHTML\s*=`,
@@ -137,6 +225,8 @@ func (d *JavaScriptDetector) loadSignatures() {
 			Name:        "Insecure random number generation",
 			Severity:    "medium",
 			Description: "Using Math.random() for security purposes is not recommended",
+			Category:    "crypto",
+			CWE:         "CWE-338",
 			CodePatterns: []string{
 				`Math\.random\s*\(\)`,
 			},
@@ -149,6 +239,8 @@ func (d *JavaScriptDetector) loadSignatures() {
 			Name:        "Hardcoded credentials",
 			Severity:    "high",
 			Description: "Hardcoded credentials are a security risk",
+			Category:    "secrets",
+			CWE:         "CWE-798",
 			CodePatterns: []string{
 				`password\s*=\s*['\"][^'\"]{3,}['\"]`,
 				`passwd\s*=\s*['\"][^'\"]{3,}['\"]`,
@@ -165,6 +257,8 @@ func (d *JavaScriptDetector) loadSignatures() {
 			Name:        "Insecure HTTP protocol",
 			Severity:    "medium",
 			Description: "Using HTTP instead of HTTPS can expose data to eavesdropping",
+			Category:    "transport",
+			CWE:         "CWE-319",
 			CodePatterns: []string{
 				`http:\/\/[^'\"]*['\"]`,
 			},
@@ -177,6 +271,8 @@ func (d *JavaScriptDetector) loadSignatures() {
 			Name:        "Potential prototype pollution",
 			Severity:    "high",
 			Description: "Modifying Object.prototype can lead to prototype pollution vulnerabilities",
+			Category:    "injection",
+			CWE:         "CWE-1321",
 			CodePatterns: []string{
 				`Object\.prototype\.[^=]+=`,
 				`__proto__\.[^=]+=`,
@@ -184,12 +280,19 @@ func (d *JavaScriptDetector) loadSignatures() {
 			References: []string{
 				"https://github.com/HoLyVieR/prototype-pollution-nsec18/blob/master/paper/JavaScript_prototype_pollution_attack_in_NodeJS.pdf",
 			},
+			Docs: &core.SignatureDocs{
+				VulnerableExample: "target.__proto__.isAdmin = req.body.isAdmin;",
+				SafeExample:       "const target = Object.create(null);\ntarget.isAdmin = req.body.isAdmin === true;",
+				Remediation:       "Never assign to Object.prototype or __proto__; merge untrusted objects into null-prototype objects or a Map, and reject the keys __proto__, constructor and prototype.",
+			},
 		},
 		{
 			ID:          "JS008",
 			Name:        "Insecure JWT verification",
 			Severity:    "high",
 			Description: "Not verifying JWT signatures can lead to authentication bypass",
+			Category:    "authentication",
+			CWE:         "CWE-347",
 			CodePatterns: []string{
 				`jwt\.verify\s*\([^,]*,\s*['\"]?none['\"]?[^)]*\)`,
 			},
@@ -202,6 +305,8 @@ func (d *JavaScriptDetector) loadSignatures() {
 			Name:        "Insecure cookie settings",
 			Severity:    "medium",
 			Description: "Cookies without secure or httpOnly flags can be vulnerable to theft",
+			Category:    "configuration",
+			CWE:         "CWE-614",
 			CodePatterns: []string{
 				`document\.cookie\s*=\s*[^;]*(?!secure|httpOnly)`,
 				`\.cookie\s*\([^)]*(?!secure|httpOnly)[^)]*\)`,
@@ -215,6 +320,8 @@ func (d *JavaScriptDetector) loadSignatures() {
 			Name:        "Debug mode enabled",
 			Severity:    "medium",
 			Description: "Running applications in debug mode can expose sensitive information",
+			Category:    "configuration",
+			CWE:         "CWE-489",
 			CodePatterns: []string{
 				`debug\s*:\s*true`,
 				`debugMode\s*=\s*true`,
@@ -223,40 +330,145 @@ func (d *JavaScriptDetector) loadSignatures() {
 				"https://expressjs.com/en/advanced/best-practice-security.html",
 			},
 		},
+		{
+			ID:          "JS013",
+			Name:        "SQL Injection in Sequelize raw query",
+			Severity:    "high",
+			Description: "Concatenating or interpolating values into sequelize.query() bypasses replacements and bind parameters",
+			Category:    "injection",
+			CWE:         "CWE-89",
+			CodePatterns: []string{
+				`sequelize\.query\s*\(\s*['\"][^'\"]*['\"]\s*\+`,
+				"sequelize\\.query\\s*\\(\\s*`[^`]*\\$\\{",
+			},
+			References: []string{
+				"https://sequelize.org/docs/v6/core-concepts/raw-queries/",
+			},
+		},
+		{
+			ID:          "JS014",
+			Name:        "Stack traces exposed to clients",
+			Severity:    "medium",
+			Description: "Sending err.stack in an Express response leaks internal details to clients",
+			Category:    "information-exposure",
+			CWE:         "CWE-209",
+			CodePatterns: []string{
+				`res\.(status\s*\([^)]*\)\s*\.)?(send|json|end|write)\s*\(.*\b(err|error|e)\.stack\b`,
+			},
+			References: []string{
+				"https://expressjs.com/en/guide/error-handling.html",
+				"https://owasp.org/www-community/Improper_Error_Handling",
+			},
+		},
+		{
+			ID:          "JS015",
+			Name:        "Unencrypted WebSocket connection",
+			Severity:    "medium",
+			Description: "WebSocket connections over ws:// are sent in plaintext; use wss:// instead",
+			Category:    "transport",
+			CWE:         "CWE-319",
+			CodePatterns: []string{
+				"new\\s+WebSocket\\s*\\(\\s*['\"`]ws://",
+			},
+			References: []string{
+				"https://owasp.org/www-project-web-security-testing-guide/latest/4-Web_Application_Security_Testing/11-Client-side_Testing/10-Testing_WebSockets",
+			},
+		},
+		{
+			ID:          "JS018",
+			Name:        "Predictable UUID",
+			Severity:    "medium",
+			Description: "UUIDs built from Math.random() or time-based v1 UUIDs are guessable and must not be used as tokens or secrets; use crypto.randomUUID()",
+			Category:    "crypto",
+			CWE:         "CWE-340",
+			CodePatterns: []string{
+				`x{8}-x{4}-4x{3}-[xy]x{3}-x{12}`,
+				`(?i)uuid.*Math\.random\s*\(`,
+				`(?i)(token|secret|session|nonce|reset|key)\w*\s*[:=].*\b(uuid\.v1|uuidv1)\s*\(`,
+			},
+			References: []string{
+				"https://developer.mozilla.org/en-US/docs/Web/API/Crypto/randomUUID",
+			},
+		},
+	}
+}
+
+// Signatures of the checks that are not driven by the signatures' code patterns
+var (
+	jsConsoleLogSignature = core.Signature{
+		ID:          "JS011",
+		Name:        "Console logging in production",
+		Severity:    "low",
+		Description: "Console logging should be removed from production code",
+		Category:    "information-exposure",
+		CWE:         "CWE-532",
+		CodePatterns: []string{
+			`console\.log\s*\(`,
+		},
+	}
+	jsAlertSignature = core.Signature{
+		ID:          "JS012",
+		Name:        "Alert in production",
+		Severity:    "low",
+		Description: "Alert dialogs should be removed from production code",
+		Category:    "code-quality",
+		CWE:         "CWE-489",
+		CodePatterns: []string{
+			`alert\s*\(`,
+		},
 	}
+	jsWebSocketOriginSignature = core.Signature{
+		ID:          "JS016",
+		Name:        "WebSocket server accepts any origin",
+		Severity:    "medium",
+		Description: "WebSocket servers without a verifyClient or Origin header check allow cross-site WebSocket hijacking",
+		Category:    "configuration",
+		CWE:         "CWE-1385",
+		References: []string{
+			"https://github.com/websockets/ws/blob/master/doc/ws.md#new-websocketserveroptions-callback",
+		},
+	}
+	jsTOCTOUSignature = toctouSignature("JS017")
+)
+
+// calculateConfidence calculates the confidence of a match and the factors it is made of
+func (d *JavaScriptDetector) calculateConfidence(matchedCode string, pattern string) (float64, []core.ConfidenceFactor) {
+	return d.calculateContextConfidence(matchedCode, pattern, nil)
 }
 
-// calculateConfidence calculates the confidence of a match
-func (d *JavaScriptDetector) calculateConfidence(matchedCode string, pattern string) float64 {
+// calculateContextConfidence calculates the confidence of a match, taking the lines
+// around it into account, and the factors it is made of
+func (d *JavaScriptDetector) calculateContextConfidence(matchedCode string, pattern string, context []string) (float64, []core.ConfidenceFactor) {
 	// Base confidence
-	confidence := 0.8
+	factors := confidenceFactors{{Reason: "base confidence", Value: 0.8}}
 
 	// Adjust based on match length
 	if len(matchedCode) > 10 {
-		confidence += 0.05
+		factors.add("matched code is longer than 10 characters", 0.05)
 	}
 
 	// Adjust based on context
 	if strings.Contains(matchedCode, "import") || strings.Contains(matchedCode, "require") {
-		confidence += 0.05
+		factors.add("matched code contains an import or require", 0.05)
 	}
 
 	// Adjust based on pattern specificity
 	if len(pattern) > 20 {
-		confidence += 0.05
+		factors.add("pattern is longer than 20 characters", 0.05)
 	}
 
 	// Adjust based on function call parameters
 	if strings.Contains(matchedCode, "(") && strings.Contains(matchedCode, ")") {
-		confidence += 0.05
+		factors.add("matched code contains a function call", 0.05)
 	}
 
+	// Adjust based on the arguments and the surrounding code
+	factors.addContext(matchedCode, context)
+
 	// Ensure confidence is between 0 and 1
-	if confidence > 1.0 {
-		confidence = 1.0
-	}
+	confidence := factors.total()
 
-	return confidence
+	return confidence, factors
 }
 
 // checkJavaScriptSpecificIssues performs additional JavaScript-specific checks
@@ -272,15 +484,7 @@ func (d *JavaScriptDetector) checkJavaScriptSpecificIssues(code string, filePath
 		matchedCode := code[match[0]:match[1]] + "...)"
 
 		matches = append(matches, core.Match{
-			Signature: core.Signature{
-				ID:          "JS011",
-				Name:        "Console logging in production",
-				Severity:    "low",
-				Description: "Console logging should be removed from production code",
-				CodePatterns: []string{
-					`console\.log\s*\(`,
-				},
-			},
+			Signature:   jsConsoleLogSignature,
 			FilePath:    filePath,
 			LineNumber:  lineNumber,
 			MatchedCode: matchedCode,
@@ -297,15 +501,7 @@ func (d *JavaScriptDetector) checkJavaScriptSpecificIssues(code string, filePath
 		matchedCode := code[match[0]:match[1]] + "...)"
 
 		matches = append(matches, core.Match{
-			Signature: core.Signature{
-				ID:          "JS012",
-				Name:        "Alert in production",
-				Severity:    "low",
-				Description: "Alert dialogs should be removed from production code",
-				CodePatterns: []string{
-					`alert\s*\(`,
-				},
-			},
+			Signature:   jsAlertSignature,
 			FilePath:    filePath,
 			LineNumber:  lineNumber,
 			MatchedCode: matchedCode,
@@ -313,5 +509,26 @@ func (d *JavaScriptDetector) checkJavaScriptSpecificIssues(code string, filePath
 		})
 	}
 
+	// Check for WebSocket servers that never look at the Origin header
+	wsServerRe := regexp.MustCompile(`new\s+(WebSocket\.Server|WebSocketServer|WebSocket\.WebSocketServer)\s*\(`)
+	if !strings.Contains(code, "verifyClient") && !strings.Contains(code, "origin") {
+		for _, match := range wsServerRe.FindAllStringIndex(code, -1) {
+			// Count line number
+			lineNumber := 1 + strings.Count(code[:match[0]], "\n")
+			matchedCode := code[match[0]:match[1]] + "...)"
+
+			matches = append(matches, core.Match{
+				Signature:   jsWebSocketOriginSignature,
+				FilePath:    filePath,
+				LineNumber:  lineNumber,
+				MatchedCode: matchedCode,
+				Confidence:  0.75,
+			})
+		}
+	}
+
+	// Check for files that are checked before they are opened
+	matches = append(matches, findTOCTOU(code, filePath, jsTOCTOUSignature, javascriptTOCTOU)...)
+
 	return matches
 } 
\ No newline at end of file
@@ -0,0 +1,164 @@
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试Sequelize原始查询的SQL注入检测
+func TestJavaScriptSequelizeQuery(t *testing.T) {
+	detector := NewJavaScriptDetector()
+
+	safe := `sequelize.query("SELECT * FROM users WHERE id = ?", { replacements: [id] })`
+	matches, err := detector.DetectCode(safe, "app.js")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "JS013"))
+
+	unsafe := "sequelize.query(`SELECT * FROM users WHERE id = ${req.params.id}`)"
+	matches, err = detector.DetectCode(unsafe, "app.js")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "JS013"))
+
+	unsafe = `sequelize.query("SELECT * FROM users WHERE id = " + id)`
+	matches, err = detector.DetectCode(unsafe, "app.js")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "JS013"))
+}
+
+// 测试Express错误处理中泄露堆栈信息的检测
+func TestJavaScriptStackTraceExposure(t *testing.T) {
+	detector := NewJavaScriptDetector()
+
+	unsafe := `app.use((err, req, res, next) => { res.status(500).send(err.stack) })`
+	matches, err := detector.DetectCode(unsafe, "app.js")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "JS014"))
+
+	safe := `app.use((err, req, res, next) => { console.error(err.stack); res.status(500).send("Internal Server Error") })`
+	matches, err = detector.DetectCode(safe, "app.js")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "JS014"))
+}
+
+// 测试WebSocket服务端来源检查和未加密连接检测
+func TestJavaScriptWebSocket(t *testing.T) {
+	detector := NewJavaScriptDetector()
+
+	unsafe := `const WebSocket = require('ws');
+const wss = new WebSocket.Server({ port: 8080 });`
+	matches, err := detector.DetectCode(unsafe, "server.js")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "JS016"))
+
+	safe := `const WebSocket = require('ws');
+const wss = new WebSocket.Server({ port: 8080, verifyClient: (info) => allowed.includes(info.origin) });`
+	matches, err = detector.DetectCode(safe, "server.js")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "JS016"))
+
+	matches, err = detector.DetectCode(`const socket = new WebSocket("ws://example.com/feed");`, "client.js")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "JS015"))
+
+	matches, err = detector.DetectCode(`const socket = new WebSocket("wss://example.com/feed");`, "client.js")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "JS015"))
+}
+
+// 测试先检查后打开文件的竞态条件检测
+func TestJavaScriptTOCTOU(t *testing.T) {
+	detector := NewJavaScriptDetector()
+
+	unsafe := `if (fs.existsSync(file)) {
+  const data = fs.readFileSync(file, 'utf8');
+}`
+	matches, err := detector.DetectCode(unsafe, "app.js")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "JS017"))
+
+	safe := `const data = fs.readFileSync(file, 'utf8');`
+	matches, err = detector.DetectCode(safe, "app.js")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "JS017"))
+}
+
+// 测试使用Math.random()或v1生成UUID的检测
+func TestJavaScriptPredictableUUID(t *testing.T) {
+	detector := NewJavaScriptDetector()
+
+	for _, code := range []string{
+		`return 'xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx'.replace(/[xy]/g, c => { const r = Math.random() * 16 | 0; return r.toString(16); });`,
+		`const uuid = Math.random().toString(36).slice(2);`,
+		`const resetToken = uuid.v1();`,
+		`session: uuidv1(),`,
+	} {
+		matches, err := detector.DetectCode(code, "app.js")
+		assert.NoError(t, err)
+		assert.True(t, hasSignature(matches, "JS018"), code)
+	}
+
+	for _, code := range []string{
+		`const id = crypto.randomUUID();`,
+		`const requestId = uuid.v1();`,
+		`const resetToken = uuid.v4();`,
+	} {
+		matches, err := detector.DetectCode(code, "app.js")
+		assert.NoError(t, err)
+		assert.False(t, hasSignature(matches, "JS018"), code)
+	}
+}
+
+// 测试TypeScript专有规则只对.ts和.tsx文件生效
+func TestTypeScriptSignatures(t *testing.T) {
+	detector := NewJavaScriptDetector()
+
+	code := `const payload: any = JSON.parse(req.body.data);
+const body = req.body as any;
+// @ts-ignore
+const user = input as unknown as User;
+eval(payload.script);
+`
+	matches, err := detector.DetectCode(code, "handler.ts")
+	assert.NoError(t, err)
+
+	evaluated := findSignature(matches, "TS001")
+	if assert.NotNil(t, evaluated) {
+		assert.Equal(t, 5, evaluated.LineNumber)
+		assert.Equal(t, "eval(payload.script);", evaluated.MatchedCode)
+	}
+	ignored := findSignature(matches, "TS002")
+	if assert.NotNil(t, ignored) {
+		assert.Equal(t, 3, ignored.LineNumber)
+	}
+	cast := findSignature(matches, "TS003")
+	if assert.NotNil(t, cast) {
+		assert.Equal(t, 2, cast.LineNumber)
+	}
+
+	// .tsx文件同样适用
+	matches, err = detector.DetectCode(code, "Component.tsx")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "TS003"))
+
+	// 普通JavaScript文件不触发TypeScript规则
+	matches, err = detector.DetectCode(code, "handler.js")
+	assert.NoError(t, err)
+	for _, id := range []string{"TS001", "TS002", "TS003"} {
+		assert.False(t, hasSignature(matches, id), id)
+	}
+	assert.True(t, hasSignature(matches, "JS001"))
+
+	// 有类型的解析结果和未传入eval的结果不触发
+	for _, safe := range []string{
+		"const payload: Payload = JSON.parse(data);\neval(payload.script);\n",
+		"const payload: any = JSON.parse(data);\nconsole.info(payload);\neval(other);\n",
+		"// @ts-ignore\nconst user = input as User;\n",
+	} {
+		matches, err = detector.DetectCode(safe, "handler.ts")
+		assert.NoError(t, err)
+		for _, id := range []string{"TS001", "TS002"} {
+			assert.False(t, hasSignature(matches, id), safe)
+		}
+	}
+}
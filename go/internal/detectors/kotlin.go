@@ -0,0 +1,226 @@
+package detectors
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// KotlinDetector is a detector for Kotlin code on Android and the JVM
+type KotlinDetector struct {
+	core.BaseDetector
+	signatures []core.Signature
+}
+
+// NewKotlinDetector creates a new Kotlin detector
+func NewKotlinDetector() *KotlinDetector {
+	detector := &KotlinDetector{}
+	detector.loadSignatures()
+	return detector
+}
+
+// Name returns the name of the detector
+func (d *KotlinDetector) Name() string {
+	return "kotlin"
+}
+
+// SupportedLanguages returns the list of supported languages
+func (d *KotlinDetector) SupportedLanguages() []string {
+	return []string{"kt", "kts"}
+}
+
+// Signatures returns every signature the detector can report
+func (d *KotlinDetector) Signatures() []core.Signature {
+	return d.signatures
+}
+
+// DetectFile detects vulnerabilities in a file
+func (d *KotlinDetector) DetectFile(filePath string) ([]core.Match, error) {
+	// Check if file is a Kotlin file
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext != ".kt" && ext != ".kts" {
+		return nil, nil
+	}
+
+	// Read file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectCode(string(content), filePath)
+}
+
+var (
+	kotlinExecRe       = regexp.MustCompile(`\b(Runtime\.getRuntime\(\)\s*\.\s*exec|ProcessBuilder)\s*\(`)
+	kotlinJavaScriptRe = regexp.MustCompile(`\b(setJavaScriptEnabled\s*\(\s*true\s*\)|javaScriptEnabled\s*=\s*true)`)
+	kotlinLoadURLRe    = regexp.MustCompile(`\bloadUrl\s*\(`)
+	kotlinPutSecretRe  = regexp.MustCompile(`(?i)\bputString\s*\(\s*"?[\w.-]*(passw(or)?d|secret|token|api_?key|private_?key|credential)`)
+	kotlinPrefsRe      = regexp.MustCompile(`\b(SharedPreferences|getSharedPreferences|getPreferences|PreferenceManager)\b`)
+)
+
+// DetectReader detects vulnerabilities in code read from r
+func (d *KotlinDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return core.ReadAndDetect(d, r, filePath)
+}
+
+// DetectCode detects vulnerabilities in code
+func (d *KotlinDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches := []core.Match{}
+
+	// JavaScript in a WebView is only a risk if the file loads URLs into it;
+	// loading only literal URLs is less likely to load attacker content
+	loadsURL, literalURLs := false, true
+	for _, loc := range kotlinLoadURLRe.FindAllStringIndex(code, -1) {
+		loadsURL = true
+		if args := cCallArgs(code[loc[1]:]); len(args) == 0 || !isKotlinLiteral(args[0]) {
+			literalURLs = false
+		}
+	}
+
+	// Values stored with EncryptedSharedPreferences are encrypted at rest
+	plainPrefs := kotlinPrefsRe.MatchString(code) && !strings.Contains(code, "EncryptedSharedPreferences")
+
+	inComment := false
+	for i, line := range strings.Split(code, "\n") {
+		lineNumber := i + 1
+
+		// Skip comments
+		trimmed := strings.TrimSpace(line)
+		if inComment {
+			if strings.Contains(trimmed, "*/") {
+				inComment = false
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "/*") {
+			inComment = !strings.Contains(trimmed, "*/")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		// A command made of literals cannot be injected into
+		if loc := kotlinExecRe.FindStringIndex(line); loc != nil {
+			literal := true
+			for _, arg := range cCallArgs(line[loc[1]:]) {
+				literal = literal && isKotlinLiteral(arg)
+			}
+			matches = append(matches, d.newMatch(0, filePath, lineNumber, line, literal))
+		}
+
+		if loadsURL && kotlinJavaScriptRe.MatchString(line) {
+			matches = append(matches, d.newMatch(1, filePath, lineNumber, line, literalURLs))
+		}
+
+		if plainPrefs && kotlinPutSecretRe.MatchString(line) {
+			matches = append(matches, d.newMatch(3, filePath, lineNumber, line, false))
+		}
+	}
+
+	// Trust managers span several lines
+	matches = append(matches, matchMultiline(d.signatures, code, filePath, func(matchedCode string, pattern string) (float64, []core.ConfidenceFactor) {
+		return d.calculateConfidence(false)
+	})...)
+
+	return matches, nil
+}
+
+// loadSignatures loads the signatures for Kotlin code
+func (d *KotlinDetector) loadSignatures() {
+	d.signatures = []core.Signature{
+		{
+			ID:          "KT001",
+			Name:        "Command execution",
+			Severity:    "high",
+			Description: "Runtime.exec() and ProcessBuilder run commands that allow command injection when built from input; pass a fixed program and validated arguments",
+			CodePatterns: []string{
+				`\b(Runtime\.getRuntime\(\)\s*\.\s*exec|ProcessBuilder)\s*\(`,
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/78.html",
+			},
+		},
+		{
+			ID:          "KT002",
+			Name:        "JavaScript enabled in a WebView",
+			Severity:    "medium",
+			Description: "A WebView with JavaScript enabled that loads URLs runs the scripts of every page it loads, which allows cross-site scripting and access to any JavaScript interfaces; only enable it for trusted content",
+			CodePatterns: []string{
+				`\b(setJavaScriptEnabled\s*\(\s*true\s*\)|javaScriptEnabled\s*=\s*true)`,
+			},
+			References: []string{
+				"https://developer.android.com/privacy-and-security/risks/unsafe-webview",
+			},
+		},
+		{
+			ID:          "KT003",
+			Name:        "TLS certificate validation disabled",
+			Severity:    "high",
+			Description: "A TrustManager whose checkServerTrusted() does nothing, or a HostnameVerifier that always returns true, accepts any certificate and allows man-in-the-middle attacks",
+			CodePatterns: []string{
+				`override\s+fun\s+checkServerTrusted\s*\([^)]*\)(\s*:\s*Unit)?\s*(\{\s*\}|=\s*Unit\b)`,
+				`HostnameVerifier\s*\{\s*_\s*,\s*_\s*->\s*true\s*\}`,
+			},
+			Multiline: true,
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/295.html",
+			},
+		},
+		{
+			ID:          "KT004",
+			Name:        "Secret stored in plaintext SharedPreferences",
+			Severity:    "medium",
+			Description: "SharedPreferences are stored unencrypted on the device, so passwords and tokens stored in them can be read from backups or rooted devices; use EncryptedSharedPreferences or the Android Keystore",
+			CodePatterns: []string{
+				`(?i)\bputString\s*\(\s*"?[\w.-]*(passw(or)?d|secret|token|api_?key|private_?key|credential)`,
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/312.html",
+			},
+		},
+	}
+}
+
+// calculateConfidence calculates the confidence of a match and the factors it is made of
+func (d *KotlinDetector) calculateConfidence(literal bool) (float64, []core.ConfidenceFactor) {
+	// Base confidence
+	factors := confidenceFactors{{Reason: "base confidence", Value: 0.85}}
+
+	// Literals without string templates are fixed by the code
+	if literal {
+		factors.add("argument is a string literal", -0.35)
+	}
+
+	// Ensure confidence is between 0 and 1
+	confidence := factors.total()
+
+	return confidence, factors
+}
+
+// newMatch creates a match of the signature at index for a line
+func (d *KotlinDetector) newMatch(index int, filePath string, lineNumber int, line string, literal bool) core.Match {
+	signature := d.signatures[index]
+	confidence, factors := d.calculateConfidence(literal)
+	return core.Match{
+		Signature:   signature,
+		FilePath:    filePath,
+		LineNumber:  lineNumber,
+		MatchedCode: strings.TrimSpace(line),
+		Confidence:  confidence,
+		Explanation: &core.Explanation{
+			Pattern: signature.CodePatterns[0],
+			Factors: factors,
+		},
+	}
+}
+
+// isKotlinLiteral reports whether an argument is a string literal without string templates
+func isKotlinLiteral(arg string) bool {
+	return strings.HasPrefix(arg, `"`) && !strings.Contains(arg, "$")
+}
@@ -0,0 +1,125 @@
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 存在漏洞的Kotlin代码
+const vulnerableKotlin = `package com.example.app
+
+import android.content.Context
+import android.webkit.WebView
+import javax.net.ssl.X509TrustManager
+
+class Insecure(private val context: Context) {
+    fun run(cmd: String) {
+        Runtime.getRuntime().exec(cmd)
+        ProcessBuilder("sh", "-c", "ls $cmd").start()
+    }
+
+    fun show(webView: WebView, url: String) {
+        webView.settings.javaScriptEnabled = true
+        webView.loadUrl(url)
+    }
+
+    val trustAll = object : X509TrustManager {
+        override fun checkClientTrusted(chain: Array<X509Certificate>, authType: String) {}
+        override fun checkServerTrusted(
+            chain: Array<X509Certificate>,
+            authType: String
+        ) {
+        }
+        override fun getAcceptedIssuers(): Array<X509Certificate> = arrayOf()
+    }
+
+    fun save(token: String) {
+        val prefs = context.getSharedPreferences("auth", Context.MODE_PRIVATE)
+        prefs.edit().putString("auth_token", token).apply()
+    }
+}
+`
+
+// 安全的Kotlin代码
+const safeKotlin = `package com.example.app
+
+import androidx.security.crypto.EncryptedSharedPreferences
+
+class Safe(private val context: Context) {
+    // Runtime.getRuntime().exec(cmd)
+    fun show(webView: WebView) {
+        webView.settings.javaScriptEnabled = false
+        webView.loadUrl("https://example.com/help")
+    }
+
+    override fun checkServerTrusted(chain: Array<X509Certificate>, authType: String) {
+        delegate.checkServerTrusted(chain, authType)
+    }
+
+    fun save(token: String) {
+        val prefs = EncryptedSharedPreferences.create(context, "auth", masterKey, keyScheme, valueScheme)
+        prefs.edit().putString("auth_token", token).apply()
+    }
+}
+`
+
+// 测试Kotlin规则
+func TestKotlinDetector(t *testing.T) {
+	detector := NewKotlinDetector()
+
+	matches, err := detector.DetectCode(vulnerableKotlin, "Insecure.kt")
+	assert.NoError(t, err)
+
+	for id, line := range map[string]int{"KT001": 9, "KT002": 14, "KT003": 20, "KT004": 30} {
+		match := findSignature(matches, id)
+		if assert.NotNil(t, match, id) {
+			assert.Equal(t, line, match.LineNumber, id)
+			assert.Equal(t, 0.85, match.Confidence, id)
+		}
+	}
+
+	// 包含字符串模板的命令也会被报告
+	count := 0
+	for _, m := range matches {
+		if m.Signature.ID == "KT001" {
+			count++
+		}
+	}
+	assert.Equal(t, 2, count)
+
+	matches, err = detector.DetectCode(safeKotlin, "Safe.kt")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+// 测试字面量参数降低置信度
+func TestKotlinDetectorLiterals(t *testing.T) {
+	detector := NewKotlinDetector()
+
+	matches, err := detector.DetectCode(`val process = ProcessBuilder("git", "status").start()`, "Build.kts")
+	assert.NoError(t, err)
+	match := findSignature(matches, "KT001")
+	if assert.NotNil(t, match) {
+		assert.InDelta(t, 0.5, match.Confidence, 0.0001)
+	}
+
+	// 只加载字面量URL
+	code := "webView.settings.setJavaScriptEnabled(true)\nwebView.loadUrl(\"file:///android_asset/index.html\")\n"
+	matches, err = detector.DetectCode(code, "Help.kt")
+	assert.NoError(t, err)
+	match = findSignature(matches, "KT002")
+	if assert.NotNil(t, match) {
+		assert.InDelta(t, 0.5, match.Confidence, 0.0001)
+	}
+
+	// 不加载URL时不报告启用JavaScript
+	matches, err = detector.DetectCode("webView.settings.javaScriptEnabled = true\n", "Help.kt")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "KT002"))
+
+	// 总是返回true的主机名校验器
+	matches, err = detector.DetectCode("connection.hostnameVerifier = HostnameVerifier { _, _ -> true }\n", "Client.kt")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "KT003"))
+}
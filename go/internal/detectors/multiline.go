@@ -0,0 +1,102 @@
+package detectors
+
+import (
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// matchMultiline matches the patterns of the multi-line signatures against code as a
+// whole, with . also matching newlines, and reports each match at the line it starts on.
+// Single-line signatures are skipped; they are matched line by line.
+func matchMultiline(signatures []core.Signature, code string, filePath string, calculateConfidence func(matchedCode string, pattern string) (float64, []core.ConfidenceFactor)) []core.Match {
+	matches := []core.Match{}
+
+	for _, signature := range signatures {
+		if !signature.Multiline {
+			continue
+		}
+
+		for _, pattern := range signature.CodePatterns {
+			re, err := compilePattern("(?s)" + pattern)
+			if err != nil {
+				continue
+			}
+
+			for _, loc := range re.re.FindAllStringIndex(code, -1) {
+				// Report the matched lines in full
+				end := loc[1]
+				if end > loc[0] && code[end-1] == '\n' {
+					end--
+				} else if i := strings.IndexByte(code[end:], '\n'); i >= 0 {
+					end += i
+				} else {
+					end = len(code)
+				}
+				matchedCode := code[loc[0]:end]
+				confidence, factors := calculateConfidence(matchedCode, pattern)
+				matches = append(matches, core.Match{
+					Signature:   signature,
+					FilePath:    filePath,
+					LineNumber:  1 + strings.Count(code[:loc[0]], "\n"),
+					MatchedCode: matchedCode,
+					Confidence:  confidence,
+					Explanation: &core.Explanation{
+						Pattern: pattern,
+						Factors: factors,
+					},
+				})
+			}
+		}
+	}
+
+	return matches
+}
+
+// multilineBuffer keeps the lines of streamed code so that the multi-line signatures are
+// matched once against the whole code, however far apart the lines of a match are.
+// Lines are only kept if there are multi-line signatures.
+type multilineBuffer struct {
+	signatures          []core.Signature
+	filePath            string
+	calculateConfidence func(matchedCode string, pattern string) (float64, []core.ConfidenceFactor)
+	enabled             bool
+	code                strings.Builder
+	lines               int
+}
+
+// newMultilineBuffer creates a buffer that matches the multi-line signatures of signatures
+func newMultilineBuffer(signatures []core.Signature, filePath string, calculateConfidence func(matchedCode string, pattern string) (float64, []core.ConfidenceFactor)) *multilineBuffer {
+	b := &multilineBuffer{
+		signatures:          signatures,
+		filePath:            filePath,
+		calculateConfidence: calculateConfidence,
+	}
+	for _, signature := range signatures {
+		if signature.Multiline {
+			b.enabled = true
+			break
+		}
+	}
+	return b
+}
+
+// add appends a line
+func (b *multilineBuffer) add(line string) {
+	if !b.enabled {
+		return
+	}
+	if b.lines > 0 {
+		b.code.WriteByte('\n')
+	}
+	b.code.WriteString(line)
+	b.lines++
+}
+
+// close matches the multi-line signatures against the buffered code
+func (b *multilineBuffer) close() []core.Match {
+	if !b.enabled {
+		return nil
+	}
+	return matchMultiline(b.signatures, b.code.String(), b.filePath, b.calculateConfidence)
+}
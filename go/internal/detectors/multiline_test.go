@@ -0,0 +1,72 @@
+package detectors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试多行签名针对整段代码匹配，并按字节偏移计算起始行号
+func TestMatchMultiline(t *testing.T) {
+	signatures := []core.Signature{
+		{ID: "ML001", CodePatterns: []string{`try:\n\s*\w+\(\)\n\s*except:.*?pass`}, Multiline: true},
+		{ID: "ML002", CodePatterns: []string{`try:`}},
+	}
+	confidence := func(matchedCode string, pattern string) (float64, []core.ConfidenceFactor) {
+		return 0.8, nil
+	}
+
+	code := "import os\n\ntry:\n    run()\nexcept:\n    # ignore\n    pass\n"
+	matches := matchMultiline(signatures, code, "app.py", confidence)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "ML001", matches[0].Signature.ID)
+		assert.Equal(t, 3, matches[0].LineNumber)
+		assert.Equal(t, "try:\n    run()\nexcept:\n    # ignore\n    pass", matches[0].MatchedCode)
+	}
+
+	// 多行签名不在逐行匹配中触发
+	detector := NewGoDetector()
+	detector.signatures = []core.Signature{
+		{ID: "ML003", CodePatterns: []string{`defer\s+\w+\.Close\(\)\n\s*return`}, Multiline: true},
+	}
+	matches, err := detector.DetectCode("package main\n\nfunc f() {\n\tdefer f.Close()\n\treturn\n}\n", "main.go")
+	assert.NoError(t, err)
+	match := findSignature(matches, "ML003")
+	if assert.NotNil(t, match) {
+		assert.Equal(t, 4, match.LineNumber)
+	}
+}
+
+// 测试流式扫描时多行签名针对整个文件匹配一次，跨度不受窗口限制
+func TestMultilineAcrossWindows(t *testing.T) {
+	pattern := `begin_transaction\(\).*?rollback\(\)`
+	detector := NewPythonDetector()
+	detector.signatures = []core.Signature{
+		{ID: "ML004", CodePatterns: []string{pattern}, Multiline: true},
+	}
+
+	// 匹配从第170行跨到第230行，超过窗口之间的重叠
+	lines := make([]string, 240)
+	for i := range lines {
+		lines[i] = "x = 1"
+	}
+	lines[169] = "begin_transaction()"
+	lines[229] = "rollback()"
+	matches, err := detector.DetectReader(strings.NewReader(strings.Join(lines, "\n")), "app.py")
+	assert.NoError(t, err)
+	count := 0
+	for _, match := range matches {
+		if match.Signature.ID == "ML004" {
+			count++
+			assert.Equal(t, 170, match.LineNumber)
+		}
+	}
+	assert.Equal(t, 1, count)
+
+	// 编译后的多行模式被缓存
+	_, ok := patternCache.Load("(?s)" + pattern)
+	assert.True(t, ok)
+}
@@ -0,0 +1,142 @@
+package detectors
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// patternKind is how a pattern is matched against a line
+type patternKind int
+
+const (
+	// regexpPattern is matched with the regexp engine
+	regexpPattern patternKind = iota
+	// containsPattern is a literal that may appear anywhere in the line
+	containsPattern
+	// prefixPattern is a literal anchored at the start of the line, e.g. ^import os
+	prefixPattern
+	// suffixPattern is a literal anchored at the end of the line
+	suffixPattern
+	// equalPattern is a literal anchored at both ends
+	equalPattern
+)
+
+// pattern is a compiled signature pattern. Literal patterns, such as eval\( or
+// ^import pickle, are matched with string functions instead of the regexp engine. Other
+// patterns skip the regexp engine for lines that lack a literal every match contains.
+// Either way the result is the same as matching the regexp.
+type pattern struct {
+	re      *regexp.Regexp
+	kind    patternKind
+	literal string
+}
+
+// patternCache holds the patterns compiled so far, as signatures are matched line by line
+var patternCache sync.Map
+
+// compilePattern compiles a signature pattern, or returns it from the cache if it was
+// compiled before
+func compilePattern(expr string) (*pattern, error) {
+	if cached, ok := patternCache.Load(expr); ok {
+		return cached.(*pattern), nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &pattern{re: re}
+	if parsed, err := syntax.Parse(expr, syntax.Perl); err == nil {
+		p.kind, p.literal = classifyPattern(parsed.Simplify())
+	}
+
+	patternCache.Store(expr, p)
+	return p, nil
+}
+
+// MatchString reports whether the line contains a match of the pattern
+func (p *pattern) MatchString(line string) bool {
+	switch p.kind {
+	case containsPattern:
+		return strings.Contains(line, p.literal)
+	case prefixPattern:
+		return strings.HasPrefix(line, p.literal)
+	case suffixPattern:
+		return strings.HasSuffix(line, p.literal)
+	case equalPattern:
+		return line == p.literal
+	}
+
+	if p.literal != "" && !strings.Contains(line, p.literal) {
+		return false
+	}
+	return p.re.MatchString(line)
+}
+
+// classifyPattern returns how a parsed pattern can be matched: as a literal, possibly
+// anchored, or with the regexp engine, along with the literal every match contains
+func classifyPattern(re *syntax.Regexp) (patternKind, string) {
+	if literal, ok := literalOf(re); ok {
+		return containsPattern, literal
+	}
+
+	if re.Op == syntax.OpConcat && len(re.Sub) >= 2 && len(re.Sub) <= 3 {
+		subs := re.Sub
+		begin := subs[0].Op == syntax.OpBeginText
+		end := subs[len(subs)-1].Op == syntax.OpEndText
+		switch {
+		case begin && end && len(subs) == 3:
+			if literal, ok := literalOf(subs[1]); ok {
+				return equalPattern, literal
+			}
+		case begin && len(subs) == 2:
+			if literal, ok := literalOf(subs[1]); ok {
+				return prefixPattern, literal
+			}
+		case end && len(subs) == 2:
+			if literal, ok := literalOf(subs[0]); ok {
+				return suffixPattern, literal
+			}
+		}
+	}
+
+	return regexpPattern, requiredLiteral(re)
+}
+
+// literalOf returns the string a literal node matches. Case-insensitive literals are
+// left to the regexp engine, as are literals with U+FFFD, which the engine also
+// matches against invalid UTF-8.
+func literalOf(re *syntax.Regexp) (string, bool) {
+	if re.Op != syntax.OpLiteral || re.Flags&syntax.FoldCase != 0 {
+		return "", false
+	}
+	literal := string(re.Rune)
+	if literal == "" || strings.ContainsRune(literal, utf8.RuneError) {
+		return "", false
+	}
+	return literal, true
+}
+
+// requiredLiteral returns the longest literal that every match of a parsed pattern
+// contains, or "" if there is none
+func requiredLiteral(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		literal, _ := literalOf(re)
+		return literal
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiteral(re.Sub[0])
+	case syntax.OpConcat:
+		longest := ""
+		for _, sub := range re.Sub {
+			if literal := requiredLiteral(sub); len(literal) > len(longest) {
+				longest = literal
+			}
+		}
+		return longest
+	}
+	return ""
+}
@@ -1,7 +1,7 @@
 package detectors
 
 import (
-	"bufio"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -13,14 +13,29 @@ import (
 
 // PythonDetector is a detector for Python code
 type PythonDetector struct {
-	signatures []core.Signature
+	core.BaseDetector
+	streamLimits
+	signatures  []core.Signature
+	astAnalysis bool
+
+	// builtinChecks enables the checks that are not signatures, e.g. PY011
+	builtinChecks bool
 }
 
 // NewPythonDetector creates a new Python detector
 func NewPythonDetector() *PythonDetector {
-	detector := &PythonDetector{}
-	detector.loadSignatures()
-	return detector
+	return &PythonDetector{
+		streamLimits:  defaultStreamLimits(),
+		signatures:    pythonSignatures(),
+		builtinChecks: true,
+	}
+}
+
+// ClearSignatures removes the built-in signatures and checks, so that the detector
+// no longer reports anything. It is used when only custom signatures should run.
+func (d *PythonDetector) ClearSignatures() {
+	d.signatures = nil
+	d.builtinChecks = false
 }
 
 // Name returns the name of the detector
@@ -33,6 +48,14 @@ func (d *PythonDetector) SupportedLanguages() []string {
 	return []string{"python", "py"}
 }
 
+// Signatures returns every signature the detector can report
+func (d *PythonDetector) Signatures() []core.Signature {
+	if !d.builtinChecks {
+		return append([]core.Signature{}, d.signatures...)
+	}
+	return append(append([]core.Signature{}, d.signatures...), pyEmptyExceptSignature, pyBareExceptSignature, pyTOCTOUSignature, pyShellSignature)
+}
+
 // DetectFile detects vulnerabilities in a file
 func (d *PythonDetector) DetectFile(filePath string) ([]core.Match, error) {
 	// Check if file is a Python file
@@ -40,74 +63,142 @@ func (d *PythonDetector) DetectFile(filePath string) ([]core.Match, error) {
 		return nil, nil
 	}
 
-	// Read file
-	content, err := ioutil.ReadFile(filePath)
+	// Skip files above the size limit
+	if skip, err := d.skipLargeFile(filePath); skip || err != nil {
+		return nil, err
+	}
+
+	// AST analysis needs the whole file
+	if d.astAnalysis {
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		return d.DetectCode(string(content), filePath)
+	}
+
+	// Open file
+	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
+
+	return d.detectReader(file, filePath)
+}
 
-	return d.DetectCode(string(content), filePath)
+// DetectReader detects vulnerabilities in code read from r. The code is streamed line
+// by line unless AST analysis, which needs the whole code, is enabled.
+func (d *PythonDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	if d.astAnalysis && d.builtinChecks {
+		return core.ReadAndDetect(d, r, filePath)
+	}
+	return d.detectReader(r, filePath)
 }
 
 // DetectCode detects vulnerabilities in code
 func (d *PythonDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches, err := d.detectReader(strings.NewReader(code), filePath)
+	if err != nil || !d.astAnalysis || !d.builtinChecks {
+		return matches, err
+	}
+	return d.applyAST(code, filePath, matches), nil
+}
+
+// detectReader detects vulnerabilities in code streamed line by line,
+// so that memory use is bounded by the line size instead of the file size. Only
+// multi-line signatures, which are matched against the whole code, keep every line.
+func (d *PythonDetector) detectReader(r io.Reader, filePath string) ([]core.Match, error) {
 	matches := []core.Match{}
 
-	// Scan code line by line
-	scanner := bufio.NewScanner(strings.NewReader(code))
-	lineNumber := 0
-	for scanner.Scan() {
-		lineNumber++
-		line := scanner.Text()
+	// Multi-line checks only see a bounded window of lines
+	window := newLineWindow(filePath, func(code string, filePath string) []core.Match {
+		matches := []core.Match{}
+		if d.builtinChecks {
+			matches = d.checkPythonSpecificIssues(code, filePath)
+		}
+		return matches
+	})
+
+	// Multi-line signatures are matched once against the whole code
+	multiline := newMultilineBuffer(d.signatures, filePath, d.calculateConfidence)
 
-		// Check each signature
+	// Single-line matches are scored once the lines around them have been read
+	buffer := newContextBuffer(d.calculateContextConfidence)
+
+	// Scan code line by line
+	err := d.scanLines(r, func(lineNumber int, line string) {
+		// Check each single-line signature
+		lineMatches := []core.Match{}
 		for _, signature := range d.signatures {
+			if signature.Multiline {
+				continue
+			}
 			for _, pattern := range signature.CodePatterns {
-				re, err := regexp.Compile(pattern)
+				re, err := compilePattern(pattern)
 				if err != nil {
 					continue
 				}
 
 				if re.MatchString(line) {
-					match := core.Match{
+					lineMatches = append(lineMatches, core.Match{
 						Signature:   signature,
 						FilePath:    filePath,
 						LineNumber:  lineNumber,
 						MatchedCode: line,
-						Confidence:  d.calculateConfidence(line, pattern),
-					}
-					matches = append(matches, match)
+						Explanation: &core.Explanation{
+							Pattern: pattern,
+						},
+					})
 				}
 			}
 		}
+
+		buffer.add(line, lineMatches)
+		window.add(line)
+		multiline.add(line)
+	})
+	if err != nil {
+		return nil, err
 	}
+	matches = append(matches, buffer.close()...)
+	matches = append(matches, multiline.close()...)
 
 	// Perform additional Python-specific checks
-	matches = append(matches, d.checkPythonSpecificIssues(code, filePath)...)
+	matches = append(matches, window.close()...)
 
 	return matches, nil
 }
 
-// loadSignatures loads the signatures for Python code
-func (d *PythonDetector) loadSignatures() {
-	d.signatures = []core.Signature{
+// pythonSignatures returns the built-in signatures for Python code
+func pythonSignatures() []core.Signature {
+	return []core.Signature{
 		{
 			ID:          "PY001",
 			Name:        "Dangerous eval() usage",
 			Severity:    "high",
 			Description: "Using eval() can execute arbitrary code and is a security risk",
+			Category:    "injection",
+			CWE:         "CWE-95",
 			CodePatterns: []string{
 				`eval\s*\([^)]*\)`,
 			},
 			References: []string{
 				"https://docs.python.org/3/library/functions.html#eval",
 			},
+			Docs: &core.SignatureDocs{
+				VulnerableExample: `result = eval(request.args["expr"])`,
+				SafeExample:       `result = ast.literal_eval(request.args["expr"])`,
+				Remediation:       "Parse literals with ast.literal_eval, or map the allowed inputs to functions, instead of evaluating input as code.",
+			},
 		},
 		{
 			ID:          "PY002",
 			Name:        "Dangerous exec() usage",
 			Severity:    "high",
 			Description: "Using exec() can execute arbitrary code and is a security risk",
+			Category:    "injection",
+			CWE:         "CWE-95",
 			CodePatterns: []string{
 				`exec\s*\([^)]*\)`,
 			},
@@ -120,6 +211,8 @@ func (d *PythonDetector) loadSignatures() {
 			Name:        "Insecure pickle usage",
 			Severity:    "high",
 			Description: "Using pickle with untrusted data can lead to arbitrary code execution",
+			Category:    "deserialization",
+			CWE:         "CWE-502",
 			CodePatterns: []string{
 				`pickle\.loads\s*\([^)]*\)`,
 				`pickle\.load\s*\([^)]*\)`,
@@ -133,6 +226,8 @@ func (d *PythonDetector) loadSignatures() {
 			Name:        "SQL Injection risk",
 			Severity:    "high",
 			Description: "String formatting in SQL queries can lead to SQL injection",
+			Category:    "injection",
+			CWE:         "CWE-89",
 			CodePatterns: []string{
 				`execute\s*\(['\"][^'\"]*%[^'\"]*['\"]`,
 				`execute\s*\(['\"][^'\"]*\{\s*[^}]*\}[^'\"]*['\"]\.format`,
@@ -147,6 +242,8 @@ func (d *PythonDetector) loadSignatures() {
 			Name:        "Insecure random number generation",
 			Severity:    "medium",
 			Description: "Using random module for security purposes is not recommended",
+			Category:    "crypto",
+			CWE:         "CWE-330",
 			CodePatterns: []string{
 				`random\.(?:random|randint|choice|randrange)`,
 			},
@@ -159,6 +256,8 @@ func (d *PythonDetector) loadSignatures() {
 			Name:        "Hardcoded credentials",
 			Severity:    "high",
 			Description: "Hardcoded credentials are a security risk",
+			Category:    "secrets",
+			CWE:         "CWE-798",
 			CodePatterns: []string{
 				`password\s*=\s*['\"][^'\"]{3,}['\"]`,
 				`passwd\s*=\s*['\"][^'\"]{3,}['\"]`,
@@ -175,6 +274,8 @@ func (d *PythonDetector) loadSignatures() {
 			Name:        "Insecure hash function",
 			Severity:    "medium",
 			Description: "Using weak hash functions like MD5 or SHA1",
+			Category:    "crypto",
+			CWE:         "CWE-328",
 			CodePatterns: []string{
 				`hashlib\.md5`,
 				`hashlib\.sha1`,
@@ -188,6 +289,8 @@ func (d *PythonDetector) loadSignatures() {
 			Name:        "Temporary file creation risk",
 			Severity:    "medium",
 			Description: "Insecure temporary file creation can lead to race conditions",
+			Category:    "filesystem",
+			CWE:         "CWE-377",
 			CodePatterns: []string{
 				`open\s*\(['\"][^'\"]*\/tmp[^'\"]*['\"]`,
 				`tempfile\.mktemp`,
@@ -201,6 +304,8 @@ func (d *PythonDetector) loadSignatures() {
 			Name:        "Insecure deserialization",
 			Severity:    "high",
 			Description: "Deserializing untrusted data can lead to arbitrary code execution",
+			Category:    "deserialization",
+			CWE:         "CWE-502",
 			CodePatterns: []string{
 				`yaml\.load\s*\([^)]*\)`,
 				`json\.loads\s*\([^)]*\)`,
@@ -214,6 +319,8 @@ func (d *PythonDetector) loadSignatures() {
 			Name:        "Debug mode enabled",
 			Severity:    "medium",
 			Description: "Running applications in debug mode can expose sensitive information",
+			Category:    "configuration",
+			CWE:         "CWE-489",
 			CodePatterns: []string{
 				`debug\s*=\s*True`,
 				`app\.run\s*\([^)]*debug\s*=\s*True[^)]*\)`,
@@ -222,40 +329,166 @@ func (d *PythonDetector) loadSignatures() {
 				"https://flask.palletsprojects.com/en/2.0.x/config/#DEBUG",
 			},
 		},
+		{
+			ID:          "PY013",
+			Name:        "SQL Injection in Django raw query",
+			Severity:    "high",
+			Description: "Interpolating values into Django .raw() or .extra() bypasses the ORM's query parameterization",
+			Category:    "injection",
+			CWE:         "CWE-89",
+			CodePatterns: []string{
+				`\.raw\s*\(\s*f['\"]`,
+				`\.raw\s*\(\s*['\"][^'\"]*['\"]\s*(%|\+|\.format\s*\()`,
+				`\.extra\s*\(.*\bf['\"]`,
+				`\.extra\s*\(.*['\"][^'\"]*['\"]\s*(%|\+|\.format\s*\()`,
+			},
+			References: []string{
+				"https://docs.djangoproject.com/en/stable/topics/db/sql/#passing-parameters-into-raw",
+			},
+		},
+		{
+			ID:          "PY014",
+			Name:        "SQL Injection in SQLAlchemy text()",
+			Severity:    "high",
+			Description: "Building SQLAlchemy text() clauses with string interpolation instead of bound parameters can lead to SQL injection",
+			Category:    "injection",
+			CWE:         "CWE-89",
+			CodePatterns: []string{
+				`\btext\s*\(\s*f['\"]`,
+				`\btext\s*\(\s*['\"][^'\"]*['\"]\s*(%|\+|\.format\s*\()`,
+			},
+			References: []string{
+				"https://docs.sqlalchemy.org/en/20/core/sqlelement.html#sqlalchemy.sql.expression.text",
+			},
+		},
+		{
+			ID:          "PY015",
+			Name:        "Stack traces exposed to clients",
+			Severity:    "medium",
+			Description: "Enabling Flask/Django debug mode or returning tracebacks in responses leaks internal details to clients",
+			Category:    "information-exposure",
+			CWE:         "CWE-209",
+			CodePatterns: []string{
+				`^\s*DEBUG\s*=\s*True`,
+				`app\.debug\s*=\s*True`,
+				`(return|jsonify|Response|HttpResponse)\b.*traceback\.format_exc\s*\(`,
+			},
+			References: []string{
+				"https://docs.djangoproject.com/en/stable/ref/settings/#debug",
+				"https://owasp.org/www-community/Improper_Error_Handling",
+			},
+		},
+		{
+			ID:          "PY017",
+			Name:        "Predictable random seed",
+			Severity:    "medium",
+			Description: "Seeding the random module with a constant makes every value it produces predictable",
+			Category:    "crypto",
+			CWE:         "CWE-336",
+			CodePatterns: []string{
+				`(^|[^.\w])random\.seed\s*\(\s*(\d+|['\"][^'\"]*['\"])\s*\)`,
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/336.html",
+			},
+		},
+		{
+			ID:          "PY018",
+			Name:        "Predictable UUID",
+			Severity:    "medium",
+			Description: "Time-based uuid1() values are guessable and must not be used as tokens or secrets; use secrets.token_urlsafe() or uuid4()",
+			Category:    "crypto",
+			CWE:         "CWE-340",
+			CodePatterns: []string{
+				`(?i)(token|secret|session|nonce|reset|key|password)\w*\s*=.*\b(uuid\.)?uuid1\s*\(`,
+			},
+			References: []string{
+				"https://docs.python.org/3/library/uuid.html#uuid.uuid1",
+			},
+		},
+		{
+			ID:          "PY019",
+			Name:        "SQL query built across statements",
+			Severity:    "high",
+			Description: "Appending user input to a SQL query with += over several statements can lead to SQL injection; use query parameters",
+			Category:    "injection",
+			CWE:         "CWE-89",
+			CodePatterns: []string{
+				`(?im)\b\w*(?:query|sql)\w*\s*=\s*f?['\"]\s*(?:SELECT|INSERT|UPDATE|DELETE)\b[^\n]*\n(?:[^\n]*\n){0,10}?[ \t]*\w*(?:query|sql)\w*\s*\+=\s*(?:[A-Za-z_][\w.]*[ \t]*(?:$|\+)|[^\n]*?(?:\+\s*[A-Za-z_]|f['\"][^\n]*\{|\.format\s*\(|['\"]\s*%\s*[A-Za-z_(]))`,
+			},
+			References: []string{
+				"https://owasp.org/www-community/attacks/SQL_Injection",
+			},
+			Multiline: true,
+		},
 	}
 }
 
-// calculateConfidence calculates the confidence of a match
-func (d *PythonDetector) calculateConfidence(matchedCode string, pattern string) float64 {
+// Signatures of the checks that are not driven by the signatures' code patterns
+var (
+	pyEmptyExceptSignature = core.Signature{
+		ID:          "PY011",
+		Name:        "Empty except block",
+		Severity:    "medium",
+		Description: "Empty except blocks can hide errors and make debugging difficult",
+		Category:    "error-handling",
+		CWE:         "CWE-390",
+		CodePatterns: []string{
+			`except(\s+\w+)?:\s*$`,
+		},
+	}
+	pyBareExceptSignature = core.Signature{
+		ID:          "PY012",
+		Name:        "Bare except block",
+		Severity:    "medium",
+		Description: "Bare except blocks can catch unexpected exceptions and hide errors",
+		Category:    "error-handling",
+		CWE:         "CWE-396",
+		CodePatterns: []string{
+			`except:\s*`,
+		},
+	}
+	pyTOCTOUSignature = toctouSignature("PY016")
+)
+
+// calculateConfidence calculates the confidence of a match and the factors it is made of
+func (d *PythonDetector) calculateConfidence(matchedCode string, pattern string) (float64, []core.ConfidenceFactor) {
+	return d.calculateContextConfidence(matchedCode, pattern, nil)
+}
+
+// calculateContextConfidence calculates the confidence of a match, taking the lines
+// around it into account, and the factors it is made of
+func (d *PythonDetector) calculateContextConfidence(matchedCode string, pattern string, context []string) (float64, []core.ConfidenceFactor) {
 	// Base confidence
-	confidence := 0.8
+	factors := confidenceFactors{{Reason: "base confidence", Value: 0.8}}
 
 	// Adjust based on match length
 	if len(matchedCode) > 10 {
-		confidence += 0.05
+		factors.add("matched code is longer than 10 characters", 0.05)
 	}
 
 	// Adjust based on context
 	if strings.Contains(matchedCode, "import") {
-		confidence += 0.05
+		factors.add("matched code contains an import", 0.05)
 	}
 
 	// Adjust based on pattern specificity
 	if len(pattern) > 20 {
-		confidence += 0.05
+		factors.add("pattern is longer than 20 characters", 0.05)
 	}
 
 	// Adjust based on function call parameters
 	if strings.Contains(matchedCode, "(") && strings.Contains(matchedCode, ")") {
-		confidence += 0.05
+		factors.add("matched code contains a function call", 0.05)
 	}
 
+	// Adjust based on the arguments and the surrounding code
+	factors.addContext(matchedCode, context)
+
 	// Ensure confidence is between 0 and 1
-	if confidence > 1.0 {
-		confidence = 1.0
-	}
+	confidence := factors.total()
 
-	return confidence
+	return confidence, factors
 }
 
 // checkPythonSpecificIssues performs additional Python-specific checks
@@ -271,15 +504,7 @@ func (d *PythonDetector) checkPythonSpecificIssues(code string, filePath string)
 		matchedCode := code[match[0]:match[1]]
 
 		matches = append(matches, core.Match{
-			Signature: core.Signature{
-				ID:          "PY011",
-				Name:        "Empty except block",
-				Severity:    "medium",
-				Description: "Empty except blocks can hide errors and make debugging difficult",
-				CodePatterns: []string{
-					`except(\s+\w+)?:\s*$`,
-				},
-			},
+			Signature:   pyEmptyExceptSignature,
 			FilePath:    filePath,
 			LineNumber:  lineNumber,
 			MatchedCode: matchedCode,
@@ -296,15 +521,7 @@ func (d *PythonDetector) checkPythonSpecificIssues(code string, filePath string)
 		matchedCode := code[match[0]:match[1]]
 
 		matches = append(matches, core.Match{
-			Signature: core.Signature{
-				ID:          "PY012",
-				Name:        "Bare except block",
-				Severity:    "medium",
-				Description: "Bare except blocks can catch unexpected exceptions and hide errors",
-				CodePatterns: []string{
-					`except:\s*`,
-				},
-			},
+			Signature:   pyBareExceptSignature,
 			FilePath:    filePath,
 			LineNumber:  lineNumber,
 			MatchedCode: matchedCode,
@@ -312,5 +529,8 @@ func (d *PythonDetector) checkPythonSpecificIssues(code string, filePath string)
 		})
 	}
 
+	// Check for files that are checked before they are opened
+	matches = append(matches, findTOCTOU(code, filePath, pyTOCTOUSignature, pythonTOCTOU)...)
+
 	return matches
 } 
\ No newline at end of file
@@ -0,0 +1,239 @@
+package detectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// pythonASTTimeout bounds the time the Python interpreter may take to parse a file
+const pythonASTTimeout = 10 * time.Second
+
+// pythonASTHelper is a Python program that parses the code on stdin and prints the
+// calls in it as JSON, with the called names resolved through the imports, e.g.
+// o.system for import os as o is reported as os.system
+const pythonASTHelper = `
+import ast, json, sys
+
+tree = ast.parse(sys.stdin.read())
+
+aliases = {}
+for node in ast.walk(tree):
+    if isinstance(node, ast.Import):
+        for alias in node.names:
+            if alias.asname:
+                aliases[alias.asname] = alias.name
+    elif isinstance(node, ast.ImportFrom) and node.module and not node.level:
+        for alias in node.names:
+            aliases[alias.asname or alias.name] = node.module + "." + alias.name
+
+def qualified_name(node):
+    if isinstance(node, ast.Name):
+        return aliases.get(node.id, node.id)
+    if isinstance(node, ast.Attribute):
+        base = qualified_name(node.value)
+        return base + "." + node.attr if base else None
+    return None
+
+def is_literal(node):
+    if isinstance(node, ast.JoinedStr):
+        return not any(isinstance(value, ast.FormattedValue) for value in node.values)
+    return isinstance(node, ast.Constant)
+
+calls = []
+for node in ast.walk(tree):
+    if not isinstance(node, ast.Call):
+        continue
+    name = qualified_name(node.func)
+    if not name:
+        continue
+    shell = any(k.arg == "shell" and not (isinstance(k.value, ast.Constant) and not k.value.value) for k in node.keywords)
+    literal = bool(node.args) and is_literal(node.args[0])
+    calls.append({"line": node.lineno, "name": name, "shell": shell, "literal": literal})
+
+json.dump(calls, sys.stdout)
+`
+
+// pythonCall is a call expression found by the Python AST helper
+type pythonCall struct {
+	Line    int    `json:"line"`
+	Name    string `json:"name"`
+	Shell   bool   `json:"shell"`
+	Literal bool   `json:"literal"`
+}
+
+// pyASTCalls are the calls that the signatures matching calls by regex are meant to
+// find. With AST analysis, their regex matches are only kept on lines with such a
+// call, and calls through import aliases that the regex misses are added.
+var pyASTCalls = map[string][]string{
+	"PY001": {"eval", "builtins.eval"},
+	"PY002": {"exec", "builtins.exec"},
+	"PY003": {"pickle.load", "pickle.loads", "cPickle.load", "cPickle.loads"},
+	"PY009": {"yaml.load", "json.loads"},
+}
+
+// pyShellCalls are the calls that always run their command through the shell
+var pyShellCalls = map[string]bool{
+	"os.system":                  true,
+	"os.popen":                   true,
+	"subprocess.getoutput":       true,
+	"subprocess.getstatusoutput": true,
+	"commands.getoutput":         true,
+}
+
+// pyShellSignature is the signature of commands run through the shell, which is only
+// reported by AST analysis
+var pyShellSignature = core.Signature{
+	ID:          "PY020",
+	Name:        "Command executed through the shell",
+	Severity:    "high",
+	Description: "Commands run through the shell, e.g. by os.system or subprocess with shell=True, allow command injection when built from input; pass an argument list to subprocess without shell=True",
+	Category:    "injection",
+	CWE:         "CWE-78",
+	References: []string{
+		"https://docs.python.org/3/library/subprocess.html#security-considerations",
+	},
+}
+
+// SetASTAnalysis sets whether Python code is parsed with the python3 interpreter to
+// report only real calls, including calls through import aliases, instead of every
+// regex match, e.g. in comments and strings. Files the interpreter cannot parse, or
+// all files if it is not installed, fall back to the regex analysis.
+func (d *PythonDetector) SetASTAnalysis(enabled bool) {
+	d.astAnalysis = enabled
+}
+
+// parsePythonCalls runs the AST helper on code and returns the calls in it
+func parsePythonCalls(code string) ([]pythonCall, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pythonASTTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "python3", "-c", pythonASTHelper)
+	cmd.Stdin = strings.NewReader(code)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("parsing Python code: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var calls []pythonCall
+	if err := json.Unmarshal(output, &calls); err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
+
+// applyAST replaces the regex matches of call signatures with the calls found in the
+// AST, and adds the calls that run commands through the shell. The regex matches are
+// returned unchanged if the code cannot be parsed.
+func (d *PythonDetector) applyAST(code string, filePath string, matches []core.Match) []core.Match {
+	calls, err := parsePythonCalls(code)
+	if err != nil {
+		return matches
+	}
+
+	// Calls by line and signature ID
+	callLines := make(map[string]map[int]bool)
+	for id, names := range pyASTCalls {
+		callLines[id] = make(map[int]bool)
+		for _, call := range calls {
+			for _, name := range names {
+				if call.Name == name {
+					callLines[id][call.Line] = true
+				}
+			}
+		}
+	}
+
+	// Drop the regex matches that are not calls, e.g. in comments and strings
+	kept := []core.Match{}
+	matched := make(map[string]map[int]bool)
+	for _, match := range matches {
+		id := match.Signature.ID
+		if lines, ok := callLines[id]; ok {
+			if !lines[match.LineNumber] {
+				continue
+			}
+			if matched[id] == nil {
+				matched[id] = make(map[int]bool)
+			}
+			matched[id][match.LineNumber] = true
+		}
+		kept = append(kept, match)
+	}
+
+	lines := strings.Split(code, "\n")
+	signatures := make(map[string]core.Signature)
+	for _, signature := range d.signatures {
+		signatures[signature.ID] = signature
+	}
+
+	// Add the calls the regexes missed, e.g. through import aliases, and shell commands
+	for _, call := range calls {
+		for id, names := range pyASTCalls {
+			for _, name := range names {
+				if call.Name == name && !matched[id][call.Line] {
+					if matched[id] == nil {
+						matched[id] = make(map[int]bool)
+					}
+					matched[id][call.Line] = true
+					kept = append(kept, newPythonCallMatch(signatures[id], call, filePath, lines))
+				}
+			}
+		}
+
+		if pyShellCalls[call.Name] || call.Shell && strings.HasPrefix(call.Name, "subprocess.") {
+			kept = append(kept, newPythonCallMatch(pyShellSignature, call, filePath, lines))
+		}
+	}
+
+	return kept
+}
+
+// newPythonCallMatch creates a match of a signature for a call found in the AST
+func newPythonCallMatch(signature core.Signature, call pythonCall, filePath string, lines []string) core.Match {
+	line := ""
+	if call.Line >= 1 && call.Line <= len(lines) {
+		line = lines[call.Line-1]
+	}
+
+	// The lines around the call adjust the confidence
+	start, end := call.Line-1-contextLines, call.Line+contextLines
+	if start < 0 {
+		start = 0
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+	var nearby []string
+	if start < end {
+		nearby = lines[start:end]
+	}
+
+	factors := confidenceFactors{{Reason: "base confidence", Value: 0.85}}
+	factors.add("call found in the Python AST", 0.05)
+	if call.Literal {
+		factors.add("argument is a literal", -0.25)
+	} else {
+		factors.addContext(line, nearby)
+	}
+
+	return core.Match{
+		Signature:   signature,
+		FilePath:    filePath,
+		LineNumber:  call.Line,
+		MatchedCode: line,
+		Confidence:  factors.total(),
+		Explanation: &core.Explanation{
+			Pattern: "call to " + call.Name,
+			Factors: factors,
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package detectors
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 创建启用AST分析的Python检测器，未安装python3时跳过测试
+func newASTPythonDetector(t *testing.T) *PythonDetector {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not installed")
+	}
+	detector := NewPythonDetector()
+	detector.SetASTAnalysis(true)
+	return detector
+}
+
+// 返回指定规则匹配的行号
+func signatureLines(matches []core.Match, id string) []int {
+	lines := []int{}
+	for _, match := range matches {
+		if match.Signature.ID == id {
+			lines = append(lines, match.LineNumber)
+		}
+	}
+	return lines
+}
+
+// 测试AST分析不报告注释和字符串中的eval
+func TestPythonASTCommentsAndStrings(t *testing.T) {
+	detector := newASTPythonDetector(t)
+
+	code := `# eval(x)
+message = "do not eval(user_input)"
+value = ast.literal_eval(data)
+result = eval(expression)
+`
+	matches, err := detector.DetectCode(code, "app.py")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4}, signatureLines(matches, "PY001"))
+
+	// 不启用AST分析时正则匹配全部报告
+	matches, err = NewPythonDetector().DetectCode(code, "app.py")
+	assert.NoError(t, err)
+	assert.Len(t, signatureLines(matches, "PY001"), 4)
+}
+
+// 测试AST分析按导入别名解析调用并报告通过shell执行的命令
+func TestPythonASTImportAliases(t *testing.T) {
+	detector := newASTPythonDetector(t)
+
+	code := `import os as o
+import pickle as p
+import subprocess as sp
+from subprocess import run
+
+o.system("ls " + request.args["dir"])
+data = p.loads(payload)
+sp.Popen(cmd, shell=True)
+run(["ls", path])
+run("ls", shell=False)
+`
+	matches, err := detector.DetectCode(code, "app.py")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6, 8}, signatureLines(matches, "PY020"))
+	assert.Equal(t, []int{7}, signatureLines(matches, "PY003"))
+	for _, match := range matches {
+		if match.Signature.ID == "PY020" && assert.NotNil(t, match.Explanation) {
+			assert.Contains(t, match.Explanation.Pattern, "call to ")
+		}
+	}
+}
+
+// 测试无法解析的代码回退到正则分析
+func TestPythonASTFallback(t *testing.T) {
+	detector := newASTPythonDetector(t)
+
+	code := "# eval(x)\nresult = eval(expression)\nif result\n"
+	matches, err := detector.DetectCode(code, "app.py")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, signatureLines(matches, "PY001"))
+}
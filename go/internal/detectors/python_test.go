@@ -0,0 +1,211 @@
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// hasSignature 检查匹配结果中是否包含指定规则
+func hasSignature(matches []core.Match, id string) bool {
+	for _, match := range matches {
+		if match.Signature.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// 测试Python和JavaScript规则都有分类和CWE编号
+func TestSignatureCategories(t *testing.T) {
+	signatures := append(NewPythonDetector().Signatures(), NewJavaScriptDetector().Signatures()...)
+	for _, signature := range signatures {
+		assert.NotEmpty(t, signature.Category, signature.ID)
+		assert.Regexp(t, `^CWE-\d+$`, signature.CWE, signature.ID)
+	}
+}
+
+// 测试清除内置签名后Python和JavaScript检测器不再报告任何问题
+func TestClearSignatures(t *testing.T) {
+	python := NewPythonDetector()
+	python.SetASTAnalysis(true)
+	python.ClearSignatures()
+	assert.Empty(t, python.Signatures())
+	matches, err := python.DetectCode("try:\n    eval(user_input)\nexcept:\n    pass\n", "app.py")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+
+	javascript := NewJavaScriptDetector()
+	javascript.ClearSignatures()
+	assert.Empty(t, javascript.Signatures())
+	matches, err = javascript.DetectCode("eval(userInput);\nconsole.log(x);\nconst y = <any>z;\n", "app.ts")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+// 测试SQLAlchemy text()参数化查询与f-string插值的区分
+func TestPythonSQLAlchemyText(t *testing.T) {
+	detector := NewPythonDetector()
+
+	// 参数化查询不应被标记
+	safe := `stmt = text("SELECT * FROM users WHERE id = :id")
+conn.execute(stmt, {"id": user_id})`
+	matches, err := detector.DetectCode(safe, "safe.py")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "PY014"))
+
+	// f-string插值应被标记
+	unsafe := `stmt = text(f"SELECT * FROM users WHERE id = {user_id}")`
+	matches, err = detector.DetectCode(unsafe, "unsafe.py")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "PY014"))
+
+	// %格式化同样应被标记
+	unsafe = `stmt = text("SELECT * FROM users WHERE name = '%s'" % name)`
+	matches, err = detector.DetectCode(unsafe, "unsafe.py")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "PY014"))
+}
+
+// 测试Django raw()与extra()的插值检测
+func TestPythonDjangoRaw(t *testing.T) {
+	detector := NewPythonDetector()
+
+	safe := `User.objects.raw("SELECT * FROM auth_user WHERE id = %s", [user_id])`
+	matches, err := detector.DetectCode(safe, "views.py")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "PY013"))
+
+	unsafe := `User.objects.raw(f"SELECT * FROM auth_user WHERE id = {user_id}")`
+	matches, err = detector.DetectCode(unsafe, "views.py")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "PY013"))
+
+	unsafe = `qs.extra(where=["name = '%s'" % name])`
+	matches, err = detector.DetectCode(unsafe, "views.py")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "PY013"))
+}
+
+// 测试Django/Flask泄露堆栈信息的配置检测
+func TestPythonStackTraceExposure(t *testing.T) {
+	detector := NewPythonDetector()
+
+	matches, err := detector.DetectCode("DEBUG = True", "settings.py")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "PY015"))
+
+	matches, err = detector.DetectCode("    return traceback.format_exc(), 500", "views.py")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "PY015"))
+
+	matches, err = detector.DetectCode("DEBUG = os.environ.get('DEBUG') == '1'", "settings.py")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "PY015"))
+}
+
+// 测试先检查后打开文件的竞态条件检测
+func TestPythonTOCTOU(t *testing.T) {
+	detector := NewPythonDetector()
+
+	unsafe := `if os.path.exists(config_path):
+    with open(config_path) as f:
+        data = f.read()
+`
+	matches, err := detector.DetectCode(unsafe, "app.py")
+	assert.NoError(t, err)
+	match := findSignature(matches, "PY016")
+	if assert.NotNil(t, match) {
+		assert.Equal(t, 2, match.LineNumber)
+	}
+
+	safe := `try:
+    with open(config_path) as f:
+        data = f.read()
+except FileNotFoundError:
+    data = None
+`
+	matches, err = detector.DetectCode(safe, "app.py")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "PY016"))
+}
+
+// 测试固定随机数种子和可预测UUID的检测
+func TestPythonPredictableRandomness(t *testing.T) {
+	detector := NewPythonDetector()
+
+	for _, code := range []string{
+		`random.seed(42)`,
+		`random.seed("fixed")`,
+		`reset_token = str(uuid.uuid1())`,
+		`session_key = uuid1().hex`,
+	} {
+		matches, err := detector.DetectCode(code, "app.py")
+		assert.NoError(t, err)
+		assert.True(t, hasSignature(matches, "PY017") || hasSignature(matches, "PY018"), code)
+	}
+
+	for _, code := range []string{
+		`random.seed()`,
+		`np.random.seed(42)`,
+		`request_id = uuid.uuid1()`,
+		`reset_token = str(uuid.uuid4())`,
+	} {
+		matches, err := detector.DetectCode(code, "app.py")
+		assert.NoError(t, err)
+		assert.False(t, hasSignature(matches, "PY017") || hasSignature(matches, "PY018"), code)
+	}
+}
+
+// 测试匹配结果记录触发的模式和置信度因素
+func TestPythonExplanation(t *testing.T) {
+	detector := NewPythonDetector()
+
+	matches, err := detector.DetectCode("result = eval(user_input)", "app.py")
+	assert.NoError(t, err)
+	match := findSignature(matches, "PY001")
+	if assert.NotNil(t, match) && assert.NotNil(t, match.Explanation) {
+		assert.Contains(t, match.Signature.CodePatterns, match.Explanation.Pattern)
+
+		reasons := []string{}
+		total := 0.0
+		for _, factor := range match.Explanation.Factors {
+			reasons = append(reasons, factor.Reason)
+			total += factor.Value
+		}
+		assert.Contains(t, reasons, "base confidence")
+		assert.Contains(t, reasons, "matched code is longer than 10 characters")
+		assert.Contains(t, reasons, "matched code contains a function call")
+		assert.InDelta(t, match.Confidence, total, 0.0001)
+	}
+}
+
+// 测试跨多行拼接SQL查询的检测
+func TestPythonMultilineSQL(t *testing.T) {
+	detector := NewPythonDetector()
+
+	code := `def find_user(cursor, name):
+    query = "SELECT * FROM users"
+    query += " WHERE active = 1"
+    query += " AND name = '" + name + "'"
+    cursor.execute(query)
+`
+	matches, err := detector.DetectCode(code, "app.py")
+	assert.NoError(t, err)
+	match := findSignature(matches, "PY019")
+	if assert.NotNil(t, match) {
+		assert.Equal(t, 2, match.LineNumber)
+		assert.Contains(t, match.MatchedCode, `query += " AND name = '" + name`)
+	}
+
+	for _, safe := range []string{
+		"query = \"SELECT * FROM users\"\nquery += \" WHERE name = %s\"\ncursor.execute(query, (name,))\n",
+		"query = \"SELECT * FROM users\"\ncount += 1\n",
+	} {
+		matches, err = detector.DetectCode(safe, "app.py")
+		assert.NoError(t, err)
+		assert.False(t, hasSignature(matches, "PY019"), safe)
+	}
+}
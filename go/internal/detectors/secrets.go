@@ -0,0 +1,296 @@
+package detectors
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// secretRule is a provider-specific credential signature
+type secretRule struct {
+	signature core.Signature
+	provider  string
+}
+
+// SecretsDetector is a detector for hardcoded credentials, shared by all languages.
+// Matches of provider-specific rules and of known key formats carry the provider
+// in their metadata under the "provider" key.
+type SecretsDetector struct {
+	core.BaseDetector
+	rules            []secretRule
+	entropyThreshold float64
+	minSecretLength  int
+}
+
+// NewSecretsDetector creates a new secrets detector
+func NewSecretsDetector() *SecretsDetector {
+	detector := &SecretsDetector{
+		entropyThreshold: DefaultEntropyThreshold,
+		minSecretLength:  DefaultMinSecretLength,
+	}
+	detector.loadRules()
+	return detector
+}
+
+// Name returns the name of the detector
+func (d *SecretsDetector) Name() string {
+	return "secrets"
+}
+
+// SupportedLanguages returns the list of supported languages
+func (d *SecretsDetector) SupportedLanguages() []string {
+	return []string{"py", "js", "ts", "go", "java", "rb", "php", "sh", "tf", "tfvars", "json", "yaml", "yml", "toml", "ini", "cfg", "conf", "env", "properties", "config"}
+}
+
+// Signatures returns every signature the detector can report
+func (d *SecretsDetector) Signatures() []core.Signature {
+	signatures := []core.Signature{secretSignature}
+	for _, rule := range d.rules {
+		signatures = append(signatures, rule.signature)
+	}
+	return append(signatures, serviceAccountSignature, terraformProviderSignature)
+}
+
+// DetectFile detects vulnerabilities in a file
+func (d *SecretsDetector) DetectFile(filePath string) ([]core.Match, error) {
+	// Check if file type is supported
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), ".")
+	supported := false
+	for _, lang := range d.SupportedLanguages() {
+		if lang == ext {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, nil
+	}
+
+	// Read file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectCode(string(content), filePath)
+}
+
+// DetectReader detects vulnerabilities in code read from r
+func (d *SecretsDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return core.ReadAndDetect(d, r, filePath)
+}
+
+// DetectCode detects vulnerabilities in code
+func (d *SecretsDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches := []core.Match{}
+
+	// Scan code line by line
+	scanner := bufio.NewScanner(strings.NewReader(code))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		// Check each rule, reporting at most one match per rule and line
+		ruleMatched := false
+		for _, rule := range d.rules {
+			for _, pattern := range rule.signature.CodePatterns {
+				re, err := compilePattern(pattern)
+				if err != nil {
+					continue
+				}
+
+				if re.MatchString(line) {
+					matches = append(matches, newSecretMatch(rule.signature, rule.provider, filePath, lineNumber, line, 0.9))
+					ruleMatched = true
+					break
+				}
+			}
+		}
+
+		// Fall back to known key formats and high-entropy literals
+		if !ruleMatched {
+			if match, ok := d.findSecret(line, lineNumber, filePath); ok {
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	// Perform checks that need more than one line of context
+	matches = append(matches, d.checkServiceAccountJSON(code, filePath)...)
+	matches = append(matches, d.checkTerraformProviders(code, filePath)...)
+
+	return matches, nil
+}
+
+// loadRules loads the provider-specific credential rules
+func (d *SecretsDetector) loadRules() {
+	d.rules = []secretRule{
+		{
+			provider: "aws",
+			signature: core.Signature{
+				ID:          "SEC002",
+				Name:        "Hardcoded AWS access key ID",
+				Severity:    "high",
+				Category:    "secrets",
+				Description: "AWS access key IDs should be provided through the environment or an IAM role, not committed to source",
+				CodePatterns: []string{
+					`(?i)aws_access_key_id['\"]?\s*[:=]\s*['\"]?(AKIA|ASIA)[0-9A-Z]{16}`,
+					`\b(AKIA|ASIA)[0-9A-Z]{16}\b`,
+				},
+				References: []string{
+					"https://docs.aws.amazon.com/general/latest/gr/aws-access-keys-best-practices.html",
+				},
+			},
+		},
+		{
+			provider: "aws",
+			signature: core.Signature{
+				ID:          "SEC003",
+				Name:        "Hardcoded AWS secret access key",
+				Severity:    "high",
+				Category:    "secrets",
+				Description: "AWS secret access keys grant full access to the owning account and must not be committed to source",
+				CodePatterns: []string{
+					`(?i)aws_secret_access_key['\"]?\s*[:=]\s*['\"]?[A-Za-z0-9/+=]{40}\b`,
+				},
+				References: []string{
+					"https://docs.aws.amazon.com/general/latest/gr/aws-access-keys-best-practices.html",
+				},
+			},
+		},
+		{
+			provider: "azure",
+			signature: core.Signature{
+				ID:          "SEC005",
+				Name:        "Hardcoded Azure connection string",
+				Severity:    "high",
+				Category:    "secrets",
+				Description: "Azure storage and Service Bus connection strings embed account keys and must not be committed to source",
+				CodePatterns: []string{
+					`(?i)DefaultEndpointsProtocol=https?;AccountName=[^;]+;AccountKey=[A-Za-z0-9+/=]{20,}`,
+					`(?i)Endpoint=sb://[^;]+;SharedAccessKeyName=[^;]+;SharedAccessKey=[A-Za-z0-9+/=]{20,}`,
+				},
+				References: []string{
+					"https://learn.microsoft.com/en-us/azure/storage/common/storage-configure-connection-string",
+				},
+			},
+		},
+	}
+}
+
+// serviceAccountSignature is the signature of GCP service account keys embedded as JSON
+var serviceAccountSignature = core.Signature{
+	ID:          "SEC004",
+	Name:        "Hardcoded GCP service account key",
+	Severity:    "high",
+	Category:    "secrets",
+	Description: "GCP service account keys should be loaded from a secret manager or workload identity, not committed to source",
+	References: []string{
+		"https://cloud.google.com/iam/docs/best-practices-for-managing-service-account-keys",
+	},
+}
+
+// terraformProviderSignature is the signature of credentials set inline in Terraform provider blocks
+var terraformProviderSignature = core.Signature{
+	ID:          "SEC006",
+	Name:        "Hardcoded credentials in Terraform provider",
+	Severity:    "high",
+	Category:    "secrets",
+	Description: "Terraform provider credentials should come from variables backed by the environment, not string literals",
+	References: []string{
+		"https://developer.hashicorp.com/terraform/language/providers/configuration",
+	},
+}
+
+// checkServiceAccountJSON detects GCP service account keys embedded as JSON
+func (d *SecretsDetector) checkServiceAccountJSON(code string, filePath string) []core.Match {
+	matches := []core.Match{}
+
+	// Only report private keys that belong to a service account blob
+	serviceAccountRe := regexp.MustCompile(`['\"]type['\"]\s*:\s*['\"]service_account['\"]`)
+	if !serviceAccountRe.MatchString(code) {
+		return matches
+	}
+
+	privateKeyRe := regexp.MustCompile(`['\"]private_key['\"]\s*:\s*['\"]-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`)
+	for i, line := range strings.Split(code, "\n") {
+		if privateKeyRe.MatchString(line) {
+			matches = append(matches, newSecretMatch(serviceAccountSignature, "gcp", filePath, i+1, line, 0.95))
+		}
+	}
+
+	return matches
+}
+
+// checkTerraformProviders detects credentials set inline in Terraform provider blocks
+func (d *SecretsDetector) checkTerraformProviders(code string, filePath string) []core.Match {
+	matches := []core.Match{}
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext != ".tf" && ext != ".tfvars" {
+		return matches
+	}
+
+	providerRe := regexp.MustCompile(`^\s*provider\s+"([A-Za-z0-9_-]+)"\s*\{`)
+	keyRe := regexp.MustCompile(`^\s*(access_key|secret_key|token|client_secret|client_certificate_password|credentials|api_key)\s*=\s*"[^"$]+"`)
+
+	provider := ""
+	depth := 0
+	for i, line := range strings.Split(code, "\n") {
+		if provider == "" {
+			if m := providerRe.FindStringSubmatch(line); m != nil {
+				provider = terraformProvider(m[1])
+				depth = 0
+			} else {
+				continue
+			}
+		} else if keyRe.MatchString(line) {
+			matches = append(matches, newSecretMatch(terraformProviderSignature, provider, filePath, i+1, line, 0.9))
+		}
+
+		// Track braces to find the end of the provider block
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth <= 0 {
+			provider = ""
+		}
+	}
+
+	return matches
+}
+
+// terraformProvider maps a Terraform provider name to the provider used in match metadata
+func terraformProvider(name string) string {
+	switch name {
+	case "aws":
+		return "aws"
+	case "google", "google-beta":
+		return "gcp"
+	case "azurerm", "azuread":
+		return "azure"
+	default:
+		return name
+	}
+}
+
+// newSecretMatch creates a match tagged with its cloud provider, if known
+func newSecretMatch(signature core.Signature, provider string, filePath string, lineNumber int, line string, confidence float64) core.Match {
+	match := core.Match{
+		Signature:   signature,
+		FilePath:    filePath,
+		LineNumber:  lineNumber,
+		MatchedCode: line,
+		Confidence:  confidence,
+	}
+	if provider != "" {
+		match.Metadata = map[string]string{
+			"provider": provider,
+		}
+	}
+	return match
+}
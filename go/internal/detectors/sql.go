@@ -0,0 +1,349 @@
+package detectors
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// SQLDetector is a detector for SQL scripts, e.g. migrations and stored procedures
+type SQLDetector struct {
+	core.BaseDetector
+	signatures []core.Signature
+}
+
+// NewSQLDetector creates a new SQL detector
+func NewSQLDetector() *SQLDetector {
+	detector := &SQLDetector{}
+	detector.loadSignatures()
+	return detector
+}
+
+// Name returns the name of the detector
+func (d *SQLDetector) Name() string {
+	return "sql"
+}
+
+// SupportedLanguages returns the list of supported languages
+func (d *SQLDetector) SupportedLanguages() []string {
+	return []string{"sql"}
+}
+
+// Signatures returns every signature the detector can report
+func (d *SQLDetector) Signatures() []core.Signature {
+	return d.signatures
+}
+
+// DetectFile detects vulnerabilities in a file
+func (d *SQLDetector) DetectFile(filePath string) ([]core.Match, error) {
+	// Check if file is a SQL file
+	if strings.ToLower(filepath.Ext(filePath)) != ".sql" {
+		return nil, nil
+	}
+
+	// Read file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectCode(string(content), filePath)
+}
+
+var (
+	sqlConcatRe    = regexp.MustCompile(`(?i)('(?:[^']|'')*\b(select|insert|update|delete|from|where|values|exec|execute)\b(?:[^']|'')*'\s*(\+|\|\|)|(\+|\|\|)\s*'(?:[^']|'')*\b(from|where|and|or|set|values|order\s+by)\b(?:[^']|'')*'|\bconcat\s*\(\s*'(?:[^']|'')*\b(select|insert|update|delete|from|where)\b)`)
+	sqlOperandRe   = regexp.MustCompile(`[@:$]?[A-Za-z_][\w.]*`)
+	sqlQuoteRe     = regexp.MustCompile(`(?i)\b(quotename|quote_ident|quote_literal|quote_nullable)\s*\(`)
+	sqlUserInputRe = regexp.MustCompile(`(?i)(user|input|param|arg|search|filter|query|term|name|email|value|request)`)
+	sqlGrantAllRe  = regexp.MustCompile(`(?i)\bgrant\s+all\b`)
+	sqlPublicRe    = regexp.MustCompile(`(?i)\bto\s+public\b`)
+	sqlWriteRe     = regexp.MustCompile(`(?i)\b(delete\s+from\s+([\w.\[\]"#@]+)|update\s+([\w.\[\]"#@]+)\s+set)\b`)
+	sqlWhereRe     = regexp.MustCompile(`(?i)\bwhere\b`)
+	sqlTempTableRe = regexp.MustCompile(`(?i)^[\["]?(#|@|te?mp_)`)
+)
+
+// DetectReader detects vulnerabilities in code read from r
+func (d *SQLDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return core.ReadAndDetect(d, r, filePath)
+}
+
+// DetectCode detects vulnerabilities in code
+func (d *SQLDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches := []core.Match{}
+
+	// Comments are blanked out so that commented-out statements are not reported
+	code = stripSQLComments(code)
+	lines := strings.Split(code, "\n")
+
+	for i, line := range lines {
+		lineNumber := i + 1
+
+		// Only concatenation with a variable builds the statement at run time
+		if sqlConcatRe.MatchString(line) {
+			if operands := sqlOperands(line); len(operands) > 0 {
+				factors := confidenceFactors{{Reason: "base confidence", Value: 0.7}}
+				if sqlUserInput(operands) {
+					factors.add("user input concatenated into the statement", 0.2)
+				} else {
+					factors.add("no user input among the concatenated values", -0.1)
+				}
+				if sqlQuoteRe.MatchString(line) {
+					factors.add("values quoted before concatenation", -0.3)
+				}
+				matches = append(matches, d.newMatch(0, filePath, lineNumber, line, factors))
+			}
+		}
+
+		if sqlGrantAllRe.MatchString(line) {
+			factors := confidenceFactors{{Reason: "base confidence", Value: 0.85}}
+			if sqlPublicRe.MatchString(line) {
+				factors.add("granted to PUBLIC", 0.1)
+			}
+			matches = append(matches, d.newMatch(1, filePath, lineNumber, line, factors))
+		}
+	}
+
+	// A WHERE clause can be on any line of the statement
+	for _, statement := range sqlStatements(code) {
+		loc := sqlWriteRe.FindStringSubmatchIndex(statement.text)
+		if loc == nil || sqlWhereRe.MatchString(statement.text[loc[1]:]) {
+			continue
+		}
+
+		// The table is the DELETE FROM or UPDATE group, whichever matched
+		start, end := loc[4], loc[5]
+		if start < 0 {
+			start, end = loc[6], loc[7]
+		}
+		table := statement.text[start:end]
+		factors := confidenceFactors{{Reason: "base confidence", Value: 0.8}}
+		if sqlTempTableRe.MatchString(table) {
+			factors.add("temporary table", -0.3)
+		}
+
+		lineNumber := statement.line + strings.Count(statement.text[:loc[0]], "\n")
+		matches = append(matches, d.newMatch(2, filePath, lineNumber, lines[lineNumber-1], factors))
+	}
+
+	return matches, nil
+}
+
+// loadSignatures loads the signatures for SQL code
+func (d *SQLDetector) loadSignatures() {
+	d.signatures = []core.Signature{
+		{
+			ID:          "SQL001",
+			Name:        "Dynamic SQL built by concatenation",
+			Severity:    "high",
+			Description: "Statements built by concatenating variables into SQL text and run with EXEC, sp_executesql or EXECUTE IMMEDIATE allow SQL injection; pass the values as bound parameters instead",
+			Category:    "injection",
+			CWE:         "CWE-89",
+			CodePatterns: []string{
+				sqlConcatRe.String(),
+			},
+			References: []string{
+				"https://cheatsheetseries.owasp.org/cheatsheets/SQL_Injection_Prevention_Cheat_Sheet.html",
+			},
+		},
+		{
+			ID:          "SQL002",
+			Name:        "GRANT ALL privileges",
+			Severity:    "medium",
+			Description: "GRANT ALL gives every privilege on the object, including altering and dropping it; grant only the privileges the role needs",
+			Category:    "access-control",
+			CWE:         "CWE-250",
+			CodePatterns: []string{
+				sqlGrantAllRe.String(),
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/250.html",
+			},
+		},
+		{
+			ID:          "SQL003",
+			Name:        "DELETE or UPDATE without WHERE",
+			Severity:    "medium",
+			Description: "A DELETE or UPDATE statement without a WHERE clause changes every row of the table, which is rarely intended in scripts and migrations",
+			Category:    "data-integrity",
+			CWE:         "CWE-1068",
+			CodePatterns: []string{
+				sqlWriteRe.String(),
+			},
+			References: []string{
+				"https://cwe.mitre.org/data/definitions/1068.html",
+			},
+		},
+	}
+}
+
+// newMatch creates a match of the signature at index for a line
+func (d *SQLDetector) newMatch(index int, filePath string, lineNumber int, line string, factors confidenceFactors) core.Match {
+	signature := d.signatures[index]
+	confidence := factors.total()
+	return core.Match{
+		Signature:   signature,
+		FilePath:    filePath,
+		LineNumber:  lineNumber,
+		MatchedCode: strings.TrimSpace(line),
+		Confidence:  confidence,
+		Explanation: &core.Explanation{
+			Pattern: signature.CodePatterns[0],
+			Factors: factors,
+		},
+	}
+}
+
+// sqlOperands returns the variables and columns in the expression that starts at the
+// first string literal of a line, leaving out string literals, numbers and SQL keywords
+func sqlOperands(line string) []string {
+	operands := []string{}
+	start := strings.IndexByte(line, '\'')
+	if start < 0 {
+		return operands
+	}
+	for _, operand := range sqlOperandRe.FindAllString(removeSQLStrings(line[start:]), -1) {
+		if !isSQLKeyword(operand) {
+			operands = append(operands, operand)
+		}
+	}
+	return operands
+}
+
+// sqlUserInput reports whether any of the concatenated values looks like user input,
+// e.g. @userName, :search or p_filter
+func sqlUserInput(operands []string) bool {
+	for _, operand := range operands {
+		if sqlUserInputRe.MatchString(operand) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSQLKeyword reports whether a word is a SQL keyword, type or function that can
+// appear in a concatenation
+func isSQLKeyword(word string) bool {
+	switch strings.ToUpper(word) {
+	case "SELECT", "FROM", "WHERE", "AND", "OR", "NOT", "NULL", "IS", "IN", "LIKE", "AS",
+		"ORDER", "GROUP", "BY", "INTO", "VALUES", "USING", "EXEC", "EXECUTE", "IMMEDIATE",
+		"CASE", "WHEN", "THEN", "ELSE", "END", "CONCAT", "CAST", "CONVERT", "COALESCE", "ISNULL",
+		"QUOTENAME", "QUOTE_IDENT", "QUOTE_LITERAL", "QUOTE_NULLABLE",
+		"VARCHAR", "NVARCHAR", "CHAR", "TEXT", "INT", "MAX", "N":
+		return true
+	}
+	return false
+}
+
+// removeSQLStrings replaces the string literals of a line with empty literals
+func removeSQLStrings(line string) string {
+	var out strings.Builder
+	inString := false
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\'' {
+			// Doubled quotes are escaped quotes inside a literal
+			if inString && i+1 < len(line) && line[i+1] == '\'' {
+				i++
+				continue
+			}
+			inString = !inString
+			out.WriteByte('\'')
+			continue
+		}
+		if !inString {
+			out.WriteByte(line[i])
+		}
+	}
+	return out.String()
+}
+
+// stripSQLComments replaces -- and /* */ comments with spaces, keeping newlines so
+// that line numbers do not change. Comment markers in string literals are kept.
+func stripSQLComments(code string) string {
+	out := []byte(code)
+	inString, lineComment, blockComment := false, false, false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case lineComment:
+			if c == '\n' {
+				lineComment = false
+				continue
+			}
+			out[i] = ' '
+		case blockComment:
+			if c == '*' && i+1 < len(out) && out[i+1] == '/' {
+				blockComment = false
+				out[i], out[i+1] = ' ', ' '
+				i++
+			} else if c != '\n' {
+				out[i] = ' '
+			}
+		case inString:
+			if c == '\'' {
+				inString = false
+			}
+		case c == '\'':
+			inString = true
+		case c == '-' && i+1 < len(out) && out[i+1] == '-':
+			lineComment = true
+			out[i] = ' '
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			blockComment = true
+			out[i] = ' '
+		}
+	}
+	return string(out)
+}
+
+// sqlStatement is a statement of a SQL script and the line it starts on
+type sqlStatement struct {
+	text string
+	line int
+}
+
+// sqlStatements splits a script into statements at semicolons and GO batch
+// separators outside of string literals
+func sqlStatements(code string) []sqlStatement {
+	statements := []sqlStatement{}
+	start, line, startLine := 0, 1, 1
+	inString := false
+
+	add := func(end int) {
+		if text := code[start:end]; strings.TrimSpace(text) != "" {
+			statements = append(statements, sqlStatement{text: text, line: startLine})
+		}
+	}
+
+	for i := 0; i < len(code); i++ {
+		switch code[i] {
+		case '\'':
+			inString = !inString
+		case ';':
+			if !inString {
+				add(i)
+				start, startLine = i+1, line
+			}
+		case '\n':
+			line++
+			if !inString && strings.EqualFold(strings.TrimSpace(sqlLineAt(code, i+1)), "GO") {
+				add(i)
+				start, startLine = i+1, line
+			}
+		}
+	}
+	add(len(code))
+
+	return statements
+}
+
+// sqlLineAt returns the line that starts at offset
+func sqlLineAt(code string, offset int) string {
+	if end := strings.IndexByte(code[offset:], '\n'); end >= 0 {
+		return code[offset : offset+end]
+	}
+	return code[offset:]
+}
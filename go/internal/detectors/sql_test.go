@@ -0,0 +1,114 @@
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 存在漏洞的SQL脚本
+const vulnerableSQL = `CREATE PROCEDURE search_users @userName NVARCHAR(100)
+AS
+BEGIN
+    DECLARE @sql NVARCHAR(MAX);
+    SET @sql = 'SELECT * FROM users WHERE name = ''' + @userName + '''';
+    EXEC(@sql);
+END
+GO
+
+GRANT ALL PRIVILEGES ON orders TO PUBLIC;
+
+DELETE FROM sessions;
+
+UPDATE accounts
+   SET balance = 0;
+`
+
+// 安全的SQL脚本
+const safeSQL = `CREATE PROCEDURE search_users @userName NVARCHAR(100)
+AS
+BEGIN
+    -- SET @sql = 'SELECT * FROM users WHERE name = ''' + @userName + '''';
+    EXEC sp_executesql N'SELECT * FROM users WHERE name = @name', N'@name NVARCHAR(100)', @name = @userName;
+END
+GO
+
+GRANT SELECT, INSERT ON orders TO app_role;
+
+/* DELETE FROM sessions; */
+DELETE FROM sessions WHERE expires_at < CURRENT_TIMESTAMP;
+
+UPDATE accounts
+   SET balance = 0
+ WHERE closed = 1;
+
+SELECT 'Total: ' || 'none' FROM dual;
+`
+
+// 测试SQL规则
+func TestSQLDetector(t *testing.T) {
+	detector := NewSQLDetector()
+
+	matches, err := detector.DetectCode(vulnerableSQL, "search_users.sql")
+	assert.NoError(t, err)
+
+	for id, line := range map[string]int{"SQL001": 5, "SQL002": 10, "SQL003": 12} {
+		match := findSignature(matches, id)
+		if assert.NotNil(t, match, id) {
+			assert.Equal(t, line, match.LineNumber, id)
+		}
+	}
+	assert.Equal(t, []int{12, 14}, signatureLines(matches, "SQL003"))
+
+	match := findSignature(matches, "SQL001")
+	if assert.NotNil(t, match) {
+		assert.InDelta(t, 0.9, match.Confidence, 0.0001)
+		assert.Equal(t, "CWE-89", match.Signature.CWE)
+	}
+	match = findSignature(matches, "SQL002")
+	if assert.NotNil(t, match) {
+		assert.InDelta(t, 0.95, match.Confidence, 0.0001)
+	}
+
+	matches, err = detector.DetectCode(safeSQL, "search_users.sql")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+// 测试拼接的值是否像用户输入影响置信度
+func TestSQLDetectorConcatenation(t *testing.T) {
+	detector := NewSQLDetector()
+
+	userInput := confidenceOf(t, detector, "SET @sql = 'SELECT * FROM orders WHERE customer = ' + @searchTerm;\n", "script.sql", "SQL001")
+	tableName := confidenceOf(t, detector, "SET @sql = 'DELETE FROM ' + @table + ' WHERE id = 1';\n", "script.sql", "SQL001")
+	quoted := confidenceOf(t, detector, "SET @sql = 'SELECT * FROM orders WHERE customer = ' + QUOTENAME(@searchTerm, '''');\n", "script.sql", "SQL001")
+	assert.Greater(t, userInput, tableName)
+	assert.Greater(t, userInput, quoted)
+
+	// PostgreSQL和MySQL的拼接写法
+	matches, err := detector.DetectCode("EXECUTE 'SELECT * FROM users WHERE email = ' || p_email;\n", "fn.sql")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "SQL001"))
+	matches, err = detector.DetectCode("SET @q = CONCAT('SELECT * FROM users WHERE id = ', user_id);\n", "proc.sql")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "SQL001"))
+
+	// 只拼接字面量时不报告
+	matches, err = detector.DetectCode("SET @sql = 'SELECT * FROM users ' + 'WHERE active = 1';\n", "static.sql")
+	assert.NoError(t, err)
+	assert.False(t, hasSignature(matches, "SQL001"))
+}
+
+// 测试临时表上没有WHERE的语句降低置信度
+func TestSQLDetectorTempTables(t *testing.T) {
+	detector := NewSQLDetector()
+
+	assert.InDelta(t, 0.8, confidenceOf(t, detector, "DELETE FROM audit_log;\n", "script.sql", "SQL003"), 0.0001)
+	assert.InDelta(t, 0.5, confidenceOf(t, detector, "DELETE FROM #staging;\n", "script.sql", "SQL003"), 0.0001)
+	assert.InDelta(t, 0.5, confidenceOf(t, detector, "UPDATE tmp_import SET processed = 1;\n", "script.sql", "SQL003"), 0.0001)
+
+	// 非SQL文件不检测
+	matches, err := detector.DetectFile("notes.txt")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
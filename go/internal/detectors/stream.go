@@ -0,0 +1,172 @@
+package detectors
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+const (
+	// DefaultMaxLineSize is the default maximum length of a line in bytes.
+	// Longer lines, such as in minified bundles, are truncated to this length.
+	DefaultMaxLineSize = 1024 * 1024
+
+	// DefaultMaxFileSizeMB is the default size above which files are skipped,
+	// matching the security.max_file_size_mb default
+	DefaultMaxFileSizeMB = 10
+
+	// windowLines is the number of lines buffered for multi-line checks
+	windowLines = 200
+
+	// windowOverlap is the number of lines carried over between windows
+	windowOverlap = 20
+)
+
+// streamLimits bounds the memory used by a detector when streaming a file
+type streamLimits struct {
+	maxLineSize int
+	maxFileSize int64
+}
+
+// defaultStreamLimits returns the default stream limits
+func defaultStreamLimits() streamLimits {
+	return streamLimits{
+		maxLineSize: DefaultMaxLineSize,
+		maxFileSize: DefaultMaxFileSizeMB * 1024 * 1024,
+	}
+}
+
+// SetMaxLineSize sets the maximum length of a line in bytes; longer lines are truncated
+func (l *streamLimits) SetMaxLineSize(size int) {
+	l.maxLineSize = size
+}
+
+// SetMaxFileSizeMB sets the size above which files are skipped with a warning.
+// A size of 0 disables the limit.
+func (l *streamLimits) SetMaxFileSizeMB(sizeMB int) {
+	l.maxFileSize = int64(sizeMB) * 1024 * 1024
+}
+
+// skipLargeFile reports whether a file exceeds the size limit, logging a warning if so
+func (l *streamLimits) skipLargeFile(filePath string) (bool, error) {
+	if l.maxFileSize <= 0 {
+		return false, nil
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return false, err
+	}
+
+	if info.Size() > l.maxFileSize {
+		fmt.Fprintf(os.Stderr, "Warning: Skipping %s: size %d bytes exceeds the limit of %d bytes\n", filePath, info.Size(), l.maxFileSize)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// scanLines calls fn for every line read from r, truncating lines longer than the max line size
+func (l *streamLimits) scanLines(r io.Reader, fn func(lineNumber int, line string)) error {
+	maxLineSize := l.maxLineSize
+	if maxLineSize <= 0 {
+		maxLineSize = DefaultMaxLineSize
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize+1)
+	scanner.Split(truncatingSplit(maxLineSize))
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fn(lineNumber, scanner.Text())
+	}
+
+	return scanner.Err()
+}
+
+// truncatingSplit is a bufio.SplitFunc like bufio.ScanLines that returns only the
+// first maxLineSize bytes of a longer line and discards the rest
+func truncatingSplit(maxLineSize int) bufio.SplitFunc {
+	skipping := false
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if skipping {
+			// Discard the rest of an overlong line
+			if i := bytes.IndexByte(data, '\n'); i >= 0 {
+				skipping = false
+				return i + 1, nil, nil
+			}
+			return len(data), nil, nil
+		}
+
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if advance == 0 && token == nil && err == nil && len(data) >= maxLineSize {
+			// The line does not fit in the buffer, skip to its end
+			skipping = true
+			return maxLineSize, data[:maxLineSize], nil
+		}
+		if len(token) > maxLineSize {
+			token = token[:maxLineSize]
+		}
+		return advance, token, err
+	}
+}
+
+// lineWindow buffers a bounded window of lines for checks that need multi-line context.
+// Windows overlap so a match near the end of one window is seen in full by the next,
+// and each match is kept only by the window that owns its line.
+type lineWindow struct {
+	filePath  string
+	check     func(code string, filePath string) []core.Match
+	lines     []string
+	startLine int
+	matches   []core.Match
+}
+
+// newLineWindow creates a window that runs check over the buffered lines
+func newLineWindow(filePath string, check func(code string, filePath string) []core.Match) *lineWindow {
+	return &lineWindow{
+		filePath:  filePath,
+		check:     check,
+		startLine: 1,
+	}
+}
+
+// add appends a line, running the check when the window is full
+func (w *lineWindow) add(line string) {
+	w.lines = append(w.lines, line)
+	if len(w.lines) >= windowLines {
+		w.flush(len(w.lines) - windowOverlap)
+	}
+}
+
+// close runs the check over the remaining lines and returns all matches
+func (w *lineWindow) close() []core.Match {
+	w.flush(len(w.lines))
+	return w.matches
+}
+
+// flush runs the check over the window and drops the first owned lines
+func (w *lineWindow) flush(owned int) {
+	if len(w.lines) == 0 {
+		return
+	}
+
+	for _, match := range w.check(strings.Join(w.lines, "\n"), w.filePath) {
+		if match.LineNumber > owned {
+			// Reported again by the next window
+			continue
+		}
+		match.LineNumber += w.startLine - 1
+		w.matches = append(w.matches, match)
+	}
+
+	w.startLine += owned
+	w.lines = append([]string{}, w.lines[owned:]...)
+}
@@ -0,0 +1,94 @@
+package detectors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试超长行被截断且不影响后续行号
+func TestScanLinesTruncatesLongLines(t *testing.T) {
+	limits := defaultStreamLimits()
+	limits.SetMaxLineSize(16)
+
+	code := strings.Repeat("x", 100) + "\nshort\n" + strings.Repeat("y", 40)
+	lines := []string{}
+	numbers := []int{}
+	err := limits.scanLines(strings.NewReader(code), func(lineNumber int, line string) {
+		numbers = append(numbers, lineNumber)
+		lines = append(lines, line)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, numbers)
+	assert.Equal(t, []string{strings.Repeat("x", 16), "short", strings.Repeat("y", 16)}, lines)
+}
+
+// 测试压缩后的超长单行文件仍可扫描
+func TestJavaScriptMinifiedBundle(t *testing.T) {
+	detector := NewJavaScriptDetector()
+	detector.SetMaxLineSize(1024)
+
+	code := "eval(userInput);" + strings.Repeat("var a=1;", 10000) + "\nalert(1)\n"
+	matches, err := detector.DetectCode(code, "bundle.min.js")
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "JS001"))
+
+	alert := findSignature(matches, "JS012")
+	if assert.NotNil(t, alert) {
+		assert.Equal(t, 2, alert.LineNumber)
+	}
+}
+
+// 测试多行检查在窗口边界处既不丢失也不重复
+func TestPythonWindowedChecks(t *testing.T) {
+	detector := NewPythonDetector()
+
+	var builder strings.Builder
+	expected := []int{}
+	for i := 1; i <= 1000; i++ {
+		if i%97 == 0 {
+			builder.WriteString("except:\n")
+			expected = append(expected, i)
+		} else {
+			builder.WriteString(fmt.Sprintf("x = %d\n", i))
+		}
+	}
+
+	matches, err := detector.DetectCode(builder.String(), "app.py")
+	assert.NoError(t, err)
+
+	lines := []int{}
+	for _, match := range matches {
+		if match.Signature.ID == "PY012" {
+			lines = append(lines, match.LineNumber)
+		}
+	}
+	assert.Equal(t, expected, lines)
+}
+
+// 测试超过大小限制的文件被跳过
+func TestDetectFileSizeLimit(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "stream-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	filePath := filepath.Join(tmpdir, "large.py")
+	content := "result = eval(user_input)\n" + strings.Repeat("# "+strings.Repeat("p", 1000)+"\n", 1100)
+	assert.NoError(t, ioutil.WriteFile(filePath, []byte(content), 0644))
+
+	detector := NewPythonDetector()
+	detector.SetMaxFileSizeMB(1)
+	matches, err := detector.DetectFile(filePath)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+
+	detector.SetMaxFileSizeMB(0)
+	matches, err = detector.DetectFile(filePath)
+	assert.NoError(t, err)
+	assert.True(t, hasSignature(matches, "PY001"))
+}
@@ -0,0 +1,213 @@
+package detectors
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// tlsRule holds the patterns of TLS001 for the files with the given extensions
+type tlsRule struct {
+	extensions []string
+	patterns   []string
+}
+
+// tlsRules are the ways of disabling certificate verification in each language.
+// Java trust managers and hostname verifiers span several lines, so patterns are
+// matched against the whole code and reported at the line they start on.
+var tlsRules = []tlsRule{
+	{
+		extensions: []string{".go"},
+		patterns: []string{
+			`\bInsecureSkipVerify\s*[:=]\s*true\b`,
+		},
+	},
+	{
+		extensions: []string{".py"},
+		patterns: []string{
+			`\bverify\s*=\s*False\b`,
+			`\bssl\._create_unverified_context\b`,
+			`\b(cert_reqs|verify_mode)\s*=\s*(ssl\.)?CERT_NONE\b`,
+		},
+	},
+	{
+		extensions: []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx"},
+		patterns: []string{
+			`\brejectUnauthorized\s*:\s*false\b`,
+			`\bNODE_TLS_REJECT_UNAUTHORIZED\s*=\s*['"]?0\b`,
+		},
+	},
+	{
+		extensions: []string{".java"},
+		patterns: []string{
+			`\bvoid\s+checkServerTrusted\s*\([^)]*\)\s*(throws\s+[\w.,\s]+)?\{\s*\}`,
+			`\bboolean\s+verify\s*\(\s*String\s+\w+\s*,\s*SSLSession\s+\w+\s*\)\s*\{\s*return\s+true\s*;\s*\}`,
+			`\bsetHostnameVerifier\s*\(\s*\(\s*\w+\s*,\s*\w+\s*\)\s*->\s*true\s*\)`,
+			`\bNoopHostnameVerifier\b`,
+		},
+	},
+}
+
+// tlsSignature is the rule family of disabled certificate verification, shared by all
+// languages so that it can be disabled or baselined with one ID
+var tlsSignature = core.Signature{
+	ID:          "TLS001",
+	Name:        "TLS certificate verification disabled",
+	Severity:    "high",
+	Description: "Disabling certificate or hostname verification accepts any certificate and allows man-in-the-middle attacks; fix the trust store or pin the certificate instead",
+	Category:    "transport",
+	CWE:         "CWE-295",
+	CodePatterns: func() []string {
+		var patterns []string
+		for _, rule := range tlsRules {
+			patterns = append(patterns, rule.patterns...)
+		}
+		return patterns
+	}(),
+	References: []string{
+		"https://cwe.mitre.org/data/definitions/295.html",
+		"https://owasp.org/www-community/attacks/Manipulator-in-the-middle_attack",
+	},
+	Docs: &core.SignatureDocs{
+		VulnerableExample: "requests.get(url, verify=False)",
+		SafeExample:       `requests.get(url, verify="/etc/ssl/certs/internal-ca.pem")`,
+		Remediation:       "Add the server's CA to the trust store, or pass it to the client, instead of turning verification off.",
+	},
+}
+
+// tlsTestPathRe matches paths of test code, where verification is often disabled on purpose
+var tlsTestPathRe = regexp.MustCompile(`(?i)(_test\.go$|(^|/)test_[^/]*\.py$|\.(test|spec)\.[jt]sx?$|Test\.java$|(^|/)(test|tests|__tests__)/)`)
+
+// TLSDetector is a detector for disabled TLS certificate verification in Go, Python,
+// JavaScript, TypeScript and Java, reported as TLS001 in every language
+type TLSDetector struct {
+	core.BaseDetector
+}
+
+// NewTLSDetector creates a new TLS detector
+func NewTLSDetector() *TLSDetector {
+	return &TLSDetector{}
+}
+
+// Name returns the name of the detector
+func (d *TLSDetector) Name() string {
+	return "tls"
+}
+
+// SupportedLanguages returns the list of supported languages
+func (d *TLSDetector) SupportedLanguages() []string {
+	var languages []string
+	for _, rule := range tlsRules {
+		for _, ext := range rule.extensions {
+			languages = append(languages, strings.TrimPrefix(ext, "."))
+		}
+	}
+	return languages
+}
+
+// Signatures returns every signature the detector can report
+func (d *TLSDetector) Signatures() []core.Signature {
+	return []core.Signature{tlsSignature}
+}
+
+// DetectFile detects vulnerabilities in a file
+func (d *TLSDetector) DetectFile(filePath string) ([]core.Match, error) {
+	// Check if file type is supported
+	if tlsRuleFor(filePath) == nil {
+		return nil, nil
+	}
+
+	// Read file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DetectCode(string(content), filePath)
+}
+
+// DetectReader detects vulnerabilities in code read from r
+func (d *TLSDetector) DetectReader(r io.Reader, filePath string) ([]core.Match, error) {
+	return core.ReadAndDetect(d, r, filePath)
+}
+
+// DetectCode detects vulnerabilities in code
+func (d *TLSDetector) DetectCode(code string, filePath string) ([]core.Match, error) {
+	matches := []core.Match{}
+
+	rule := tlsRuleFor(filePath)
+	if rule == nil {
+		return matches, nil
+	}
+
+	lines := strings.Split(code, "\n")
+	reported := make(map[int]bool)
+	for _, pattern := range rule.patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+
+		for _, loc := range re.FindAllStringIndex(code, -1) {
+			lineNumber := 1 + strings.Count(code[:loc[0]], "\n")
+			line := strings.TrimSpace(lines[lineNumber-1])
+			if reported[lineNumber] || isCommentLine(line) {
+				continue
+			}
+			reported[lineNumber] = true
+
+			confidence, factors := d.calculateConfidence(filePath)
+			matches = append(matches, core.Match{
+				Signature:   tlsSignature,
+				FilePath:    filePath,
+				LineNumber:  lineNumber,
+				MatchedCode: line,
+				Confidence:  confidence,
+				Explanation: &core.Explanation{
+					Pattern: pattern,
+					Factors: factors,
+				},
+			})
+		}
+	}
+
+	return matches, nil
+}
+
+// calculateConfidence calculates the confidence of a match and the factors it is made of
+func (d *TLSDetector) calculateConfidence(filePath string) (float64, []core.ConfidenceFactor) {
+	// Verification is almost never disabled by accident
+	factors := confidenceFactors{{Reason: "base confidence", Value: 0.9}}
+
+	// Tests often talk to servers with self-signed certificates
+	if tlsTestPathRe.MatchString(filepath.ToSlash(filePath)) {
+		factors.add("file is test code", -0.2)
+	}
+
+	// Ensure confidence is between 0 and 1
+	confidence := factors.total()
+
+	return confidence, factors
+}
+
+// tlsRuleFor returns the rule for a file by its extension, or nil if it has none
+func tlsRuleFor(filePath string) *tlsRule {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for i, rule := range tlsRules {
+		for _, e := range rule.extensions {
+			if e == ext {
+				return &tlsRules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// isCommentLine reports whether a trimmed line is a comment in one of the supported languages
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "/*") || strings.HasPrefix(line, "*")
+}
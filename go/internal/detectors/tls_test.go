@@ -0,0 +1,67 @@
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 关闭证书校验的Java代码
+const insecureJava = `package com.example;
+
+public class TrustAll implements X509TrustManager {
+    public void checkClientTrusted(X509Certificate[] chain, String authType) {}
+
+    public void checkServerTrusted(X509Certificate[] chain, String authType)
+            throws CertificateException {
+    }
+
+    public boolean verify(String hostname, SSLSession session) {
+        return true;
+    }
+
+    // connection.setHostnameVerifier((host, session) -> true);
+    void open(HttpsURLConnection connection) {
+        connection.setHostnameVerifier((host, session) -> true);
+    }
+}
+`
+
+// 测试各语言中关闭证书校验的写法都报告为TLS001
+func TestTLSDetector(t *testing.T) {
+	detector := NewTLSDetector()
+
+	tests := []struct {
+		filePath string
+		code     string
+		lines    []int
+	}{
+		{"client.go", "tr := &http.Transport{\n\tTLSClientConfig: &tls.Config{InsecureSkipVerify: true},\n}\n", []int{2}},
+		{"client.go", "cfg.InsecureSkipVerify = true\n// InsecureSkipVerify: true\ncfg.InsecureSkipVerify = false\n", []int{1}},
+		{"client.py", "requests.get(url, verify=False)\nctx = ssl._create_unverified_context()\nrequests.get(url, verify=True)\n", []int{1, 2}},
+		{"client.py", "# requests.get(url, verify=False)\nsock = ssl.wrap_socket(s, cert_reqs=ssl.CERT_NONE)\n", []int{2}},
+		{"client.js", "https.request({ host, rejectUnauthorized: false })\nprocess.env.NODE_TLS_REJECT_UNAUTHORIZED = '0'\n", []int{1, 2}},
+		{"client.ts", "const agent = new https.Agent({\n  rejectUnauthorized: true,\n})\n", []int{}},
+		{"TrustAll.java", insecureJava, []int{6, 10, 16}},
+		{"client.rb", "http.verify_mode = OpenSSL::SSL::VERIFY_NONE\n", []int{}},
+	}
+	for _, tt := range tests {
+		matches, err := detector.DetectCode(tt.code, tt.filePath)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.lines, signatureLines(matches, "TLS001"), tt.filePath)
+	}
+
+	// 几乎总是有意为之，因此置信度高；测试代码中略低
+	code := "requests.get(url, verify=False)\n"
+	assert.Equal(t, 0.9, confidenceOf(t, detector, code, "client.py", "TLS001"))
+	assert.InDelta(t, 0.7, confidenceOf(t, detector, code, "tests/test_client.py", "TLS001"), 0.001)
+
+	matches, err := detector.DetectCode(code, "client.py")
+	assert.NoError(t, err)
+	match := findSignature(matches, "TLS001")
+	if assert.NotNil(t, match) {
+		assert.Equal(t, "high", match.Signature.Severity)
+		assert.Equal(t, "CWE-295", match.Signature.CWE)
+	}
+	assert.Contains(t, detector.SupportedLanguages(), "java")
+}
@@ -0,0 +1,72 @@
+package detectors
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// toctouSignature returns the time-of-check-to-time-of-use signature with the given ID
+func toctouSignature(id string) core.Signature {
+	return core.Signature{
+		ID:          id,
+		Name:        "File access race condition (TOCTOU)",
+		Severity:    "medium",
+		Description: "Checking a file before opening it leaves a window in which the file can be replaced, e.g. by a symlink; open the file directly and handle the error instead",
+		Category:    "filesystem",
+		CWE:         "CWE-367",
+		References: []string{
+			"https://cwe.mitre.org/data/definitions/367.html",
+		},
+	}
+}
+
+// toctouPattern pairs a file check with a later use of the same path.
+// Both expressions capture the path argument in the "path" group.
+type toctouPattern struct {
+	check *regexp.Regexp
+	use   *regexp.Regexp
+}
+
+// pythonTOCTOU matches os.path.exists(path) and similar checks followed by open(path)
+var pythonTOCTOU = toctouPattern{
+	check: regexp.MustCompile(`\bos\.(path\.(exists|isfile|islink)|access|stat|lstat)\s*\(\s*(?P<path>[^,)]+)`),
+	use:   regexp.MustCompile(`(\bos\.|[^.\w]|^)(open|remove|unlink|chmod|chown)\s*\(\s*(?P<path>[^,)]+)`),
+}
+
+// javascriptTOCTOU matches fs.existsSync(path) and similar checks followed by fs.openSync(path) and friends
+var javascriptTOCTOU = toctouPattern{
+	check: regexp.MustCompile(`\bfs\.(existsSync|statSync|lstatSync|accessSync|exists|stat|lstat|access)\s*\(\s*(?P<path>[^,)]+)`),
+	use:   regexp.MustCompile(`\bfs\.(openSync|readFileSync|writeFileSync|unlinkSync|chmodSync|open|readFile|writeFile|unlink|chmod|createReadStream|createWriteStream)\s*\(\s*(?P<path>[^,)]+)`),
+}
+
+// findTOCTOU reports uses of a path that was checked on an earlier line of the code
+func findTOCTOU(code string, filePath string, signature core.Signature, pattern toctouPattern) []core.Match {
+	matches := []core.Match{}
+	checked := make(map[string]bool)
+
+	for i, line := range strings.Split(code, "\n") {
+		if m := pattern.use.FindStringSubmatch(line); m != nil {
+			if checked[strings.TrimSpace(m[pattern.use.SubexpIndex("path")])] {
+				matches = append(matches, core.Match{
+					Signature:   signature,
+					FilePath:    filePath,
+					LineNumber:  i + 1,
+					MatchedCode: strings.TrimSpace(line),
+					Confidence:  0.75,
+					Explanation: &core.Explanation{
+						Pattern: pattern.check.String() + " followed by " + pattern.use.String(),
+						Factors: []core.ConfidenceFactor{{Reason: "fixed confidence of check-then-use heuristics", Value: 0.75}},
+					},
+				})
+			}
+		}
+
+		for _, m := range pattern.check.FindAllStringSubmatch(line, -1) {
+			checked[strings.TrimSpace(m[pattern.check.SubexpIndex("path")])] = true
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,118 @@
+package detectors
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// typeScriptSignatures returns the signatures that only apply to TypeScript code.
+// They look for escapes from the type checker, which plain JavaScript does not have,
+// so .js and .jsx files never trigger them.
+func typeScriptSignatures() []core.Signature {
+	return []core.Signature{
+		{
+			ID:          "TS002",
+			Name:        "@ts-ignore hiding an unsafe cast",
+			Severity:    "medium",
+			Description: "A @ts-ignore comment on an any or double cast hides the type error that would flag the unchecked value",
+			Category:    "type-safety",
+			CWE:         "CWE-704",
+			CodePatterns: []string{
+				`//[ \t]*@ts-ignore[^\n]*\n[^\n]*(\bas\s+any\b|\bas\s+unknown\s+as\b|<any>)`,
+			},
+			References: []string{
+				"https://www.typescriptlang.org/docs/handbook/intro-to-js-ts.html#ts-check",
+			},
+			Multiline: true,
+		},
+		{
+			ID:          "TS003",
+			Name:        "User input cast to any",
+			Severity:    "medium",
+			Description: "Casting request data to any disables type checking on untrusted input; validate it against a schema instead",
+			Category:    "type-safety",
+			CWE:         "CWE-20",
+			CodePatterns: []string{
+				`\b(req|request|ctx\.request)\.(body|query|params|headers|cookies)\b[^;\n]*\bas\s+any\b`,
+				`<any>\s*(req|request|ctx\.request)\.(body|query|params|headers|cookies)\b`,
+			},
+			References: []string{
+				"https://cheatsheetseries.owasp.org/cheatsheets/Input_Validation_Cheat_Sheet.html",
+			},
+		},
+	}
+}
+
+// isTypeScript reports whether a file is a TypeScript file by its extension
+func isTypeScript(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return ext == ".ts" || ext == ".tsx"
+}
+
+// signaturesFor returns the signatures that apply to a file: the JavaScript signatures,
+// plus the TypeScript signatures for .ts and .tsx files
+func (d *JavaScriptDetector) signaturesFor(filePath string) []core.Signature {
+	if !isTypeScript(filePath) {
+		return d.signatures
+	}
+	return append(append([]core.Signature{}, d.signatures...), d.tsSignatures...)
+}
+
+// tsJSONParseEvalSignature is the signature of the any-typed JSON.parse check
+var tsJSONParseEvalSignature = core.Signature{
+	ID:          "TS001",
+	Name:        "Untyped JSON.parse result evaluated as code",
+	Severity:    "high",
+	Description: "An any-typed JSON.parse result is passed to eval or a similar sink, so untrusted input runs as code without a type error",
+	Category:    "injection",
+	CWE:         "CWE-95",
+	References: []string{
+		"https://developer.mozilla.org/en-US/docs/Web/JavaScript/Reference/Global_Objects/eval#never_use_eval!",
+	},
+}
+
+var (
+	tsAnyJSONParseRe = regexp.MustCompile(`(?:const|let|var)\s+(\w+)\s*(:\s*any\b)?\s*=\s*(<any>\s*)?JSON\.parse\s*\([^;\n]*?\)(\s+as\s+any\b)?`)
+	tsCodeSinkRe     = regexp.MustCompile(`\b(eval|new\s+Function|setTimeout|setInterval)\s*\(`)
+)
+
+// checkTypeScriptSpecificIssues performs additional TypeScript-specific checks
+func (d *JavaScriptDetector) checkTypeScriptSpecificIssues(code string, filePath string) []core.Match {
+	matches := []core.Match{}
+
+	// Check for any-typed JSON.parse results that flow into code evaluation
+	for _, m := range tsAnyJSONParseRe.FindAllStringSubmatchIndex(code, -1) {
+		if m[4] < 0 && m[6] < 0 && m[8] < 0 {
+			// Typed results are checked by the compiler
+			continue
+		}
+		name := code[m[2]:m[3]]
+		argRe := regexp.MustCompile(`^[^;\n]*\b` + regexp.QuoteMeta(name) + `\b`)
+
+		for _, sink := range tsCodeSinkRe.FindAllStringIndex(code[m[1]:], -1) {
+			rest := code[m[1]+sink[1]:]
+			if !argRe.MatchString(rest) {
+				continue
+			}
+
+			start := m[1] + sink[0]
+			end := strings.IndexByte(code[start:], '\n')
+			if end < 0 {
+				end = len(code) - start
+			}
+
+			matches = append(matches, core.Match{
+				Signature:   tsJSONParseEvalSignature,
+				FilePath:    filePath,
+				LineNumber:  1 + strings.Count(code[:start], "\n"),
+				MatchedCode: strings.TrimSpace(code[start : start+end]),
+				Confidence:  0.8,
+			})
+		}
+	}
+
+	return matches
+}
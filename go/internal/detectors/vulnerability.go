@@ -3,8 +3,9 @@ package detectors
 import (
 	"encoding/json"
 	"fmt"
+	"go/parser"
+	"go/token"
 	"io/ioutil"
-	"os"
 	"regexp"
 	"strings"
 	"sync"
@@ -19,6 +20,9 @@ type Signature struct {
 	Name         string   `json:"name"`
 	Severity     string   `json:"severity"`
 	CodePatterns []string `json:"code_patterns"`
+
+	// MinConfidence 为该签名的置信度阈值，为0时使用检测器的全局阈值
+	MinConfidence float64 `json:"min_confidence"`
 }
 
 // Match 表示漏洞匹配结果
@@ -94,6 +98,10 @@ func (d *VulnerabilityDetector) DetectFile(filePath string) ([]Match, error) {
 		wg.Add(1)
 		go func(signature Signature) {
 			defer wg.Done()
+			threshold := d.minConfidence
+			if signature.MinConfidence > 0 {
+				threshold = signature.MinConfidence
+			}
 			for _, pattern := range signature.CodePatterns {
 				re, err := regexp.Compile(pattern)
 				if err != nil {
@@ -105,7 +113,7 @@ func (d *VulnerabilityDetector) DetectFile(filePath string) ([]Match, error) {
 					matchedCode := string(content[match[0]:match[1]])
 					confidence := d.calculateConfidence(matchedCode, pattern)
 
-					if confidence >= d.minConfidence {
+					if confidence >= threshold {
 						// 计算行号
 						lineNumber := 1 + strings.Count(string(content[:match[0]]), "\n")
 						matchChan <- Match{
@@ -132,7 +140,9 @@ func (d *VulnerabilityDetector) DetectFile(filePath string) ([]Match, error) {
 
 // AnalyzeAST 分析AST节点中的漏洞
 func (d *VulnerabilityDetector) AnalyzeAST(filePath string) ([]Match, error) {
-	fset, node, err := decorator.ParseFile(filePath, nil)
+	fset := token.NewFileSet()
+	dec := decorator.NewDecorator(fset)
+	node, err := dec.ParseFile(filePath, nil, parser.AllErrors)
 	if err != nil {
 		return nil, fmt.Errorf("解析文件失败: %v", err)
 	}
@@ -163,7 +173,7 @@ func (d *VulnerabilityDetector) AnalyzeAST(filePath string) ([]Match, error) {
 					if matched, _ := regexp.MatchString(pattern, funcName); matched {
 						matches = append(matches, Match{
 							Signature:   sig,
-							LineNumber:  fset.Position(call.Pos()).Line,
+							LineNumber:  fset.Position(dec.Ast.Nodes[call].Pos()).Line,
 							MatchedCode: funcName,
 							Confidence:  0.9,
 						})
@@ -179,7 +189,9 @@ func (d *VulnerabilityDetector) AnalyzeAST(filePath string) ([]Match, error) {
 
 // DetectSimilarPatterns 检测相似的漏洞模式
 func (d *VulnerabilityDetector) DetectSimilarPatterns(filePath string, threshold float64) ([]Match, error) {
-	fset, node, err := decorator.ParseFile(filePath, nil)
+	fset := token.NewFileSet()
+	dec := decorator.NewDecorator(fset)
+	node, err := dec.ParseFile(filePath, nil, parser.AllErrors)
 	if err != nil {
 		return nil, fmt.Errorf("解析文件失败: %v", err)
 	}
@@ -211,7 +223,7 @@ func (d *VulnerabilityDetector) DetectSimilarPatterns(filePath string, threshold
 					if similarity >= threshold {
 						matches = append(matches, Match{
 							Signature:   sig,
-							LineNumber:  fset.Position(call.Pos()).Line,
+							LineNumber:  fset.Position(dec.Ast.Nodes[call].Pos()).Line,
 							MatchedCode: funcName,
 							Confidence:  similarity,
 						})
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"sync"
+	"time"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// DedupNotifier wraps a notifier so that a finding already notified within the
+// window is not notified again. Findings are keyed by core.Fingerprint.
+type DedupNotifier struct {
+	next   Notifier
+	window time.Duration
+	seen   map[string]time.Time
+	mutex  sync.Mutex
+	now    func() time.Time
+}
+
+// NewDedupNotifier creates a notifier that drops findings seen within the window
+func NewDedupNotifier(next Notifier, window time.Duration) *DedupNotifier {
+	return &DedupNotifier{
+		next:   next,
+		window: window,
+		seen:   make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Notify forwards the findings not notified within the window
+func (n *DedupNotifier) Notify(matches []core.Match) error {
+	n.mutex.Lock()
+	now := n.now()
+
+	// Drop expired entries so the seen set does not grow without bound
+	for fingerprint, notifiedAt := range n.seen {
+		if now.Sub(notifiedAt) >= n.window {
+			delete(n.seen, fingerprint)
+		}
+	}
+
+	fresh := []core.Match{}
+	fingerprints := []string{}
+	batch := make(map[string]bool)
+	for _, match := range matches {
+		fingerprint := core.Fingerprint(match)
+		if _, ok := n.seen[fingerprint]; ok || batch[fingerprint] {
+			continue
+		}
+		batch[fingerprint] = true
+		fresh = append(fresh, match)
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	n.mutex.Unlock()
+
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	if err := n.next.Notify(fresh); err != nil {
+		// Not recorded as seen, so the findings are retried on the next scan
+		return err
+	}
+
+	n.mutex.Lock()
+	for _, fingerprint := range fingerprints {
+		n.seen[fingerprint] = now
+	}
+	n.mutex.Unlock()
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 记录通知内容的通知器
+type recordingNotifier struct {
+	notified [][]core.Match
+}
+
+func (n *recordingNotifier) Notify(matches []core.Match) error {
+	n.notified = append(n.notified, matches)
+	return nil
+}
+
+// 测试去重窗口内不重复通知，过期后重新通知
+func TestDedupNotifier(t *testing.T) {
+	recorder := &recordingNotifier{}
+	notifier := NewDedupNotifier(recorder, time.Hour)
+
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return current }
+
+	match := core.Match{
+		Signature:   core.Signature{ID: "PY001"},
+		FilePath:    "app.py",
+		LineNumber:  3,
+		MatchedCode: "eval(x)",
+	}
+
+	// 首次通知
+	assert.NoError(t, notifier.Notify([]core.Match{match}))
+	assert.Len(t, recorder.notified, 1)
+
+	// 窗口内重复扫描（即使行号变化）不再通知
+	current = current.Add(30 * time.Minute)
+	moved := match
+	moved.LineNumber = 10
+	assert.NoError(t, notifier.Notify([]core.Match{moved}))
+	assert.Len(t, recorder.notified, 1)
+
+	// 新的问题仍会通知
+	other := match
+	other.Signature.ID = "PY002"
+	assert.NoError(t, notifier.Notify([]core.Match{match, other}))
+	assert.Len(t, recorder.notified, 2)
+	assert.Equal(t, []core.Match{other}, recorder.notified[1])
+
+	// 窗口过期后重新通知
+	current = current.Add(time.Hour)
+	assert.NoError(t, notifier.Notify([]core.Match{match}))
+	assert.Len(t, recorder.notified, 3)
+}
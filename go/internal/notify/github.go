@@ -0,0 +1,245 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// DefaultGitHubAPIURL is the base URL of the GitHub REST API
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// pullRequestRe matches pull request references of the form owner/repo#123
+var pullRequestRe = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)#(\d+)$`)
+
+// hunkHeaderRe matches the header of a unified diff hunk and captures the first new line
+var hunkHeaderRe = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// GitHubReviewNotifier posts findings on the lines changed by a pull request as the
+// comments of a single review. Findings on other lines are dropped, as are findings
+// that already have an identical comment on the pull request. Match file paths must
+// be relative to the repository root.
+type GitHubReviewNotifier struct {
+	baseURL string
+	owner   string
+	repo    string
+	number  int
+	token   string
+	client  *http.Client
+}
+
+// ParsePullRequest splits a pull request reference of the form owner/repo#123
+func ParsePullRequest(ref string) (string, string, int, error) {
+	m := pullRequestRe.FindStringSubmatch(ref)
+	if m == nil {
+		return "", "", 0, fmt.Errorf("invalid pull request: %s (expected owner/repo#number)", ref)
+	}
+	number, err := strconv.Atoi(m[3])
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid pull request number: %s", m[3])
+	}
+	return m[1], m[2], number, nil
+}
+
+// NewGitHubReviewNotifier creates a notifier that reviews the given pull request
+func NewGitHubReviewNotifier(owner string, repo string, number int, token string) *GitHubReviewNotifier {
+	return &GitHubReviewNotifier{
+		baseURL: DefaultGitHubAPIURL,
+		owner:   owner,
+		repo:    repo,
+		number:  number,
+		token:   token,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// SetBaseURL sets the base URL of the GitHub API, e.g. for GitHub Enterprise
+func (n *GitHubReviewNotifier) SetBaseURL(url string) {
+	n.baseURL = strings.TrimRight(url, "/")
+}
+
+// reviewComment is a review comment on a line of the new version of a file
+type reviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side,omitempty"`
+	Body string `json:"body"`
+}
+
+// reviewPayload is the JSON body posted to create a review
+type reviewPayload struct {
+	CommitID string          `json:"commit_id"`
+	Body     string          `json:"body"`
+	Event    string          `json:"event"`
+	Comments []reviewComment `json:"comments"`
+}
+
+// Notify creates a review with one comment per finding on a changed line.
+// No review is created if no finding is left to comment on.
+func (n *GitHubReviewNotifier) Notify(matches []core.Match) error {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	prPath := fmt.Sprintf("/repos/%s/%s/pulls/%d", n.owner, n.repo, n.number)
+
+	var pr struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := n.request(http.MethodGet, prPath, nil, &pr); err != nil {
+		return err
+	}
+
+	// Lines added by the pull request, by file
+	changed := make(map[string]map[int]bool)
+	err := n.paginate(prPath+"/files", func(page []byte) (int, error) {
+		var files []struct {
+			Filename string `json:"filename"`
+			Patch    string `json:"patch"`
+		}
+		if err := json.Unmarshal(page, &files); err != nil {
+			return 0, err
+		}
+		for _, file := range files {
+			changed[file.Filename] = ChangedLines(file.Patch)
+		}
+		return len(files), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Comments already on the pull request, so that re-runs do not repeat them
+	existing := make(map[reviewComment]bool)
+	err = n.paginate(prPath+"/comments", func(page []byte) (int, error) {
+		var comments []reviewComment
+		if err := json.Unmarshal(page, &comments); err != nil {
+			return 0, err
+		}
+		for _, comment := range comments {
+			existing[reviewComment{Path: comment.Path, Line: comment.Line, Body: comment.Body}] = true
+		}
+		return len(comments), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	comments := []reviewComment{}
+	for _, match := range matches {
+		path := strings.TrimPrefix(match.FilePath, "./")
+		if !changed[path][match.LineNumber] {
+			continue
+		}
+		key := reviewComment{Path: path, Line: match.LineNumber, Body: reviewCommentBody(match)}
+		if existing[key] {
+			continue
+		}
+		existing[key] = true
+
+		key.Side = "RIGHT"
+		comments = append(comments, key)
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+
+	return n.request(http.MethodPost, prPath+"/reviews", reviewPayload{
+		CommitID: pr.Head.SHA,
+		Body:     fmt.Sprintf("Re-movery found %d issue(s) in the changed lines.", len(comments)),
+		Event:    "COMMENT",
+		Comments: comments,
+	}, nil)
+}
+
+// reviewCommentBody formats a finding as the Markdown body of a review comment
+func reviewCommentBody(match core.Match) string {
+	return fmt.Sprintf("**%s: %s** (%s severity)\n\n%s", match.Signature.ID, match.Signature.Name, match.Signature.Severity, match.Signature.Description)
+}
+
+// ChangedLines returns the line numbers in the new version of a file that a unified diff patch adds
+func ChangedLines(patch string) map[int]bool {
+	lines := make(map[int]bool)
+	line := 0
+	for _, text := range strings.Split(patch, "\n") {
+		if m := hunkHeaderRe.FindStringSubmatch(text); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			continue
+		}
+		switch {
+		case strings.HasPrefix(text, "+"):
+			lines[line] = true
+			line++
+		case strings.HasPrefix(text, "-"), strings.HasPrefix(text, "\\"):
+			// Removed lines and "\ No newline at end of file" do not exist in the new file
+		default:
+			line++
+		}
+	}
+	return lines
+}
+
+// paginate fetches all pages of a list endpoint, passing each page to handle,
+// which returns the number of items on the page
+func (n *GitHubReviewNotifier) paginate(path string, handle func(page []byte) (int, error)) error {
+	const perPage = 100
+	for page := 1; ; page++ {
+		var body json.RawMessage
+		if err := n.request(http.MethodGet, fmt.Sprintf("%s?per_page=%d&page=%d", path, perPage, page), nil, &body); err != nil {
+			return err
+		}
+		count, err := handle(body)
+		if err != nil {
+			return err
+		}
+		if count < perPage {
+			return nil
+		}
+	}
+}
+
+// request sends an authenticated request to the GitHub API and decodes the JSON response into out, if given
+func (n *GitHubReviewNotifier) request(method string, path string, in interface{}, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, n.baseURL+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+n.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("GitHub API %s %s returned status %d", method, path, resp.StatusCode)
+	}
+
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
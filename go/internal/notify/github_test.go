@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试解析拉取请求引用
+func TestParsePullRequest(t *testing.T) {
+	owner, repo, number, err := ParsePullRequest("re-movery/re-movery#123")
+	assert.NoError(t, err)
+	assert.Equal(t, "re-movery", owner)
+	assert.Equal(t, "re-movery", repo)
+	assert.Equal(t, 123, number)
+
+	for _, ref := range []string{"re-movery#123", "re-movery/re-movery", "re-movery/re-movery#abc"} {
+		_, _, _, err = ParsePullRequest(ref)
+		assert.Error(t, err, ref)
+	}
+}
+
+// 测试从补丁中提取新增行
+func TestChangedLines(t *testing.T) {
+	patch := "@@ -1,3 +1,4 @@\n import os\n-x = 1\n+x = eval(y)\n+z = 2\n print(x)\n@@ -10,2 +11,2 @@\n a = 1\n+b = 2\n\\ No newline at end of file"
+	assert.Equal(t, map[int]bool{2: true, 3: true, 12: true}, ChangedLines(patch))
+}
+
+// 测试对模拟的GitHub API创建审查评论
+func TestGitHubReviewNotifier(t *testing.T) {
+	var review reviewPayload
+	reviews := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret-token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"head": {"sha": "abc123"}}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"filename": "app.py", "patch": "@@ -1,2 +1,3 @@\n import os\n+x = eval(y)\n+z = pickle.loads(y)\n"}]`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		existing := []reviewComment{{Path: "app.py", Line: 3, Body: reviewCommentBody(core.Match{Signature: core.Signature{ID: "PY003", Name: "Insecure deserialization", Severity: "high"}})}}
+		json.NewEncoder(w).Encode(existing)
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7/reviews", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		reviews++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	notifier := NewGitHubReviewNotifier("owner", "repo", 7, "secret-token")
+	notifier.SetBaseURL(server.URL)
+
+	eval := core.Signature{ID: "PY001", Name: "Dangerous eval() usage", Severity: "high", Description: "Using eval() can execute arbitrary code"}
+	err := notifier.Notify([]core.Match{
+		// 新增行上的问题
+		{Signature: eval, FilePath: "app.py", LineNumber: 2},
+		// 已有相同评论的问题
+		{Signature: core.Signature{ID: "PY003", Name: "Insecure deserialization", Severity: "high"}, FilePath: "app.py", LineNumber: 3},
+		// 未修改行和未修改文件上的问题
+		{Signature: eval, FilePath: "app.py", LineNumber: 1},
+		{Signature: eval, FilePath: "other.py", LineNumber: 2},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, reviews)
+	assert.Equal(t, "abc123", review.CommitID)
+	assert.Equal(t, "COMMENT", review.Event)
+	assert.Equal(t, []reviewComment{{
+		Path: "app.py",
+		Line: 2,
+		Side: "RIGHT",
+		Body: "**PY001: Dangerous eval() usage** (high severity)\n\nUsing eval() can execute arbitrary code",
+	}}, review.Comments)
+
+	// 没有可评论的问题时不创建审查
+	assert.NoError(t, notifier.Notify([]core.Match{{Signature: eval, FilePath: "app.py", LineNumber: 1}}))
+	assert.Equal(t, 1, reviews)
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// Notifier sends scan findings to an external channel
+type Notifier interface {
+	Notify(matches []core.Match) error
+}
+
+// WebhookNotifier posts findings as JSON to a webhook URL
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url: url,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// webhookPayload is the JSON body posted to the webhook
+type webhookPayload struct {
+	Timestamp string       `json:"timestamp"`
+	Findings  []core.Match `json:"findings"`
+}
+
+// Notify posts the matches to the webhook. Nothing is sent if there are no matches.
+func (n *WebhookNotifier) Notify(matches []core.Match) error {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Timestamp: time.Now().Format(time.RFC3339),
+		Findings:  matches,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package reporters
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// Annotator writes copies of scanned files with a comment inserted above each flagged line
+type Annotator struct{}
+
+// NewAnnotator creates a new annotator
+func NewAnnotator() *Annotator {
+	return &Annotator{}
+}
+
+// Annotate writes an annotated copy of every file with matches into outputDir,
+// mirroring each file's path relative to baseDir. The original files are not modified.
+// It returns the paths of the written copies.
+func (a *Annotator) Annotate(results map[string][]core.Match, baseDir string, outputDir string) ([]string, error) {
+	written := []string{}
+	for _, fileResult := range core.SortedResults(results) {
+		if len(fileResult.Matches) == 0 {
+			continue
+		}
+
+		filePath := fileResult.FilePath
+		relPath, err := filepath.Rel(baseDir, filePath)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			relPath = filepath.Base(filePath)
+		}
+		outputPath := filepath.Join(outputDir, relPath)
+
+		if err := a.annotateFile(filePath, outputPath, fileResult.Matches); err != nil {
+			return written, fmt.Errorf("failed to annotate %s: %v", filePath, err)
+		}
+		written = append(written, outputPath)
+	}
+
+	return written, nil
+}
+
+// annotateFile writes a copy of filePath to outputPath with the matches inserted as comments
+func (a *Annotator) annotateFile(filePath string, outputPath string, matches []core.Match) error {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	// Group annotations by line number
+	annotations := make(map[int][]string)
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		key := fmt.Sprintf("%d:%s", match.LineNumber, match.Signature.ID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		annotations[match.LineNumber] = append(annotations[match.LineNumber],
+			fmt.Sprintf("movery: %s %s [%s]", match.Signature.ID, match.Signature.Name, match.Signature.Severity))
+	}
+
+	prefix := commentPrefix(filePath)
+	lines := strings.Split(string(content), "\n")
+	var builder strings.Builder
+	for i, line := range lines {
+		if notes, ok := annotations[i+1]; ok {
+			// Indent the comment like the flagged line
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			sort.Strings(notes)
+			for _, note := range notes {
+				builder.WriteString(indent + prefix + " " + note + "\n")
+			}
+		}
+		builder.WriteString(line)
+		if i < len(lines)-1 {
+			builder.WriteString("\n")
+		}
+	}
+
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(outputPath, []byte(builder.String()), 0644)
+}
+
+// commentPrefix returns the line comment marker for a file based on its extension
+func commentPrefix(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".py", ".rb", ".sh":
+		return "#"
+	default:
+		return "//"
+	}
+}
@@ -0,0 +1,58 @@
+package reporters
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试生成带注释的文件副本
+func TestAnnotate(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "annotate-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	srcDir := filepath.Join(tmpdir, "src")
+	outDir := filepath.Join(tmpdir, "annotated")
+	assert.NoError(t, os.MkdirAll(filepath.Join(srcDir, "pkg"), 0755))
+
+	original := "import os\n\ndef run(x):\n    return eval(x)\n"
+	srcFile := filepath.Join(srcDir, "pkg", "app.py")
+	assert.NoError(t, ioutil.WriteFile(srcFile, []byte(original), 0644))
+
+	results := map[string][]core.Match{
+		srcFile: {
+			{
+				Signature: core.Signature{
+					ID:       "PY001",
+					Name:     "Dangerous eval() usage",
+					Severity: "high",
+				},
+				FilePath:   srcFile,
+				LineNumber: 4,
+			},
+		},
+	}
+
+	written, err := NewAnnotator().Annotate(results, srcDir, outDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(outDir, "pkg", "app.py")}, written)
+
+	// 注释应插入在被标记行的上方，并保持缩进
+	annotated, err := ioutil.ReadFile(written[0])
+	assert.NoError(t, err)
+	lines := strings.Split(string(annotated), "\n")
+	assert.Equal(t, "    # movery: PY001 Dangerous eval() usage [high]", lines[3])
+	assert.Equal(t, "    return eval(x)", lines[4])
+
+	// 原文件不应被修改
+	content, err := ioutil.ReadFile(srcFile)
+	assert.NoError(t, err)
+	assert.Equal(t, original, string(content))
+}
@@ -0,0 +1,115 @@
+package reporters
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// BadgeReporter is a reporter that generates a findings count badge.
+// Files ending in .svg get an SVG image; any other file gets the JSON
+// endpoint format understood by shields.io.
+type BadgeReporter struct {
+	Label string
+}
+
+// NewBadgeReporter creates a new badge reporter
+func NewBadgeReporter() *BadgeReporter {
+	return &BadgeReporter{
+		Label: "re-movery",
+	}
+}
+
+// Badge is the shields.io endpoint badge format
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// badgeColors maps a badge color name to its hex value for SVG output
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+}
+
+// NewBadge creates a badge from a scan summary, colored by the highest severity found
+func (r *BadgeReporter) NewBadge(summary core.Summary) Badge {
+	total := summary.Total()
+
+	message := "no findings"
+	if total > 0 {
+		message = fmt.Sprintf("%d high / %d total", summary.High, total)
+	}
+
+	color := "brightgreen"
+	switch {
+	case summary.High > 0:
+		color = "red"
+	case summary.Medium > 0:
+		color = "orange"
+	case summary.Low > 0:
+		color = "yellow"
+	}
+
+	return Badge{
+		SchemaVersion: 1,
+		Label:         r.Label,
+		Message:       message,
+		Color:         color,
+	}
+}
+
+// GenerateReport generates a report
+func (r *BadgeReporter) GenerateReport(data core.ReportData, outputPath string) error {
+	// Create output directory if it doesn't exist
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	badge := r.NewBadge(data.Summary)
+
+	if strings.ToLower(filepath.Ext(outputPath)) == ".svg" {
+		return ioutil.WriteFile(outputPath, []byte(r.renderSVG(badge)), 0644)
+	}
+
+	content, err := json.MarshalIndent(badge, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(outputPath, content, 0644)
+}
+
+// renderSVG renders a badge as a flat SVG image
+func (r *BadgeReporter) renderSVG(badge Badge) string {
+	// Approximate text widths for the 11px Verdana font used by shields.io
+	labelWidth := len(badge.Label)*7 + 10
+	messageWidth := len(badge.Message)*7 + 10
+	width := labelWidth + messageWidth
+
+	label := html.EscapeString(badge.Label)
+	message := html.EscapeString(badge.Message)
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">
+  <title>%s: %s</title>
+  <rect width="%d" height="20" fill="#555"/>
+  <rect x="%d" width="%d" height="20" fill="%s"/>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="%d" y="14">%s</text>
+    <text x="%d" y="14">%s</text>
+  </g>
+</svg>
+`, width, label, message, label, message,
+		labelWidth, labelWidth, messageWidth, badgeColors[badge.Color],
+		labelWidth/2, label, labelWidth+messageWidth/2, message)
+}
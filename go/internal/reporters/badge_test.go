@@ -0,0 +1,66 @@
+package reporters
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试生成shields.io格式的JSON徽章
+func TestBadgeReporterJSON(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "badge-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	data := core.ReportData{
+		Summary: core.Summary{High: 2, Medium: 3, Low: 1},
+	}
+
+	outputPath := filepath.Join(tmpdir, "badge.json")
+	assert.NoError(t, NewBadgeReporter().GenerateReport(data, outputPath))
+
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+
+	var badge map[string]interface{}
+	assert.NoError(t, json.Unmarshal(content, &badge))
+	assert.Equal(t, float64(1), badge["schemaVersion"])
+	assert.Equal(t, "re-movery", badge["label"])
+	assert.Equal(t, "2 high / 6 total", badge["message"])
+	assert.Equal(t, "red", badge["color"])
+}
+
+// 测试徽章颜色随最高严重程度变化
+func TestBadgeColor(t *testing.T) {
+	reporter := NewBadgeReporter()
+
+	badge := reporter.NewBadge(core.Summary{})
+	assert.Equal(t, "no findings", badge.Message)
+	assert.Equal(t, "brightgreen", badge.Color)
+
+	assert.Equal(t, "orange", reporter.NewBadge(core.Summary{Medium: 1, Low: 1}).Color)
+	assert.Equal(t, "yellow", reporter.NewBadge(core.Summary{Low: 1}).Color)
+}
+
+// 测试生成SVG徽章
+func TestBadgeReporterSVG(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "badge-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	outputPath := filepath.Join(tmpdir, "badge.svg")
+	assert.NoError(t, NewBadgeReporter().GenerateReport(core.ReportData{Summary: core.Summary{Low: 4}}, outputPath))
+
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "<svg"))
+	assert.Contains(t, string(content), "0 high / 4 total")
+	assert.Contains(t, string(content), "#dfb317")
+}
@@ -0,0 +1,158 @@
+package reporters
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// ANSI escape codes used by the console reporter
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiBlue   = "\033[34m"
+)
+
+// ConsoleReporter is a reporter that prints findings grouped by file, followed by a
+// summary line. Each finding starts with its file:line location, which most
+// terminals turn into a link, with the path relative to the working directory.
+type ConsoleReporter struct {
+	writer io.Writer
+	color  bool
+}
+
+// NewConsoleReporter creates a new console reporter writing to stdout, with
+// color if stdout is a terminal
+func NewConsoleReporter() *ConsoleReporter {
+	return &ConsoleReporter{
+		writer: os.Stdout,
+		color:  isTerminal(os.Stdout),
+	}
+}
+
+// SetColor enables or disables ANSI colors
+func (r *ConsoleReporter) SetColor(color bool) {
+	r.color = color
+}
+
+// SetWriter sets the writer that reports without an output path are written to
+func (r *ConsoleReporter) SetWriter(w io.Writer) {
+	r.writer = w
+}
+
+// GenerateReport generates a report. Without an output path the report is written
+// to stdout; reports written to a file never contain colors.
+func (r *ConsoleReporter) GenerateReport(data core.ReportData, outputPath string) error {
+	if outputPath == "" {
+		return r.Render(r.writer, data)
+	}
+
+	// Create output directory if it doesn't exist
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	// Create output file
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	plain := *r
+	plain.color = false
+	return plain.Render(file, data)
+}
+
+// Render writes the findings grouped by file path and ordered by line number,
+// followed by the summary line
+func (r *ConsoleReporter) Render(w io.Writer, data core.ReportData) error {
+	var out strings.Builder
+	files := 0
+	for _, fileResult := range core.SortedResults(data.Results) {
+		if len(fileResult.Matches) == 0 {
+			continue
+		}
+		files++
+
+		path := relativePath(fileResult.FilePath)
+		out.WriteString(r.paint(ansiBold, path) + "\n")
+		for _, match := range fileResult.Matches {
+			severity := strings.ToLower(match.Signature.Severity)
+			fmt.Fprintf(&out, "  %s:%d  %s  %s %s\n", path, match.LineNumber,
+				r.paint(severityColor(severity), fmt.Sprintf("%-6s", strings.ToUpper(severity))),
+				match.Signature.ID, match.Signature.Name)
+			if code := strings.TrimSpace(match.MatchedCode); code != "" {
+				fmt.Fprintf(&out, "      %s\n", strings.Replace(code, "\n", "\n      ", -1))
+			}
+		}
+		out.WriteString("\n")
+	}
+
+	summary := data.Summary
+	fmt.Fprintf(&out, "Issues found: %d (High: %s, Medium: %s, Low: %s) in %d file(s)\n",
+		summary.Total(),
+		r.paint(ansiRed, fmt.Sprint(summary.High)),
+		r.paint(ansiYellow, fmt.Sprint(summary.Medium)),
+		r.paint(ansiBlue, fmt.Sprint(summary.Low)),
+		files)
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// paint wraps text in an ANSI color if colors are enabled
+func (r *ConsoleReporter) paint(code string, text string) string {
+	if !r.color || code == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// severityColor returns the ANSI color of a severity level
+func severityColor(severity string) string {
+	switch severity {
+	case "high":
+		return ansiRed
+	case "medium":
+		return ansiYellow
+	case "low":
+		return ansiBlue
+	default:
+		return ""
+	}
+}
+
+// relativePath returns a path relative to the working directory if the path is
+// inside it, and the path unchanged otherwise
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// isTerminal reports whether a file is a terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,72 @@
+package reporters
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试控制台报告按文件分组并输出摘要
+func TestConsoleReporter(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	data := core.ReportData{
+		Results: map[string][]core.Match{
+			filepath.Join(wd, "src", "app.py"): {
+				{Signature: core.Signature{ID: "PY010", Name: "Bare except block", Severity: "low"}, LineNumber: 7, MatchedCode: "except:"},
+				{Signature: core.Signature{ID: "PY001", Name: "Dangerous eval() usage", Severity: "high"}, LineNumber: 2, MatchedCode: "  result = eval(x)  "},
+			},
+			"/elsewhere/util.js": {
+				{Signature: core.Signature{ID: "JS001", Name: "Dangerous eval() usage", Severity: "medium"}, LineNumber: 1},
+			},
+			"clean.py": {},
+		},
+	}
+	data.Summary = core.GenerateSummary(data.Results)
+
+	var out bytes.Buffer
+	reporter := NewConsoleReporter()
+	reporter.SetWriter(&out)
+	reporter.SetColor(false)
+	assert.NoError(t, reporter.GenerateReport(data, ""))
+
+	app := filepath.Join("src", "app.py")
+	assert.Equal(t, "/elsewhere/util.js\n"+
+		"  /elsewhere/util.js:1  MEDIUM  JS001 Dangerous eval() usage\n"+
+		"\n"+
+		app+"\n"+
+		"  "+app+":2  HIGH    PY001 Dangerous eval() usage\n"+
+		"      result = eval(x)\n"+
+		"  "+app+":7  LOW     PY010 Bare except block\n"+
+		"      except:\n"+
+		"\n"+
+		"Issues found: 3 (High: 1, Medium: 1, Low: 1) in 2 file(s)\n", out.String())
+
+	// 启用颜色时按严重程度着色
+	out.Reset()
+	reporter.SetColor(true)
+	assert.NoError(t, reporter.GenerateReport(data, ""))
+	assert.Contains(t, out.String(), ansiRed+"HIGH  "+ansiReset)
+	assert.Contains(t, out.String(), ansiYellow+"MEDIUM"+ansiReset)
+	assert.Contains(t, out.String(), ansiBlue+"LOW   "+ansiReset)
+
+	// 写入文件时不包含颜色
+	tmpdir, err := ioutil.TempDir("", "console-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	outputPath := filepath.Join(tmpdir, "report.txt")
+	assert.NoError(t, reporter.GenerateReport(data, outputPath))
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(string(content), "\033["))
+	assert.Contains(t, string(content), "Issues found: 3")
+}
@@ -0,0 +1,64 @@
+package reporters
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// CSVReporter is a reporter that generates CSV reports
+type CSVReporter struct{}
+
+// NewCSVReporter creates a new CSV reporter
+func NewCSVReporter() *CSVReporter {
+	return &CSVReporter{}
+}
+
+// csvHeader is the header row of a CSV report
+var csvHeader = []string{"File", "Line", "Rule ID", "Rule Name", "Severity", "Confidence", "Matched Code"}
+
+// GenerateReport generates a report
+func (r *CSVReporter) GenerateReport(data core.ReportData, outputPath string) error {
+	// Create output directory if it doesn't exist
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	// Create output file
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Write header row, even if there are no matches
+	writer := csv.NewWriter(file)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	// Write one row per match, ordered by file path and line number
+	for _, fileResult := range core.SortedResults(data.Results) {
+		for _, match := range fileResult.Matches {
+			record := []string{
+				fileResult.FilePath,
+				strconv.Itoa(match.LineNumber),
+				match.Signature.ID,
+				match.Signature.Name,
+				match.Signature.Severity,
+				strconv.FormatFloat(match.Confidence, 'f', 2, 64),
+				match.MatchedCode,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
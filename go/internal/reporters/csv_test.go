@@ -0,0 +1,72 @@
+package reporters
+
+import (
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试生成CSV报告
+func TestCSVReporter(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "csv-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	data := core.ReportData{
+		Title: "Test Report",
+		Results: map[string][]core.Match{
+			"app.py": {
+				{
+					Signature: core.Signature{
+						ID:       "PY001",
+						Name:     "Dangerous eval() usage",
+						Severity: "high",
+					},
+					FilePath:    "app.py",
+					LineNumber:  4,
+					MatchedCode: "eval(a, b)\nprint(\"done\")",
+					Confidence:  0.9,
+				},
+			},
+			"clean.py": {},
+		},
+	}
+
+	outputPath := filepath.Join(tmpdir, "report.csv")
+	assert.NoError(t, NewCSVReporter().GenerateReport(data, outputPath))
+
+	file, err := os.Open(outputPath)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	// 含逗号和换行的字段应被正确引用
+	records, err := csv.NewReader(file).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{"app.py", "4", "PY001", "Dangerous eval() usage", "high", "0.90", "eval(a, b)\nprint(\"done\")"}, records[1])
+}
+
+// 测试没有匹配时仍输出表头
+func TestCSVReporterNoMatches(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "csv-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	outputPath := filepath.Join(tmpdir, "report.csv")
+	assert.NoError(t, NewCSVReporter().GenerateReport(core.ReportData{}, outputPath))
+
+	file, err := os.Open(outputPath)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{csvHeader}, records)
+}
@@ -0,0 +1,227 @@
+package reporters
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// gitLabSchemaVersion is the version of the GitLab security report schema the report follows
+const gitLabSchemaVersion = "14.1.2"
+
+// gitLabTimeFormat is the time format of the GitLab security report schema
+const gitLabTimeFormat = "2006-01-02T15:04:05"
+
+// GitLabSASTReporter is a reporter that generates GitLab SAST reports
+// (gl-sast-report.json), which GitLab shows in merge requests and the security dashboard
+type GitLabSASTReporter struct{}
+
+// NewGitLabSASTReporter creates a new GitLab SAST reporter
+func NewGitLabSASTReporter() *GitLabSASTReporter {
+	return &GitLabSASTReporter{}
+}
+
+// GitLabReport is the root object of a GitLab SAST report
+type GitLabReport struct {
+	Version         string                `json:"version"`
+	Vulnerabilities []GitLabVulnerability `json:"vulnerabilities"`
+	Scan            GitLabScan            `json:"scan"`
+}
+
+// GitLabVulnerability is the GitLab representation of a match
+type GitLabVulnerability struct {
+	ID          string             `json:"id"`
+	Category    string             `json:"category"`
+	Name        string             `json:"name"`
+	Message     string             `json:"message"`
+	Description string             `json:"description,omitempty"`
+	CVE         string             `json:"cve"`
+	Severity    string             `json:"severity"`
+	Confidence  string             `json:"confidence"`
+	Scanner     GitLabScanner      `json:"scanner"`
+	Location    GitLabLocation     `json:"location"`
+	Identifiers []GitLabIdentifier `json:"identifiers"`
+	Links       []GitLabLink       `json:"links,omitempty"`
+}
+
+// GitLabScanner identifies the scanner that found a vulnerability
+type GitLabScanner struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Version string        `json:"version,omitempty"`
+	Vendor  *GitLabVendor `json:"vendor,omitempty"`
+}
+
+// GitLabVendor is the vendor of a scanner
+type GitLabVendor struct {
+	Name string `json:"name"`
+}
+
+// GitLabLocation is the location of a vulnerability in the repository
+type GitLabLocation struct {
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+}
+
+// GitLabIdentifier identifies the rule that found a vulnerability
+type GitLabIdentifier struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// GitLabLink is a reference for a vulnerability
+type GitLabLink struct {
+	URL string `json:"url"`
+}
+
+// GitLabScan describes the scan that produced a report
+type GitLabScan struct {
+	Analyzer  GitLabScanner `json:"analyzer"`
+	Scanner   GitLabScanner `json:"scanner"`
+	Type      string        `json:"type"`
+	StartTime string        `json:"start_time"`
+	EndTime   string        `json:"end_time"`
+	Status    string        `json:"status"`
+}
+
+// gitLabScanner is the scanner block identifying Re-movery
+var gitLabScanner = GitLabScanner{
+	ID:      "re-movery",
+	Name:    "Re-movery",
+	Version: "1.0.0",
+	Vendor:  &GitLabVendor{Name: "Re-movery"},
+}
+
+// GenerateReport generates a report
+func (r *GitLabSASTReporter) GenerateReport(data core.ReportData, outputPath string) error {
+	// Create output directory if it doesn't exist
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	// Create output file
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Marshal data to JSON
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buildGitLabReport(data))
+}
+
+// buildGitLabReport converts report data to a GitLab SAST report
+func buildGitLabReport(data core.ReportData) GitLabReport {
+	// The scan ends when the report data is created
+	end, err := time.Parse(time.RFC3339, data.Timestamp)
+	if err != nil {
+		end = time.Now()
+	}
+	start := end.Add(-time.Duration(data.Duration * float64(time.Second)))
+
+	report := GitLabReport{
+		Version:         gitLabSchemaVersion,
+		Vulnerabilities: []GitLabVulnerability{},
+		Scan: GitLabScan{
+			Analyzer:  gitLabScanner,
+			Scanner:   gitLabScanner,
+			Type:      "sast",
+			StartTime: start.UTC().Format(gitLabTimeFormat),
+			EndTime:   end.UTC().Format(gitLabTimeFormat),
+			Status:    "success",
+		},
+	}
+
+	// Sort results so output is deterministic
+	for _, fileResult := range core.SortedResults(data.Results) {
+		for _, match := range fileResult.Matches {
+			report.Vulnerabilities = append(report.Vulnerabilities, gitLabVulnerability(fileResult.FilePath, match))
+		}
+	}
+
+	return report
+}
+
+// gitLabVulnerability converts a match to a GitLab vulnerability
+func gitLabVulnerability(filePath string, match core.Match) GitLabVulnerability {
+	// The fingerprint keeps the vulnerability's identity when it moves to another line
+	fingerprint := core.Fingerprint(match)
+
+	links := []GitLabLink{}
+	for _, ref := range match.Signature.References {
+		links = append(links, GitLabLink{URL: ref})
+	}
+
+	return GitLabVulnerability{
+		ID:          fingerprint,
+		Category:    "sast",
+		Name:        match.Signature.Name,
+		Message:     match.Signature.Name,
+		Description: match.Signature.Description,
+		CVE:         fingerprint,
+		Severity:    gitLabSeverity(match.Signature.Severity),
+		Confidence:  gitLabConfidence(match.Confidence),
+		Scanner: GitLabScanner{
+			ID:   gitLabScanner.ID,
+			Name: gitLabScanner.Name,
+		},
+		Location: GitLabLocation{
+			File:      strings.TrimPrefix(filepath.ToSlash(filePath), "./"),
+			StartLine: match.LineNumber,
+		},
+		Identifiers: gitLabIdentifiers(match.Signature),
+		Links:       links,
+	}
+}
+
+// gitLabIdentifiers returns the identifiers of a signature: its rule ID and, if it
+// has one, its CWE, which GitLab links to the CWE database
+func gitLabIdentifiers(signature core.Signature) []GitLabIdentifier {
+	identifiers := []GitLabIdentifier{{
+		Type:  "re_movery_rule_id",
+		Name:  "Re-movery " + signature.ID,
+		Value: signature.ID,
+	}}
+	if signature.CWE != "" {
+		identifiers = append(identifiers, GitLabIdentifier{
+			Type:  "cwe",
+			Name:  signature.CWE,
+			Value: strings.TrimPrefix(signature.CWE, "CWE-"),
+		})
+	}
+	return identifiers
+}
+
+// gitLabSeverity maps a severity to a GitLab severity
+func gitLabSeverity(severity string) string {
+	switch core.NormalizeSeverity(severity) {
+	case "high":
+		return "High"
+	case "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	default:
+		return "Unknown"
+	}
+}
+
+// gitLabConfidence maps a match confidence to a GitLab confidence level
+func gitLabConfidence(confidence float64) string {
+	switch {
+	case confidence >= 0.9:
+		return "High"
+	case confidence >= 0.7:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
@@ -0,0 +1,85 @@
+package reporters
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试生成GitLab SAST报告
+func TestGitLabSASTReporter(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "gitlab-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	match := core.Match{
+		Signature: core.Signature{
+			ID:          "PY001",
+			Name:        "Dangerous eval() usage",
+			Severity:    "high",
+			Description: "Using eval() can execute arbitrary code and is a security risk",
+			References:  []string{"https://docs.python.org/3/library/functions.html#eval"},
+			Category:    "injection",
+			CWE:         "CWE-95",
+		},
+		FilePath:    "./src/app.py",
+		LineNumber:  12,
+		MatchedCode: "eval(data)",
+		Confidence:  0.75,
+	}
+	data := core.ReportData{
+		Title:     "Test Report",
+		Timestamp: "2024-01-01T10:00:30+02:00",
+		Duration:  30,
+		Results: map[string][]core.Match{
+			"./src/app.py": {match},
+			"clean.py":     {},
+		},
+	}
+
+	outputPath := filepath.Join(tmpdir, "gl-sast-report.json")
+	assert.NoError(t, NewGitLabSASTReporter().GenerateReport(data, outputPath))
+
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	var report GitLabReport
+	assert.NoError(t, json.Unmarshal(content, &report))
+
+	assert.Equal(t, gitLabSchemaVersion, report.Version)
+	assert.Equal(t, "sast", report.Scan.Type)
+	assert.Equal(t, "re-movery", report.Scan.Scanner.ID)
+	assert.Equal(t, "2024-01-01T08:00:00", report.Scan.StartTime)
+	assert.Equal(t, "2024-01-01T08:00:30", report.Scan.EndTime)
+
+	if assert.Len(t, report.Vulnerabilities, 1) {
+		vuln := report.Vulnerabilities[0]
+		assert.Equal(t, "sast", vuln.Category)
+		assert.Equal(t, core.Fingerprint(match), vuln.ID)
+		assert.Equal(t, vuln.ID, vuln.CVE)
+		assert.Equal(t, "High", vuln.Severity)
+		assert.Equal(t, "Medium", vuln.Confidence)
+		assert.Equal(t, GitLabLocation{File: "src/app.py", StartLine: 12}, vuln.Location)
+		assert.Equal(t, "re-movery", vuln.Scanner.ID)
+		assert.Equal(t, []GitLabIdentifier{
+			{Type: "re_movery_rule_id", Name: "Re-movery PY001", Value: "PY001"},
+			{Type: "cwe", Name: "CWE-95", Value: "95"},
+		}, vuln.Identifiers)
+		assert.Equal(t, []GitLabLink{{URL: "https://docs.python.org/3/library/functions.html#eval"}}, vuln.Links)
+	}
+}
+
+// 测试严重程度映射
+func TestGitLabSeverity(t *testing.T) {
+	assert.Equal(t, "High", gitLabSeverity("high"))
+	assert.Equal(t, "Medium", gitLabSeverity("Medium"))
+	assert.Equal(t, "Low", gitLabSeverity("low"))
+	assert.Equal(t, "Low", gitLabSeverity("info"))
+	assert.Equal(t, "High", gitLabSeverity("CRITICAL"))
+	assert.Equal(t, "Unknown", gitLabSeverity("urgent"))
+}
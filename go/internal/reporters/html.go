@@ -3,24 +3,66 @@ package reporters
 import (
 	"fmt"
 	"html/template"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
-	"time"
 
 	"github.com/re-movery/re-movery/internal/core"
 )
 
 // HTMLReporter is a reporter that generates HTML reports
-type HTMLReporter struct{}
+type HTMLReporter struct {
+	template *template.Template
+}
 
 // NewHTMLReporter creates a new HTML reporter
 func NewHTMLReporter() *HTMLReporter {
 	return &HTMLReporter{}
 }
 
+// SetTemplate sets a html/template file that replaces the embedded report template,
+// e.g. for a team's branding or columns. The template is executed with the same data
+// as the embedded one:
+//
+//	.Title                     report title
+//	.Timestamp                 scan time (RFC 3339)
+//	.Results                   map of file path to []core.Match, with Signature, LineNumber, MatchedCode, Confidence etc.
+//	                           and Explanation, if explanations are reported, with Pattern, Factors and Reason
+//	.Summary                   core.Summary with TotalFiles, High, Medium, Low, Vulnerabilities, ByCategory,
+//	                           ByFile and TopFiles, the files with the most findings, each with FilePath and Count
+//	.Categories                findings per signature category, each with Name, High, Medium, Low and Total
+//	.TopVulnerabilities.Labels names of the 10 most frequent signatures
+//	.TopVulnerabilities.Data   their finding counts
+//
+// The mul function multiplies two numbers, e.g. {{mul .Confidence 100}}. The file is
+// parsed immediately, so that an invalid template is reported before a scan starts.
+func (r *HTMLReporter) SetTemplate(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading HTML template: %v", err)
+	}
+
+	tmpl, err := parseHTMLTemplate(string(content))
+	if err != nil {
+		return fmt.Errorf("parsing HTML template %s: %v", path, err)
+	}
+	r.template = tmpl
+	return nil
+}
+
 // GenerateReport generates a report
 func (r *HTMLReporter) GenerateReport(data core.ReportData, outputPath string) error {
+	// Use the embedded template unless one was set
+	tmpl := r.template
+	if tmpl == nil {
+		var err error
+		tmpl, err = parseHTMLTemplate(htmlTemplate)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create output directory if it doesn't exist
 	outputDir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
@@ -37,16 +79,6 @@ func (r *HTMLReporter) GenerateReport(data core.ReportData, outputPath string) e
 	// Process data for the template
 	processedData := r.processData(data)
 
-	// Parse template
-	tmpl, err := template.New("report").Funcs(template.FuncMap{
-		"mul": func(a, b float64) float64 {
-			return a * b
-		},
-	}).Parse(htmlTemplate)
-	if err != nil {
-		return err
-	}
-
 	// Execute template
 	if err := tmpl.Execute(file, processedData); err != nil {
 		return err
@@ -55,6 +87,16 @@ func (r *HTMLReporter) GenerateReport(data core.ReportData, outputPath string) e
 	return nil
 }
 
+// parseHTMLTemplate parses a report template with the functions available to it
+func parseHTMLTemplate(text string) (*template.Template, error) {
+	return template.New("report").Funcs(template.FuncMap{
+		"mul": func(a, b float64) float64 {
+			return a * b
+		},
+		"severity": core.NormalizeSeverity,
+	}).Parse(text)
+}
+
 // processData processes the report data for the template
 func (r *HTMLReporter) processData(data core.ReportData) map[string]interface{} {
 	// Count vulnerabilities by type
@@ -75,7 +117,10 @@ func (r *HTMLReporter) processData(data core.ReportData) map[string]interface{}
 		vulnCountList = append(vulnCountList, vulnCount{Name: name, Count: count})
 	}
 	sort.Slice(vulnCountList, func(i, j int) bool {
-		return vulnCountList[i].Count > vulnCountList[j].Count
+		if vulnCountList[i].Count != vulnCountList[j].Count {
+			return vulnCountList[i].Count > vulnCountList[j].Count
+		}
+		return vulnCountList[i].Name < vulnCountList[j].Name
 	})
 
 	// Get top 10 vulnerabilities
@@ -86,10 +131,11 @@ func (r *HTMLReporter) processData(data core.ReportData) map[string]interface{}
 
 	// Prepare data for the template
 	processedData := map[string]interface{}{
-		"Title":     data.Title,
-		"Timestamp": data.Timestamp,
-		"Results":   data.Results,
-		"Summary":   data.Summary,
+		"Title":      data.Title,
+		"Timestamp":  data.Timestamp,
+		"Results":    sortedMatches(data.Results),
+		"Summary":    data.Summary,
+		"Categories": categoryCounts(data.Results),
 		"TopVulnerabilities": map[string]interface{}{
 			"Labels": func() []string {
 				labels := []string{}
@@ -111,6 +157,60 @@ func (r *HTMLReporter) processData(data core.ReportData) map[string]interface{}
 	return processedData
 }
 
+// categoryCount is the number of findings of a signature category by severity
+type categoryCount struct {
+	Name   string
+	High   int
+	Medium int
+	Low    int
+	Total  int
+}
+
+// categoryCounts counts the findings by signature category, most findings first.
+// Findings of signatures without a category are counted as uncategorized, and
+// truncation markers are not counted.
+func categoryCounts(results map[string][]core.Match) []categoryCount {
+	counts := make(map[string]*categoryCount)
+	for _, matches := range results {
+		for _, match := range matches {
+			if match.Signature.ID == core.TruncatedSignature.ID {
+				continue
+			}
+			name := match.Signature.Category
+			if name == "" {
+				name = "uncategorized"
+			}
+			count, ok := counts[name]
+			if !ok {
+				count = &categoryCount{Name: name}
+				counts[name] = count
+			}
+
+			switch core.NormalizeSeverity(match.Signature.Severity) {
+			case "high":
+				count.High++
+			case "medium":
+				count.Medium++
+			case "low":
+				count.Low++
+			}
+			count.Total++
+		}
+	}
+
+	list := []categoryCount{}
+	for _, count := range counts {
+		list = append(list, *count)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Total != list[j].Total {
+			return list[i].Total > list[j].Total
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
 // htmlTemplate is the HTML template for the report
 const htmlTemplate = `<!DOCTYPE html>
 <html lang="en">
@@ -182,6 +282,19 @@ const htmlTemplate = `<!DOCTYPE html>
             white-space: pre-wrap;
             margin-top: 10px;
         }
+        .context-line {
+            color: #777;
+        }
+        .match-reason {
+            color: #555;
+            font-size: 0.9em;
+        }
+        .match-line {
+            display: inline-block;
+            width: 100%;
+            background-color: #fff3cd;
+            font-weight: bold;
+        }
         .footer {
             margin-top: 30px;
             text-align: center;
@@ -235,6 +348,52 @@ const htmlTemplate = `<!DOCTYPE html>
         <canvas id="vulnerabilitiesChart"></canvas>
     </div>
     
+    {{if .Categories}}
+    <h2>Findings by Category</h2>
+    <table class="categories">
+        <thead>
+            <tr>
+                <th>Category</th>
+                <th>High</th>
+                <th>Medium</th>
+                <th>Low</th>
+                <th>Total</th>
+            </tr>
+        </thead>
+        <tbody>
+            {{range .Categories}}
+            <tr>
+                <td>{{.Name}}</td>
+                <td>{{.High}}</td>
+                <td>{{.Medium}}</td>
+                <td>{{.Low}}</td>
+                <td>{{.Total}}</td>
+            </tr>
+            {{end}}
+        </tbody>
+    </table>
+    {{end}}
+    
+    {{if .Summary.TopFiles}}
+    <h2>Most Affected Files</h2>
+    <table class="top-files">
+        <thead>
+            <tr>
+                <th>File</th>
+                <th>Findings</th>
+            </tr>
+        </thead>
+        <tbody>
+            {{range .Summary.TopFiles}}
+            <tr>
+                <td>{{.FilePath}}</td>
+                <td>{{.Count}}</td>
+            </tr>
+            {{end}}
+        </tbody>
+    </table>
+    {{end}}
+    
     <h2>Top Vulnerabilities</h2>
     <div class="chart-container">
         <canvas id="topVulnerabilitiesChart"></canvas>
@@ -259,13 +418,16 @@ const htmlTemplate = `<!DOCTYPE html>
                 </thead>
                 <tbody>
                     {{range $match := $matches}}
-                    <tr class="match-item {{$match.Signature.Severity}}">
+                    <tr class="match-item {{severity $match.Signature.Severity}}">
                         <td>{{$match.LineNumber}}</td>
                         <td>{{$match.Signature.Severity}}</td>
                         <td>
-                            <strong>{{$match.Signature.Name}}</strong>
+                            <strong>{{$match.Signature.Name}}</strong>{{if $match.Signature.CWE}} <span class="cwe">{{$match.Signature.CWE}}</span>{{end}}
                             <p>{{$match.Signature.Description}}</p>
-                            <div class="match-code">{{$match.MatchedCode}}</div>
+                            <div class="match-code">{{range $line := $match.ContextBefore}}<span class="context-line">{{$line}}</span>
+{{end}}<span class="match-line">{{$match.MatchedCode}}</span>{{range $line := $match.ContextAfter}}
+<span class="context-line">{{$line}}</span>{{end}}</div>
+                            {{with $match.Explanation}}<p class="match-reason">Why: {{.Reason}}</p>{{end}}
                         </td>
                         <td>{{printf "%.0f%%" (mul $match.Confidence 100)}}</td>
                     </tr>
@@ -0,0 +1,162 @@
+package reporters
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试HTML报告以代码片段展示上下文并高亮匹配行
+func TestHTMLReporterContext(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "html-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	data := core.ReportData{
+		Title: "Test Report",
+		Results: map[string][]core.Match{
+			"app.py": {
+				{
+					Signature: core.Signature{
+						ID:       "PY001",
+						Name:     "Dangerous eval() usage",
+						Severity: "high",
+					},
+					FilePath:      "app.py",
+					LineNumber:    2,
+					MatchedCode:   "x = eval(data)",
+					Confidence:    0.9,
+					ContextBefore: []string{"data = input()"},
+					ContextAfter:  []string{"print(x < 1)"},
+					Explanation: &core.Explanation{
+						Pattern: `eval\(`,
+						Factors: []core.ConfidenceFactor{{Reason: "base confidence", Value: 0.8}},
+					},
+				},
+			},
+		},
+		Summary: core.Summary{TotalFiles: 1, High: 1},
+	}
+
+	outputPath := filepath.Join(tmpdir, "report.html")
+	assert.NoError(t, NewHTMLReporter().GenerateReport(data, outputPath))
+
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `<span class="context-line">data = input()</span>
+<span class="match-line">x = eval(data)</span>
+<span class="context-line">print(x &lt; 1)</span>`)
+	assert.Contains(t, string(content), `<p class="match-reason">Why: matched eval\(: base confidence &#43;0.80</p>`)
+}
+
+// 测试HTML报告按规则分类汇总问题并显示CWE
+func TestHTMLReporterCategories(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "html-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	eval := core.Signature{ID: "PY001", Name: "Dangerous eval() usage", Severity: "high", Category: "injection", CWE: "CWE-95"}
+	sql := core.Signature{ID: "PY004", Name: "SQL Injection risk", Severity: "medium", Category: "injection", CWE: "CWE-89"}
+	except := core.Signature{ID: "PY012", Name: "Bare except block", Severity: "low"}
+	data := core.ReportData{
+		Title: "Test Report",
+		Results: map[string][]core.Match{
+			"app.py": {
+				{Signature: eval, FilePath: "app.py", LineNumber: 1},
+				{Signature: sql, FilePath: "app.py", LineNumber: 2},
+				{Signature: except, FilePath: "app.py", LineNumber: 3},
+			},
+		},
+	}
+
+	assert.Equal(t, []categoryCount{
+		{Name: "injection", High: 1, Medium: 1, Total: 2},
+		{Name: "uncategorized", Low: 1, Total: 1},
+	}, categoryCounts(data.Results))
+
+	outputPath := filepath.Join(tmpdir, "report.html")
+	assert.NoError(t, NewHTMLReporter().GenerateReport(data, outputPath))
+
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Findings by Category")
+	assert.Contains(t, string(content), "<td>injection</td>")
+	assert.Contains(t, string(content), `<span class="cwe">CWE-95</span>`)
+}
+
+// 测试HTML报告列出问题最多的文件
+func TestHTMLReporterTopFiles(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "html-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	eval := core.Signature{ID: "PY001", Name: "Dangerous eval() usage", Severity: "high"}
+	results := map[string][]core.Match{
+		"app.py": {{Signature: eval, FilePath: "app.py", LineNumber: 1}},
+		"lib.py": {{Signature: eval, FilePath: "lib.py", LineNumber: 1}, {Signature: eval, FilePath: "lib.py", LineNumber: 2}},
+	}
+	data := core.ReportData{Title: "Test Report", Results: results, Summary: core.GenerateSummary(results)}
+
+	outputPath := filepath.Join(tmpdir, "report.html")
+	assert.NoError(t, NewHTMLReporter().GenerateReport(data, outputPath))
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Most Affected Files")
+	assert.Regexp(t, `(?s)<td>lib.py</td>\s*<td>2</td>.*<td>app.py</td>\s*<td>1</td>`, string(content))
+
+	// 没有问题时不显示
+	data.Summary = core.GenerateSummary(map[string][]core.Match{})
+	assert.NoError(t, NewHTMLReporter().GenerateReport(data, outputPath))
+	content, err = ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "Most Affected Files")
+}
+
+// 测试用自定义模板文件替换内置模板，模板无效时在生成报告前报错
+func TestHTMLReporterTemplate(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "html-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	data := core.ReportData{
+		Title: "Test Report",
+		Results: map[string][]core.Match{
+			"app.py": {
+				{Signature: core.Signature{ID: "PY001", Name: "Dangerous eval() usage", Severity: "high", Category: "injection"}, FilePath: "app.py", LineNumber: 2, Confidence: 0.9},
+			},
+		},
+		Summary: core.Summary{TotalFiles: 1, High: 1},
+	}
+
+	templatePath := filepath.Join(tmpdir, "report.html.tmpl")
+	assert.NoError(t, ioutil.WriteFile(templatePath, []byte(`<h1>ACME {{.Title}}</h1>
+{{range $file, $matches := .Results}}{{range $matches}}<p>{{$file}}:{{.LineNumber}} {{.Signature.ID}} {{mul .Confidence 100}}%</p>{{end}}{{end}}
+{{range .Categories}}<li>{{.Name}} {{.Total}}</li>{{end}}
+<footer>{{.Summary.High}} high, top: {{index .TopVulnerabilities.Labels 0}}</footer>`), 0644))
+
+	reporter := NewHTMLReporter()
+	assert.NoError(t, reporter.SetTemplate(templatePath))
+	outputPath := filepath.Join(tmpdir, "report.html")
+	assert.NoError(t, reporter.GenerateReport(data, outputPath))
+
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.Equal(t, `<h1>ACME Test Report</h1>
+<p>app.py:2 PY001 90%</p>
+<li>injection 1</li>
+<footer>1 high, top: Dangerous eval() usage</footer>`, string(content))
+
+	// 模板无效或不存在时保留之前的模板
+	assert.NoError(t, ioutil.WriteFile(templatePath, []byte("{{.Title"), 0644))
+	assert.Error(t, reporter.SetTemplate(templatePath))
+	assert.Error(t, reporter.SetTemplate(filepath.Join(tmpdir, "missing.tmpl")))
+	assert.NoError(t, reporter.GenerateReport(data, outputPath))
+	content, err = ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "ACME Test Report")
+}
@@ -32,6 +32,7 @@ func (r *JSONReporter) GenerateReport(data core.ReportData, outputPath string) e
 	defer file.Close()
 
 	// Marshal data to JSON
+	data.Results = sortedMatches(data.Results)
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(data); err != nil {
@@ -39,4 +40,15 @@ func (r *JSONReporter) GenerateReport(data core.ReportData, outputPath string) e
 	}
 
 	return nil
-} 
\ No newline at end of file
+} 
+
+// sortedMatches returns a copy of the results with the matches of each file in the
+// order of core.SortedResults. Maps are encoded and ranged over in key order, so
+// reports that include the results map are deterministic with it.
+func sortedMatches(results map[string][]core.Match) map[string][]core.Match {
+	sorted := make(map[string][]core.Match, len(results))
+	for _, fileResult := range core.SortedResults(results) {
+		sorted[fileResult.FilePath] = fileResult.Matches
+	}
+	return sorted
+}
@@ -0,0 +1,144 @@
+package reporters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// JUnitReporter is a reporter that generates JUnit XML reports. Each file becomes a
+// test suite and each match a test case; high and medium severity matches are failures.
+type JUnitReporter struct{}
+
+// NewJUnitReporter creates a new JUnit reporter
+func NewJUnitReporter() *JUnitReporter {
+	return &JUnitReporter{}
+}
+
+// JUnitTestSuites is the root element of a JUnit report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is the JUnit representation of a scanned file
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is the JUnit representation of a match
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure describes a failing test case
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+// GenerateReport generates a report
+func (r *JUnitReporter) GenerateReport(data core.ReportData, outputPath string) error {
+	// Create output directory if it doesn't exist
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	// Create output file
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Write XML header
+	if _, err := file.WriteString(xml.Header); err != nil {
+		return err
+	}
+
+	// Marshal data to XML
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(r.convertToJUnit(data)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// convertToJUnit converts the report data to JUnit format. The scan duration is not
+// tracked per file, so each suite is given an equal share of it.
+func (r *JUnitReporter) convertToJUnit(data core.ReportData) JUnitTestSuites {
+	suites := JUnitTestSuites{
+		Name:   data.Title,
+		Time:   formatSeconds(data.Duration),
+		Suites: []JUnitTestSuite{},
+	}
+
+	// Sort results so output is deterministic
+	fileResults := core.SortedResults(data.Results)
+
+	suiteTime := 0.0
+	if len(fileResults) > 0 {
+		suiteTime = data.Duration / float64(len(fileResults))
+	}
+
+	for _, fileResult := range fileResults {
+		filePath := fileResult.FilePath
+		suite := JUnitTestSuite{
+			Name:      filePath,
+			Time:      formatSeconds(suiteTime),
+			Timestamp: data.Timestamp,
+			Cases:     []JUnitTestCase{},
+		}
+
+		for _, match := range fileResult.Matches {
+			testCase := JUnitTestCase{
+				Name:      fmt.Sprintf("%s %s (line %d)", match.Signature.ID, match.Signature.Name, match.LineNumber),
+				ClassName: filePath,
+				Time:      formatSeconds(0),
+			}
+
+			severity := core.NormalizeSeverity(match.Signature.Severity)
+			if severity == "high" || severity == "medium" {
+				testCase.Failure = &JUnitFailure{
+					Message: match.Signature.Name,
+					Type:    severity,
+					Content: fmt.Sprintf("%s\n\n%s:%d\n%s", match.Signature.Description, filePath, match.LineNumber, match.MatchedCode),
+				}
+				suite.Failures++
+			}
+
+			suite.Cases = append(suite.Cases, testCase)
+			suite.Tests++
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	return suites
+}
+
+// formatSeconds formats a duration in seconds as used by JUnit time attributes
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,101 @@
+package reporters
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试生成JUnit XML报告
+func TestJUnitReporter(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "junit-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	data := core.ReportData{
+		Title:     "Test Report",
+		Timestamp: "2024-01-01T00:00:00Z",
+		Duration:  1.5,
+		Results: map[string][]core.Match{
+			"app.py": {
+				{
+					Signature: core.Signature{
+						ID:          "PY001",
+						Name:        "Dangerous eval() usage",
+						Severity:    "high",
+						Description: "eval() executes arbitrary code",
+					},
+					FilePath:    "app.py",
+					LineNumber:  4,
+					MatchedCode: "eval(user_input)",
+					Confidence:  0.9,
+				},
+				{
+					Signature: core.Signature{
+						ID:       "PY010",
+						Name:     "Debug mode enabled",
+						Severity: "low",
+					},
+					FilePath:    "app.py",
+					LineNumber:  9,
+					MatchedCode: "DEBUG = True",
+					Confidence:  0.8,
+				},
+			},
+			"util.js": {
+				{
+					Signature: core.Signature{
+						ID:       "JS002",
+						Name:     "Function constructor",
+						Severity: "medium",
+					},
+					FilePath:    "util.js",
+					LineNumber:  2,
+					MatchedCode: "new Function(code)",
+					Confidence:  0.8,
+				},
+			},
+		},
+	}
+
+	outputPath := filepath.Join(tmpdir, "report.xml")
+	assert.NoError(t, NewJUnitReporter().GenerateReport(data, outputPath))
+
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+
+	var report JUnitTestSuites
+	assert.NoError(t, xml.Unmarshal(content, &report))
+
+	assert.Equal(t, 3, report.Tests)
+	assert.Equal(t, 2, report.Failures)
+	assert.Equal(t, "1.500", report.Time)
+
+	// 每个文件一个测试套件，按路径排序
+	if assert.Len(t, report.Suites, 2) {
+		suite := report.Suites[0]
+		assert.Equal(t, "app.py", suite.Name)
+		assert.Equal(t, 2, suite.Tests)
+		assert.Equal(t, 1, suite.Failures)
+		assert.Equal(t, "0.750", suite.Time)
+
+		// 高危问题为失败，低危问题为通过
+		if assert.Len(t, suite.Cases, 2) {
+			if assert.NotNil(t, suite.Cases[0].Failure) {
+				assert.Equal(t, "high", suite.Cases[0].Failure.Type)
+				assert.Contains(t, suite.Cases[0].Failure.Content, "eval() executes arbitrary code")
+				assert.Contains(t, suite.Cases[0].Failure.Content, "eval(user_input)")
+			}
+			assert.Nil(t, suite.Cases[1].Failure)
+		}
+
+		assert.Equal(t, "util.js", report.Suites[1].Name)
+		assert.Equal(t, 1, report.Suites[1].Failures)
+	}
+}
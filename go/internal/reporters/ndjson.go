@@ -0,0 +1,82 @@
+package reporters
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// NDJSONReporter is a reporter that generates JSON lines reports: one compact JSON
+// object per match, followed by a summary object. Each object has a "type" field,
+// "match" or "summary", so the report can be filtered with jq or ingested as logs.
+type NDJSONReporter struct{}
+
+// NewNDJSONReporter creates a new NDJSON reporter
+func NewNDJSONReporter() *NDJSONReporter {
+	return &NDJSONReporter{}
+}
+
+// ndjsonMatch is the line written for a match
+type ndjsonMatch struct {
+	Type string `json:"type"`
+	core.Match
+}
+
+// ndjsonSummary is the trailing line written for the summary
+type ndjsonSummary struct {
+	Type      string  `json:"type"`
+	Title     string  `json:"title"`
+	Timestamp string  `json:"timestamp"`
+	Duration  float64 `json:"duration,omitempty"`
+	core.Summary
+}
+
+// GenerateReport generates a report
+func (r *NDJSONReporter) GenerateReport(data core.ReportData, outputPath string) error {
+	// Create output directory if it doesn't exist
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	// Create output file
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return r.Write(file, data)
+}
+
+// Write writes a report to w, encoding one match at a time
+func (r *NDJSONReporter) Write(w io.Writer, data core.ReportData) error {
+	writer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(writer)
+
+	// Sort results so output is deterministic
+	for _, fileResult := range core.SortedResults(data.Results) {
+		for _, match := range fileResult.Matches {
+			if err := encoder.Encode(ndjsonMatch{Type: "match", Match: match}); err != nil {
+				return err
+			}
+		}
+	}
+
+	summary := ndjsonSummary{
+		Type:      "summary",
+		Title:     data.Title,
+		Timestamp: data.Timestamp,
+		Duration:  data.Duration,
+		Summary:   data.Summary,
+	}
+	if err := encoder.Encode(summary); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,62 @@
+package reporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试生成JSON lines报告，每行一个匹配，最后一行为摘要
+func TestNDJSONReporter(t *testing.T) {
+	eval := core.Signature{ID: "PY001", Name: "Dangerous eval() usage", Severity: "high"}
+	data := core.ReportData{
+		Title:     "Test Report",
+		Timestamp: "2024-01-01T00:00:00Z",
+		Results: map[string][]core.Match{
+			"b.py": {{Signature: eval, FilePath: "b.py", LineNumber: 1, MatchedCode: "eval(x)", Confidence: 0.9}},
+			"a.py": {
+				{Signature: eval, FilePath: "a.py", LineNumber: 3, MatchedCode: "eval(y)", Confidence: 0.9},
+				{Signature: eval, FilePath: "a.py", LineNumber: 7, MatchedCode: "eval(z)", Confidence: 0.8},
+			},
+		},
+		Summary: core.Summary{TotalFiles: 2, High: 3, Vulnerabilities: map[string]int{"Dangerous eval() usage": 3}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, NewNDJSONReporter().Write(&buf, data))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if !assert.Len(t, lines, 4) {
+		return
+	}
+
+	// 匹配按文件路径排序，且为紧凑格式
+	for i, want := range []string{"a.py:3", "a.py:7", "b.py:1"} {
+		assert.NotContains(t, lines[i], "\n  ")
+		var match struct {
+			Type       string `json:"type"`
+			FilePath   string `json:"filePath"`
+			LineNumber int    `json:"lineNumber"`
+			Signature  struct {
+				ID string `json:"id"`
+			} `json:"signature"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(lines[i]), &match))
+		assert.Equal(t, "match", match.Type)
+		assert.Equal(t, want, fmt.Sprintf("%s:%d", match.FilePath, match.LineNumber))
+		assert.Equal(t, "PY001", match.Signature.ID)
+	}
+
+	var summary map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[3]), &summary))
+	assert.Equal(t, "summary", summary["type"])
+	assert.Equal(t, "Test Report", summary["title"])
+	assert.Equal(t, float64(3), summary["high"])
+	assert.Equal(t, float64(2), summary["totalFiles"])
+}
@@ -0,0 +1,66 @@
+package reporters
+
+import (
+	"fmt"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// ReportOptions are the options shared by all reporters
+type ReportOptions struct {
+	// MinSeverity drops matches below this severity (high, medium or low); empty keeps all matches
+	MinSeverity string
+}
+
+// Validate returns an error if the options are invalid
+func (o ReportOptions) Validate() error {
+	if o.MinSeverity != "" && core.SeverityRank(o.MinSeverity) == 0 {
+		return fmt.Errorf("invalid minimum severity: %s (expected high, medium or low)", o.MinSeverity)
+	}
+	return nil
+}
+
+// Apply returns the report data with the options applied. Files left without
+// matches are dropped and the summary is recomputed from the remaining matches; the
+// number of files scanned is kept.
+func (o ReportOptions) Apply(data core.ReportData) core.ReportData {
+	if o.MinSeverity == "" {
+		return data
+	}
+
+	minRank := core.SeverityRank(o.MinSeverity)
+	results := make(map[string][]core.Match)
+	for filePath, matches := range data.Results {
+		for _, match := range matches {
+			if core.SeverityRank(match.Signature.Severity) >= minRank {
+				results[filePath] = append(results[filePath], match)
+			}
+		}
+	}
+
+	summary := core.GenerateSummary(results)
+	summary.TotalFiles = data.Summary.TotalFiles
+	data.Results = results
+	data.Summary = summary
+	return data
+}
+
+// optionsReporter applies report options before delegating to another reporter
+type optionsReporter struct {
+	reporter core.Reporter
+	options  ReportOptions
+}
+
+// WithOptions returns a reporter that applies the options to the report data
+// before passing it to reporter
+func WithOptions(reporter core.Reporter, options ReportOptions) core.Reporter {
+	return &optionsReporter{
+		reporter: reporter,
+		options:  options,
+	}
+}
+
+// GenerateReport generates a report from the filtered data
+func (r *optionsReporter) GenerateReport(data core.ReportData, outputPath string) error {
+	return r.reporter.GenerateReport(r.options.Apply(data), outputPath)
+}
@@ -0,0 +1,62 @@
+package reporters
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试按最低严重程度过滤报告中的问题
+func TestReportOptionsMinSeverity(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "options-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	data := core.ReportData{
+		Title: "Test Report",
+		Results: map[string][]core.Match{
+			"app.py": {
+				{Signature: core.Signature{ID: "PY001", Name: "Dangerous eval() usage", Severity: "high"}, LineNumber: 1},
+				{Signature: core.Signature{ID: "PY010", Name: "Bare except block", Severity: "low"}, LineNumber: 2},
+			},
+			"util.py": {
+				{Signature: core.Signature{ID: "PY010", Name: "Bare except block", Severity: "low"}, LineNumber: 3},
+			},
+		},
+	}
+	data.Summary = core.GenerateSummary(data.Results)
+
+	// 未设置时保留所有问题
+	assert.Equal(t, data, ReportOptions{}.Apply(data))
+
+	outputPath := filepath.Join(tmpdir, "report.json")
+	reporter := WithOptions(NewJSONReporter(), ReportOptions{MinSeverity: "medium"})
+	assert.NoError(t, reporter.GenerateReport(data, outputPath))
+
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	var report core.ReportData
+	assert.NoError(t, json.Unmarshal(content, &report))
+
+	// 过滤后没有问题的文件被移除，摘要重新计算，但扫描的文件数不变
+	assert.Len(t, report.Results, 1)
+	if assert.Len(t, report.Results["app.py"], 1) {
+		assert.Equal(t, "PY001", report.Results["app.py"][0].Signature.ID)
+	}
+	assert.Equal(t, 2, report.Summary.TotalFiles)
+	assert.Equal(t, 1, report.Summary.High)
+	assert.Equal(t, 0, report.Summary.Low)
+
+	// 原始数据不受影响
+	assert.Len(t, data.Results["app.py"], 2)
+
+	assert.NoError(t, ReportOptions{MinSeverity: "LOW"}.Validate())
+	assert.NoError(t, ReportOptions{MinSeverity: "critical"}.Validate())
+	assert.Error(t, ReportOptions{MinSeverity: "urgent"}.Validate())
+}
@@ -0,0 +1,66 @@
+package reporters
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// TemplateReporter is a reporter that renders a text/template once per match,
+// writing one line per match, e.g. "{{.FilePath}}:{{.LineNumber}} {{.Signature.ID}}"
+type TemplateReporter struct {
+	template *template.Template
+}
+
+// NewTemplateReporter creates a new template reporter, returning an error if the template does not parse
+func NewTemplateReporter(text string) (*TemplateReporter, error) {
+	tmpl, err := template.New("match").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateReporter{
+		template: tmpl,
+	}, nil
+}
+
+// GenerateReport generates a report
+func (r *TemplateReporter) GenerateReport(data core.ReportData, outputPath string) error {
+	// Create output directory if it doesn't exist
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	// Create output file
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return r.Render(file, data.Results)
+}
+
+// Render writes one rendered line per match, ordered by file path and line number
+func (r *TemplateReporter) Render(w io.Writer, results map[string][]core.Match) error {
+	for _, fileResult := range core.SortedResults(results) {
+		for _, match := range fileResult.Matches {
+			var line strings.Builder
+			if err := r.template.Execute(&line, match); err != nil {
+				return err
+			}
+
+			// Each match is exactly one line of output
+			if _, err := io.WriteString(w, strings.TrimRight(line.String(), "\n")+"\n"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package reporters
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试按模板逐条输出匹配结果
+func TestTemplateReporter(t *testing.T) {
+	results := map[string][]core.Match{
+		"web/app.js": {
+			{
+				Signature:  core.Signature{ID: "JS001", Severity: "high"},
+				FilePath:   "web/app.js",
+				LineNumber: 12,
+			},
+		},
+		"app.py": {
+			{
+				Signature:  core.Signature{ID: "PY010", Severity: "low"},
+				FilePath:   "app.py",
+				LineNumber: 9,
+			},
+			{
+				Signature:  core.Signature{ID: "PY001", Severity: "high"},
+				FilePath:   "app.py",
+				LineNumber: 4,
+			},
+		},
+		"clean.py": {},
+	}
+
+	reporter, err := NewTemplateReporter("{{.FilePath}}:{{.LineNumber}} [{{.Signature.Severity}}] {{.Signature.ID}}")
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+	assert.NoError(t, reporter.Render(&output, results))
+	assert.Equal(t, "app.py:4 [high] PY001\napp.py:9 [low] PY010\nweb/app.js:12 [high] JS001\n", output.String())
+
+	// 无效的模板在扫描前报错
+	_, err = NewTemplateReporter("{{.FilePath")
+	assert.Error(t, err)
+
+	// 不存在的字段在渲染时报错
+	reporter, err = NewTemplateReporter("{{.Missing}}")
+	assert.NoError(t, err)
+	assert.Error(t, reporter.Render(&output, results))
+}
@@ -95,14 +95,14 @@ func (r *XMLReporter) convertToXML(data core.ReportData) XMLReportData {
 		Results: []XMLFileResult{},
 	}
 
-	// Convert results
-	for filePath, matches := range data.Results {
+	// Convert results, sorted so output is deterministic
+	for _, sorted := range core.SortedResults(data.Results) {
 		fileResult := XMLFileResult{
-			Path:    filePath,
+			Path:    sorted.FilePath,
 			Matches: []XMLMatch{},
 		}
 
-		for _, match := range matches {
+		for _, match := range sorted.Matches {
 			xmlMatch := XMLMatch{
 				ID:          match.Signature.ID,
 				Name:        match.Signature.Name,
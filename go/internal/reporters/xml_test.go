@@ -0,0 +1,56 @@
+package reporters
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/core"
+)
+
+// 测试XML报告按文件路径和行号排序
+func TestXMLReporterOrder(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "xml-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	eval := core.Signature{ID: "PY001", Name: "Dangerous eval() usage", Severity: "high"}
+	data := core.ReportData{
+		Title: "Test Report",
+		Results: map[string][]core.Match{
+			"src/b.py": {{Signature: eval, LineNumber: 9}, {Signature: eval, LineNumber: 2}},
+			"src/a.py": {{Signature: eval, LineNumber: 5}},
+			"lib/c.py": {{Signature: eval, LineNumber: 1}},
+		},
+	}
+
+	outputPath := filepath.Join(tmpdir, "report.xml")
+	assert.NoError(t, NewXMLReporter().GenerateReport(data, outputPath))
+
+	content, err := ioutil.ReadFile(outputPath)
+	assert.NoError(t, err)
+	var report XMLReportData
+	assert.NoError(t, xml.Unmarshal(content, &report))
+
+	if assert.Len(t, report.Results, 3) {
+		assert.Equal(t, "lib/c.py", report.Results[0].Path)
+		assert.Equal(t, "src/a.py", report.Results[1].Path)
+		assert.Equal(t, "src/b.py", report.Results[2].Path)
+		if assert.Len(t, report.Results[2].Matches, 2) {
+			assert.Equal(t, 2, report.Results[2].Matches[0].LineNumber)
+			assert.Equal(t, 9, report.Results[2].Matches[1].LineNumber)
+		}
+	}
+
+	// 多次生成的报告完全相同
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, NewXMLReporter().GenerateReport(data, outputPath))
+		again, err := ioutil.ReadFile(outputPath)
+		assert.NoError(t, err)
+		assert.Equal(t, string(content), string(again))
+	}
+}
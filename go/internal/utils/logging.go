@@ -34,7 +34,7 @@ type FileLogger struct {
 
 // NewFileLogger creates a new file logger
 func NewFileLogger(filename string) (*FileLogger, error) {
-    file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+    file, err := OpenLogFile(filename)
     if err != nil {
         return nil, err
     }
@@ -49,6 +49,18 @@ func NewFileLogger(filename string) (*FileLogger, error) {
     }, nil
 }
 
+// OpenLogFile opens a log file for appending, creating it if it does not exist
+func OpenLogFile(filename string) (*os.File, error) {
+    return os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+}
+
+// SetJSONOutput makes the singleton logger write JSON entries to w, in the same
+// format as file loggers. Unlike file loggers, nothing is copied to stdout.
+func SetJSONOutput(w io.Writer) {
+    GetLogger().SetFormatter(&logrus.JSONFormatter{})
+    GetLogger().SetOutput(w)
+}
+
 // Close closes the log file
 func (fl *FileLogger) Close() error {
     if fl.file != nil {
@@ -5,15 +5,16 @@ import (
     "runtime"
     "sync"
     "time"
-
-    "github.com/shirou/gopsutil/v3/mem"
 )
 
-// MemoryMonitor monitors system memory usage
+// MemoryMonitor monitors the memory usage of this process
 type MemoryMonitor struct {
     maxMemoryGB float64
     interval    time.Duration
     stopChan    chan struct{}
+    limitTicks  int
+    limitFunc   func(usedGB float64)
+    usage       func() (float64, error)
 }
 
 // NewMemoryMonitor creates a new memory monitor
@@ -22,7 +23,19 @@ func NewMemoryMonitor(maxMemoryGB float64, interval time.Duration) *MemoryMonito
         maxMemoryGB: maxMemoryGB,
         interval:    interval,
         stopChan:    make(chan struct{}),
+        usage:       processMemoryGB,
+    }
+}
+
+// SetLimitFunc sets a function that is called, once, when memory usage has exceeded
+// the limit for ticks consecutive checks, e.g. to pause or abort the work using the
+// memory. It must be set before Start and is called from the monitor's goroutine.
+func (mm *MemoryMonitor) SetLimitFunc(ticks int, fn func(usedGB float64)) {
+    if ticks < 1 {
+        ticks = 1
     }
+    mm.limitTicks = ticks
+    mm.limitFunc = fn
 }
 
 // Start starts monitoring memory usage
@@ -31,19 +44,29 @@ func (mm *MemoryMonitor) Start() {
         ticker := time.NewTicker(mm.interval)
         defer ticker.Stop()
 
+        exceeded := 0
+        fired := false
         for {
             select {
             case <-ticker.C:
-                v, err := mem.VirtualMemory()
+                usedGB, err := mm.usage()
                 if err != nil {
                     GetLogger().Errorf("Failed to get memory stats: %v", err)
                     continue
                 }
 
-                usedGB := float64(v.Used) / (1024 * 1024 * 1024)
-                if usedGB > mm.maxMemoryGB {
-                    GetLogger().Warnf("Memory usage (%.2f GB) exceeds limit (%.2f GB), triggering GC", usedGB, mm.maxMemoryGB)
-                    runtime.GC()
+                if usedGB <= mm.maxMemoryGB {
+                    exceeded = 0
+                    continue
+                }
+
+                GetLogger().Warnf("Memory usage (%.2f GB) exceeds limit (%.2f GB), triggering GC", usedGB, mm.maxMemoryGB)
+                runtime.GC()
+
+                exceeded++
+                if mm.limitFunc != nil && !fired && exceeded >= mm.limitTicks {
+                    fired = true
+                    mm.limitFunc(usedGB)
                 }
             case <-mm.stopChan:
                 return
@@ -57,17 +80,34 @@ func (mm *MemoryMonitor) Stop() {
     close(mm.stopChan)
 }
 
-// LRUCache implements a thread-safe LRU cache
+// processMemoryGB returns the memory this process holds from the system in GB, not
+// counting heap memory it has released back to the system. Memory used by other
+// processes does not count, so that a busy host does not trip the limit.
+func processMemoryGB() (float64, error) {
+    var m runtime.MemStats
+    runtime.ReadMemStats(&m)
+    return float64(m.Sys-m.HeapReleased) / (1024 * 1024 * 1024), nil
+}
+
+// LRUCache implements a thread-safe LRU cache. Entries are evicted, least recently
+// used first, when the cache holds more than capacity items or, for caches created
+// with NewSizedLRUCache, more than maxBytes bytes.
 type LRUCache struct {
-    capacity int
-    cache    map[interface{}]*list.Element
-    ll       *list.List
-    mutex    sync.RWMutex
+    capacity  int
+    maxBytes  int64
+    sizeOf    func(value interface{}) int64
+    usedBytes int64
+    ttl       time.Duration
+    cache     map[interface{}]*list.Element
+    ll        *list.List
+    mutex     sync.Mutex
 }
 
 type entry struct {
-    key   interface{}
-    value interface{}
+    key     interface{}
+    value   interface{}
+    size    int64
+    expires time.Time
 }
 
 // NewLRUCache creates a new LRU cache with the specified capacity
@@ -79,16 +119,44 @@ func NewLRUCache(capacity int) *LRUCache {
     }
 }
 
+// NewSizedLRUCache creates a new LRU cache that holds at most maxBytes bytes of values,
+// as estimated by sizeOf, regardless of their number
+func NewSizedLRUCache(maxBytes int64, sizeOf func(value interface{}) int64) *LRUCache {
+    return &LRUCache{
+        maxBytes: maxBytes,
+        sizeOf:   sizeOf,
+        cache:    make(map[interface{}]*list.Element),
+        ll:       list.New(),
+    }
+}
+
+// SetTTL sets how long entries stay in the cache after they are put. Expired entries
+// are treated as missing. A TTL of 0, the default, keeps entries until they are evicted.
+func (c *LRUCache) SetTTL(ttl time.Duration) {
+    c.mutex.Lock()
+    defer c.mutex.Unlock()
+
+    c.ttl = ttl
+}
+
 // Get retrieves a value from the cache
 func (c *LRUCache) Get(key interface{}) (interface{}, bool) {
-    c.mutex.RLock()
-    defer c.mutex.RUnlock()
+    c.mutex.Lock()
+    defer c.mutex.Unlock()
 
-    if elem, ok := c.cache[key]; ok {
-        c.ll.MoveToFront(elem)
-        return elem.Value.(*entry).value, true
+    elem, ok := c.cache[key]
+    if !ok {
+        return nil, false
+    }
+
+    e := elem.Value.(*entry)
+    if !e.expires.IsZero() && time.Now().After(e.expires) {
+        c.removeElement(elem)
+        return nil, false
     }
-    return nil, false
+
+    c.ll.MoveToFront(elem)
+    return e.value, true
 }
 
 // Put adds a value to the cache
@@ -96,20 +164,60 @@ func (c *LRUCache) Put(key, value interface{}) {
     c.mutex.Lock()
     defer c.mutex.Unlock()
 
+    var size int64
+    if c.sizeOf != nil {
+        size = c.sizeOf(value)
+    }
+    var expires time.Time
+    if c.ttl > 0 {
+        expires = time.Now().Add(c.ttl)
+    }
+
     if elem, ok := c.cache[key]; ok {
         c.ll.MoveToFront(elem)
-        elem.Value.(*entry).value = value
-        return
+        e := elem.Value.(*entry)
+        c.usedBytes += size - e.size
+        e.value, e.size, e.expires = value, size, expires
+    } else {
+        elem := c.ll.PushFront(&entry{key, value, size, expires})
+        c.cache[key] = elem
+        c.usedBytes += size
     }
 
-    if c.ll.Len() >= c.capacity {
-        oldest := c.ll.Back()
-        if oldest != nil {
-            c.ll.Remove(oldest)
-            delete(c.cache, oldest.Value.(*entry).key)
-        }
+    // Evict the least recently used entries, keeping at least the new one
+    for c.ll.Len() > 1 && c.overBudget() {
+        c.removeElement(c.ll.Back())
     }
+}
+
+// Len returns the number of entries in the cache, including expired entries not yet removed
+func (c *LRUCache) Len() int {
+    c.mutex.Lock()
+    defer c.mutex.Unlock()
 
-    elem := c.ll.PushFront(&entry{key, value})
-    c.cache[key] = elem
-} 
\ No newline at end of file
+    return c.ll.Len()
+}
+
+// Bytes returns the estimated size of the values in the cache
+func (c *LRUCache) Bytes() int64 {
+    c.mutex.Lock()
+    defer c.mutex.Unlock()
+
+    return c.usedBytes
+}
+
+// overBudget reports whether the cache holds more items or bytes than it may
+func (c *LRUCache) overBudget() bool {
+    if c.capacity > 0 && c.ll.Len() > c.capacity {
+        return true
+    }
+    return c.maxBytes > 0 && c.usedBytes > c.maxBytes
+}
+
+// removeElement removes an entry from the cache
+func (c *LRUCache) removeElement(elem *list.Element) {
+    e := elem.Value.(*entry)
+    c.ll.Remove(elem)
+    delete(c.cache, e.key)
+    c.usedBytes -= e.size
+}
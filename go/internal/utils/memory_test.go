@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试按条目数量淘汰最久未使用的条目
+func TestLRUCacheCapacity(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	// 访问a后，b成为最久未使用的条目
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	cache.Put("c", 3)
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 2, cache.Len())
+}
+
+// 测试过期条目视为未命中并被移除
+func TestLRUCacheTTL(t *testing.T) {
+	cache := NewLRUCache(10)
+	cache.SetTTL(50 * time.Millisecond)
+	cache.Put("a", 1)
+
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	time.Sleep(100 * time.Millisecond)
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+
+	// 重新写入会刷新过期时间
+	cache.Put("a", 2)
+	value, ok = cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+}
+
+// 测试按字节预算淘汰条目
+func TestLRUCacheByteBudget(t *testing.T) {
+	cache := NewSizedLRUCache(10, func(value interface{}) int64 {
+		return int64(len(value.(string)))
+	})
+	cache.Put("a", "1234")
+	cache.Put("b", "5678")
+	assert.Equal(t, int64(8), cache.Bytes())
+
+	// 超出预算时淘汰最久未使用的条目
+	cache.Put("c", "90")
+	assert.Equal(t, int64(10), cache.Bytes())
+	cache.Put("d", "x")
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, int64(7), cache.Bytes())
+
+	// 更新条目时重新计算大小
+	cache.Put("b", "123456789")
+	assert.Equal(t, int64(10), cache.Bytes())
+	_, ok = cache.Get("c")
+	assert.False(t, ok)
+	_, ok = cache.Get("d")
+	assert.True(t, ok)
+
+	// 单个超出预算的条目仍会保留
+	cache.Put("e", "0123456789ab")
+	assert.Equal(t, 1, cache.Len())
+	value, ok := cache.Get("e")
+	assert.True(t, ok)
+	assert.Equal(t, "0123456789ab", value)
+}
+
+// 测试内存使用超过下限时触发回调
+func TestMemoryMonitorLimit(t *testing.T) {
+	monitor := NewMemoryMonitor(0.000001, 10*time.Millisecond)
+	fired := make(chan float64, 1)
+	monitor.SetLimitFunc(2, func(usedGB float64) {
+		fired <- usedGB
+	})
+	monitor.Start()
+	defer monitor.Stop()
+
+	select {
+	case usedGB := <-fired:
+		assert.Greater(t, usedGB, 0.000001)
+	case <-time.After(5 * time.Second):
+		t.Fatal("limit function was not called")
+	}
+}
+
+// 测试内存使用只计算本进程，而不是整个系统
+func TestProcessMemoryGB(t *testing.T) {
+	usedGB, err := processMemoryGB()
+	assert.NoError(t, err)
+	assert.Greater(t, usedGB, 0.0)
+	// 测试进程本身远小于1GB，而整个系统的内存使用通常超过1GB
+	assert.Less(t, usedGB, 1.0)
+}
+
+// 测试只有连续超限时才触发回调
+func TestMemoryMonitorConsecutiveTicks(t *testing.T) {
+	monitor := NewMemoryMonitor(1, 10*time.Millisecond)
+	readings := make(chan float64, 10)
+	for _, usedGB := range []float64{2, 0.5, 2, 2, 0.5, 2, 2, 2, 2, 2} {
+		readings <- usedGB
+	}
+	monitor.usage = func() (float64, error) {
+		select {
+		case usedGB := <-readings:
+			return usedGB, nil
+		default:
+			return 0.5, nil
+		}
+	}
+	calls := make(chan float64, 10)
+	monitor.SetLimitFunc(3, func(usedGB float64) {
+		calls <- usedGB
+	})
+	monitor.Start()
+
+	time.Sleep(300 * time.Millisecond)
+	monitor.Stop()
+
+	// 只在第三次连续超限时触发一次
+	assert.Len(t, calls, 1)
+}
@@ -59,6 +59,16 @@ func (wp *WorkerPool) Submit(job Job) {
     wp.jobs <- job
 }
 
+// TrySubmit submits a job without blocking, reporting false if the queue is full
+func (wp *WorkerPool) TrySubmit(job Job) bool {
+    select {
+    case wp.jobs <- job:
+        return true
+    default:
+        return false
+    }
+}
+
 // Stop stops the worker pool
 func (wp *WorkerPool) Stop() {
     close(wp.stopChan)
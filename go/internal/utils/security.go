@@ -3,17 +3,28 @@ package utils
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/parser"
 	"go/token"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"regexp"
-	"runtime"
+	"strconv"
 	"strings"
 	"sync"
-	"time"
 )
 
+// largeAllocationThreshold 单次make分配的元素数量上限
+const largeAllocationThreshold = 1 << 20
+
+// SecurityCheckResult 单项安全检查的结果
+type SecurityCheckResult struct {
+	HasIssues bool     `json:"has_issues"`
+	Details   string   `json:"details"`
+	Patterns  []string `json:"patterns"`
+}
+
 // SecurityChecker 安全检查器
 type SecurityChecker struct {
 	sensitivePatterns map[string][]string
@@ -27,22 +38,36 @@ func NewSecurityChecker() *SecurityChecker {
 			"file_access": {
 				`os\.(Open|Create|Remove|RemoveAll|Chmod|Chown)`,
 				`ioutil\.(ReadFile|WriteFile)`,
+				`(^|[^\w.])open\(`,
+				`os\.(remove|unlink|chmod|chown)\(`,
+				`shutil\.rmtree`,
+				`fs\.(readFile|writeFile|unlink|rm|chmod)(Sync)?\(`,
 			},
 			"network_access": {
 				`net\.(Dial|Listen)`,
 				`http\.(Get|Post|Put|Delete)`,
+				`requests\.(get|post|put|delete)`,
+				`socket\.socket`,
 			},
 			"code_execution": {
 				`exec\.(Command|Run)`,
 				`syscall\.(Exec|StartProcess)`,
+				`subprocess\.`,
+				`os\.(system|popen)`,
+				`child_process`,
 			},
 			"input_validation": {
 				`fmt\.(Scan|Scanf|Scanln)`,
 				`bufio\.NewScanner`,
+				`(^|[^\w.])input\(`,
+				`sys\.stdin`,
+				`process\.argv`,
 			},
 			"random_generation": {
-				`math/rand\.(Int|Float|Perm)`,
+				`math/rand\.(Int|Float|Perm|Seed|Read|Shuffle)`,
 				`crypto/rand\.(Read|Prime)`,
+				`random\.(random|randint|choice|shuffle)`,
+				`Math\.random`,
 			},
 			"sensitive_data": {
 				`(?i)(password|secret|key|token|credential)`,
@@ -52,303 +77,435 @@ func NewSecurityChecker() *SecurityChecker {
 	}
 }
 
-// CheckMemoryUsage 检查内存使用情况
-func (c *SecurityChecker) CheckMemoryUsage(filePath string) (uint64, error) {
-	var m runtime.MemStats
-	runtime.ReadMemStats(&m)
-	initialAlloc := m.Alloc
-
-	// 读取并执行文件
-	content, err := ioutil.ReadFile(filePath)
+// CheckMemoryUsage 检查大内存分配
+func (c *SecurityChecker) CheckMemoryUsage(filePath string) SecurityCheckResult {
+	src, err := loadSource(filePath)
 	if err != nil {
-		return 0, fmt.Errorf("读取文件失败: %v", err)
+		return errorResult(err)
 	}
-
-	// 解析文件以检查内存使用
-	fset := token.NewFileSet()
-	_, err = parser.ParseFile(fset, filePath, content, parser.AllErrors)
-	if err != nil {
-		return 0, fmt.Errorf("解析文件失败: %v", err)
+	if !src.isGo() {
+		// 其他语言没有可检查的语法树
+		return (&checkFindings{}).result()
 	}
 
-	runtime.ReadMemStats(&m)
-	finalAlloc := m.Alloc
+	findings := &checkFindings{}
+	ast.Inspect(src.file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := call.Fun.(*ast.Ident); !ok || ident.Name != "make" || len(call.Args) < 2 {
+			return true
+		}
+
+		// make的长度和容量参数
+		for _, arg := range call.Args[1:] {
+			size, ok := constValue(arg)
+			if ok && constant.Compare(size, token.GEQ, constant.MakeInt64(largeAllocationThreshold)) {
+				findings.add("make", fmt.Sprintf("第%d行 大内存分配: %s", src.lineNumber(call.Pos()), src.line(call.Pos())))
+				break
+			}
+		}
+		return true
+	})
 
-	return finalAlloc - initialAlloc, nil
+	return findings.result()
 }
 
-// CheckExecutionTime 检查执行时间
-func (c *SecurityChecker) CheckExecutionTime(filePath string, timeout time.Duration) error {
-	done := make(chan bool)
-	var execErr error
+// CheckExecutionTime 检查可能导致长时间执行的代码
+func (c *SecurityChecker) CheckExecutionTime(filePath string) SecurityCheckResult {
+	src, err := loadSource(filePath)
+	if err != nil {
+		return errorResult(err)
+	}
+	if !src.isGo() {
+		// 其他语言没有可检查的语法树
+		return (&checkFindings{}).result()
+	}
 
-	go func() {
-		// 读取并解析文件
-		content, err := ioutil.ReadFile(filePath)
-		if err != nil {
-			execErr = fmt.Errorf("读取文件失败: %v", err)
-			done <- true
-			return
+	findings := &checkFindings{}
+	ast.Inspect(src.file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if funcName := selectorName(node); funcName == "time.Sleep" {
+				findings.add(funcName, fmt.Sprintf("第%d行 长时间等待: %s", src.lineNumber(node.Pos()), src.line(node.Pos())))
+			}
+		case *ast.ForStmt:
+			if node.Cond == nil {
+				findings.add("for {}", fmt.Sprintf("第%d行 无限循环: %s", src.lineNumber(node.Pos()), src.line(node.Pos())))
+			}
 		}
+		return true
+	})
 
-		fset := token.NewFileSet()
-		_, err = parser.ParseFile(fset, filePath, content, parser.AllErrors)
-		if err != nil {
-			execErr = fmt.Errorf("解析文件失败: %v", err)
-			done <- true
-			return
-		}
+	return findings.result()
+}
+
+// CheckFileAccess 检查文件访问安全性
+func (c *SecurityChecker) CheckFileAccess(filePath string) SecurityCheckResult {
+	src, err := loadSource(filePath)
+	if err != nil {
+		return errorResult(err)
+	}
 
-		done <- true
-	}()
+	return c.matchLines(src, "file_access", "发现敏感文件操作")
+}
 
-	select {
-	case <-done:
-		return execErr
-	case <-time.After(timeout):
-		return fmt.Errorf("执行超时(>%v)", timeout)
+// CheckNetworkAccess 检查网络访问安全性
+func (c *SecurityChecker) CheckNetworkAccess(filePath string) SecurityCheckResult {
+	src, err := loadSource(filePath)
+	if err != nil {
+		return errorResult(err)
 	}
+
+	return c.matchLines(src, "network_access", "发现敏感网络操作")
 }
 
-// CheckFileAccess 检查文件访问安全性
-func (c *SecurityChecker) CheckFileAccess(filePath string) ([]string, error) {
-	violations := make([]string, 0)
-	content, err := ioutil.ReadFile(filePath)
+// CheckInputValidation 检查输入验证
+func (c *SecurityChecker) CheckInputValidation(filePath string) SecurityCheckResult {
+	src, err := loadSource(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
+		return errorResult(err)
+	}
+	if !src.isGo() {
+		// 其他语言只按行匹配读取输入的代码
+		return c.matchLines(src, "input_validation", "未验证的输入")
 	}
 
-	c.mu.RLock()
-	patterns := c.sensitivePatterns["file_access"]
-	c.mu.RUnlock()
+	inputPatterns := c.compilePatterns("input_validation")
+	execPatterns := c.compilePatterns("code_execution")
 
-	for _, pattern := range patterns {
-		re, err := regexp.Compile(pattern)
-		if err != nil {
-			continue
+	// 记录读取输入的调用以及接收输入的变量
+	findings := &checkFindings{}
+	tainted := make(map[string]bool)
+	ast.Inspect(src.file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		funcName := selectorName(call)
+		if re := matchAny(inputPatterns, funcName); re != nil {
+			findings.add(re.String(), fmt.Sprintf("第%d行 未验证的输入: %s", src.lineNumber(call.Pos()), funcName))
+			for _, arg := range call.Args {
+				if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+					if ident, ok := unary.X.(*ast.Ident); ok {
+						tainted[ident.Name] = true
+					}
+				}
+			}
 		}
+		return true
+	})
 
-		matches := re.FindAllString(string(content), -1)
-		for _, match := range matches {
-			violations = append(violations, fmt.Sprintf("发现敏感文件操作: %s", match))
+	// 未经验证的输入被直接用于执行命令
+	ast.Inspect(src.file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
 		}
-	}
+		funcName := selectorName(call)
+		if re := matchAny(execPatterns, funcName); re != nil && usesAny(call.Args, tainted) {
+			findings.add(re.String(), fmt.Sprintf("第%d行 未验证的输入被传入%s: %s", src.lineNumber(call.Pos()), funcName, src.line(call.Pos())))
+		}
+		return true
+	})
 
-	return violations, nil
+	return findings.result()
 }
 
-// CheckNetworkAccess 检查网络访问安全性
-func (c *SecurityChecker) CheckNetworkAccess(filePath string) ([]string, error) {
-	violations := make([]string, 0)
-	content, err := ioutil.ReadFile(filePath)
+// CheckRandomGeneration 检查随机数生成安全性
+func (c *SecurityChecker) CheckRandomGeneration(filePath string) SecurityCheckResult {
+	src, err := loadSource(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
+		return errorResult(err)
+	}
+	if !src.isGo() {
+		// 其他语言只按行匹配
+		return c.matchLines(src, "random_generation", "不安全的随机数生成")
 	}
 
-	c.mu.RLock()
-	patterns := c.sensitivePatterns["network_access"]
-	c.mu.RUnlock()
+	patterns := c.compilePatterns("random_generation")
+	imports := importPaths(src.file)
 
-	for _, pattern := range patterns {
-		re, err := regexp.Compile(pattern)
-		if err != nil {
-			continue
+	findings := &checkFindings{}
+	ast.Inspect(src.file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		x, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
 		}
 
-		matches := re.FindAllString(string(content), -1)
-		for _, match := range matches {
-			violations = append(violations, fmt.Sprintf("发现敏感网络操作: %s", match))
+		// 按导入路径匹配，同名的crypto/rand是安全的
+		for _, path := range imports[x.Name] {
+			funcName := path + "." + sel.Sel.Name
+			if strings.Contains(funcName, "crypto/rand") {
+				continue
+			}
+			if re := matchAny(patterns, funcName); re != nil {
+				findings.add(re.String(), fmt.Sprintf("第%d行 不安全的随机数生成: %s", src.lineNumber(call.Pos()), funcName))
+			}
 		}
-	}
+		return true
+	})
 
-	return violations, nil
+	return findings.result()
 }
 
-// CheckInputValidation 检查输入验证
-func (c *SecurityChecker) CheckInputValidation(filePath string) ([]string, error) {
-	issues := make([]string, 0)
-	content, err := ioutil.ReadFile(filePath)
+// CheckSensitiveData 检查敏感数据处理
+func (c *SecurityChecker) CheckSensitiveData(filePath string) SecurityCheckResult {
+	src, err := loadSource(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
+		return errorResult(err)
 	}
 
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, filePath, content, parser.AllErrors)
+	return c.matchLines(src, "sensitive_data", "敏感数据泄露风险")
+}
+
+// CheckSandboxEscape 检查沙箱逃逸
+func (c *SecurityChecker) CheckSandboxEscape(filePath string) SecurityCheckResult {
+	src, err := loadSource(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("解析文件失败: %v", err)
+		return errorResult(err)
+	}
+	if !src.isGo() {
+		// 其他语言只按行匹配执行命令的代码
+		return c.matchLines(src, "code_execution", "危险的系统调用")
 	}
 
-	ast.Inspect(file, func(n ast.Node) bool {
+	findings := &checkFindings{}
+	ast.Inspect(src.file, func(n ast.Node) bool {
 		if call, ok := n.(*ast.CallExpr); ok {
-			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
-				if x, ok := sel.X.(*ast.Ident); ok {
-					funcName := x.Name + "." + sel.Sel.Name
-					if strings.Contains(funcName, "fmt.Scan") || strings.Contains(funcName, "bufio.NewScanner") {
-						issues = append(issues, fmt.Sprintf("未验证的输入: %s", funcName))
-					}
-				}
+			funcName := selectorName(call)
+			if strings.HasPrefix(funcName, "os.") || strings.HasPrefix(funcName, "exec.") {
+				findings.add(funcName, fmt.Sprintf("第%d行 危险的系统调用: %s", src.lineNumber(call.Pos()), funcName))
 			}
 		}
 		return true
 	})
 
-	return issues, nil
+	return findings.result()
 }
 
-// CheckRandomGeneration 检查随机数生成安全性
-func (c *SecurityChecker) CheckRandomGeneration(filePath string) ([]string, error) {
-	issues := make([]string, 0)
-	content, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
+// PerformFullCheck 执行完整的安全检查
+func (c *SecurityChecker) PerformFullCheck(filePath string) map[string]SecurityCheckResult {
+	return map[string]SecurityCheckResult{
+		"memory_usage":      c.CheckMemoryUsage(filePath),
+		"execution_time":    c.CheckExecutionTime(filePath),
+		"file_access":       c.CheckFileAccess(filePath),
+		"network_access":    c.CheckNetworkAccess(filePath),
+		"input_validation":  c.CheckInputValidation(filePath),
+		"random_generation": c.CheckRandomGeneration(filePath),
+		"sensitive_data":    c.CheckSensitiveData(filePath),
+		"sandbox_escape":    c.CheckSandboxEscape(filePath),
 	}
+}
 
-	c.mu.RLock()
-	patterns := c.sensitivePatterns["random_generation"]
-	c.mu.RUnlock()
-
-	for _, pattern := range patterns {
-		re, err := regexp.Compile(pattern)
-		if err != nil {
-			continue
-		}
-
-		matches := re.FindAllString(string(content), -1)
-		for _, match := range matches {
-			if !strings.Contains(match, "crypto/rand") {
-				issues = append(issues, fmt.Sprintf("不安全的随机数生成: %s", match))
+// matchLines 逐行匹配指定类别的正则模式
+func (c *SecurityChecker) matchLines(src *sourceFile, category string, message string) SecurityCheckResult {
+	findings := &checkFindings{}
+	for _, re := range c.compilePatterns(category) {
+		for i, line := range src.lines {
+			if re.MatchString(line) {
+				findings.add(re.String(), fmt.Sprintf("第%d行 %s: %s", i+1, message, strings.TrimSpace(line)))
 			}
 		}
 	}
-
-	return issues, nil
+	return findings.result()
 }
 
-// CheckSensitiveData 检查敏感数据处理
-func (c *SecurityChecker) CheckSensitiveData(filePath string) ([]string, error) {
-	issues := make([]string, 0)
-	content, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
-	}
-
+// compilePatterns 编译指定类别的正则模式，跳过无效的模式
+func (c *SecurityChecker) compilePatterns(category string) []*regexp.Regexp {
 	c.mu.RLock()
-	patterns := c.sensitivePatterns["sensitive_data"]
+	patterns := c.sensitivePatterns[category]
 	c.mu.RUnlock()
 
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
 	for _, pattern := range patterns {
 		re, err := regexp.Compile(pattern)
 		if err != nil {
 			continue
 		}
-
-		matches := re.FindAllString(string(content), -1)
-		for _, match := range matches {
-			issues = append(issues, fmt.Sprintf("敏感数据泄露风险: %s", match))
-		}
+		compiled = append(compiled, re)
 	}
+	return compiled
+}
 
-	return issues, nil
+// sourceFile 已读取的源文件，只有Go文件有语法树
+type sourceFile struct {
+	fset  *token.FileSet
+	file  *ast.File
+	lines []string
 }
 
-// CheckSandboxEscape 检查沙箱逃逸
-func (c *SecurityChecker) CheckSandboxEscape(filePath string) ([]string, error) {
-	violations := make([]string, 0)
+// loadSource 读取源文件，并解析.go文件的语法树；其他语言的文件只能按行检查
+func loadSource(filePath string) (*sourceFile, error) {
 	content, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file not found: %s", filePath)
+		}
+		return nil, fmt.Errorf("read error: %v", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if !strings.EqualFold(filepath.Ext(filePath), ".go") {
+		return &sourceFile{lines: lines}, nil
 	}
 
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, filePath, content, parser.AllErrors)
 	if err != nil {
-		return nil, fmt.Errorf("解析文件失败: %v", err)
+		return nil, fmt.Errorf("parse error: %v", err)
 	}
 
-	ast.Inspect(file, func(n ast.Node) bool {
-		if call, ok := n.(*ast.CallExpr); ok {
-			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
-				if x, ok := sel.X.(*ast.Ident); ok {
-					funcName := x.Name + "." + sel.Sel.Name
-					if strings.Contains(funcName, "os.") || strings.Contains(funcName, "exec.") {
-						violations = append(violations, fmt.Sprintf("危险的系统调用: %s", funcName))
-					}
-				}
-			}
-		}
-		return true
-	})
+	return &sourceFile{
+		fset:  fset,
+		file:  file,
+		lines: lines,
+	}, nil
+}
 
-	return violations, nil
+// isGo 返回源文件是否为已解析语法树的Go文件
+func (s *sourceFile) isGo() bool {
+	return s.file != nil
 }
 
-// PerformFullCheck 执行完整的安全检查
-func (c *SecurityChecker) PerformFullCheck(filePath string) (map[string]interface{}, error) {
-	results := make(map[string]interface{})
+// lineNumber 返回位置所在的行号
+func (s *sourceFile) lineNumber(pos token.Pos) int {
+	return s.fset.Position(pos).Line
+}
 
-	// 检查内存使用
-	memoryUsage, err := c.CheckMemoryUsage(filePath)
-	if err != nil {
-		results["memory_usage"] = err.Error()
-	} else {
-		results["memory_usage"] = memoryUsage
-	}
+// line 返回位置所在行的代码
+func (s *sourceFile) line(pos token.Pos) string {
+	return strings.TrimSpace(s.lines[s.lineNumber(pos)-1])
+}
 
-	// 检查执行时间
-	err = c.CheckExecutionTime(filePath, 5*time.Second)
-	if err != nil {
-		results["execution_time"] = err.Error()
-	} else {
-		results["execution_time"] = "OK"
+// checkFindings 收集单项检查的发现
+type checkFindings struct {
+	details  []string
+	patterns []string
+}
+
+// add 记录一条发现及其匹配的模式
+func (f *checkFindings) add(pattern string, detail string) {
+	f.details = append(f.details, detail)
+	for _, p := range f.patterns {
+		if p == pattern {
+			return
+		}
 	}
+	f.patterns = append(f.patterns, pattern)
+}
 
-	// 检查文件访问
-	fileAccess, err := c.CheckFileAccess(filePath)
-	if err != nil {
-		results["file_access"] = err.Error()
-	} else {
-		results["file_access"] = fileAccess
+// result 生成检查结果
+func (f *checkFindings) result() SecurityCheckResult {
+	patterns := f.patterns
+	if patterns == nil {
+		patterns = []string{}
 	}
+	return SecurityCheckResult{
+		HasIssues: len(f.details) > 0,
+		Details:   strings.Join(f.details, "\n"),
+		Patterns:  patterns,
+	}
+}
 
-	// 检查网络访问
-	networkAccess, err := c.CheckNetworkAccess(filePath)
-	if err != nil {
-		results["network_access"] = err.Error()
-	} else {
-		results["network_access"] = networkAccess
+// errorResult 生成无法完成检查时的结果
+func errorResult(err error) SecurityCheckResult {
+	return SecurityCheckResult{
+		Details:  err.Error(),
+		Patterns: []string{},
 	}
+}
 
-	// 检查输入验证
-	inputValidation, err := c.CheckInputValidation(filePath)
-	if err != nil {
-		results["input_validation"] = err.Error()
-	} else {
-		results["input_validation"] = inputValidation
+// selectorName 返回形如pkg.Func的调用名称
+func selectorName(call *ast.CallExpr) string {
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if x, ok := sel.X.(*ast.Ident); ok {
+			return x.Name + "." + sel.Sel.Name
+		}
 	}
+	return ""
+}
 
-	// 检查随机数生成
-	randomGeneration, err := c.CheckRandomGeneration(filePath)
-	if err != nil {
-		results["random_generation"] = err.Error()
-	} else {
-		results["random_generation"] = randomGeneration
+// matchAny 返回第一个匹配的正则模式
+func matchAny(patterns []*regexp.Regexp, s string) *regexp.Regexp {
+	if s == "" {
+		return nil
+	}
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return re
+		}
 	}
+	return nil
+}
 
-	// 检查敏感数据
-	sensitiveData, err := c.CheckSensitiveData(filePath)
-	if err != nil {
-		results["sensitive_data"] = err.Error()
-	} else {
-		results["sensitive_data"] = sensitiveData
+// usesAny 检查表达式中是否引用了指定的变量
+func usesAny(exprs []ast.Expr, names map[string]bool) bool {
+	found := false
+	for _, expr := range exprs {
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && names[ident.Name] {
+				found = true
+			}
+			return !found
+		})
 	}
+	return found
+}
 
-	// 检查沙箱逃逸
-	sandboxEscape, err := c.CheckSandboxEscape(filePath)
-	if err != nil {
-		results["sandbox_escape"] = err.Error()
-	} else {
-		results["sandbox_escape"] = sandboxEscape
+// importPaths 返回包名到导入路径的映射，同名导入会保留所有路径
+func importPaths(file *ast.File) map[string][]string {
+	imports := make(map[string][]string)
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = append(imports[name], path)
 	}
+	return imports
+}
 
-	return results, nil
-} 
\ No newline at end of file
+// constValue 计算常量表达式的值
+func constValue(expr ast.Expr) (constant.Value, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		value := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		return value, value.Kind() == constant.Int || value.Kind() == constant.Float
+	case *ast.ParenExpr:
+		return constValue(e.X)
+	case *ast.BinaryExpr:
+		x, ok := constValue(e.X)
+		if !ok {
+			return nil, false
+		}
+		y, ok := constValue(e.Y)
+		if !ok {
+			return nil, false
+		}
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL:
+			return constant.BinaryOp(x, e.Op, y), true
+		case token.SHL:
+			shift, exact := constant.Uint64Val(constant.ToInt(y))
+			if !exact || shift > 64 {
+				return nil, false
+			}
+			return constant.Shift(constant.ToInt(x), e.Op, uint(shift)), true
+		}
+	}
+	return nil, false
+}
@@ -2,8 +2,8 @@ package utils
 
 import (
 	"os"
+	"strings"
 	"testing"
-	"time"
 )
 
 func TestNewSecurityChecker(t *testing.T) {
@@ -25,7 +25,11 @@ func TestNewSecurityChecker(t *testing.T) {
 }
 
 func createTestFile(content string) (string, error) {
-	tmpfile, err := os.CreateTemp("", "test_*.go")
+	return createTestFileExt(content, ".go")
+}
+
+func createTestFileExt(content string, ext string) (string, error) {
+	tmpfile, err := os.CreateTemp("", "test_*"+ext)
 	if err != nil {
 		return "", err
 	}
@@ -50,11 +54,9 @@ func TestCheckMemoryUsage(t *testing.T) {
 import "fmt"
 
 func main() {
-	var arr []int
-	for i := 0; i < 1000; i++ {
-		arr = append(arr, i)
-	}
-	fmt.Println(arr)
+	small := make([]int, 1000)
+	large := make([]int, 1<<24)
+	fmt.Println(small, large)
 }`
 
 	filename, err := createTestFile(content)
@@ -63,13 +65,13 @@ func main() {
 	}
 	defer os.Remove(filename)
 
-	usage, err := checker.CheckMemoryUsage(filename)
-	if err != nil {
-		t.Errorf("检查内存使用失败: %v", err)
+	result := checker.CheckMemoryUsage(filename)
+	if !result.HasIssues {
+		t.Error("应该检测到大内存分配")
 	}
 
-	if usage == 0 {
-		t.Error("内存使用量不应为0")
+	if strings.Contains(result.Details, "small") {
+		t.Errorf("小内存分配不应被报告: %s", result.Details)
 	}
 }
 
@@ -89,16 +91,9 @@ func main() {
 	}
 	defer os.Remove(filename)
 
-	// 测试正常超时
-	err = checker.CheckExecutionTime(filename, 5*time.Second)
-	if err != nil {
-		t.Errorf("执行时间检查失败: %v", err)
-	}
-
-	// 测试超时情况
-	err = checker.CheckExecutionTime(filename, 1*time.Millisecond)
-	if err == nil {
-		t.Error("预期应该发生超时错误")
+	result := checker.CheckExecutionTime(filename)
+	if !result.HasIssues {
+		t.Error("应该检测到长时间等待")
 	}
 }
 
@@ -122,12 +117,8 @@ func main() {
 	}
 	defer os.Remove(filename)
 
-	violations, err := checker.CheckFileAccess(filename)
-	if err != nil {
-		t.Errorf("文件访问检查失败: %v", err)
-	}
-
-	if len(violations) == 0 {
+	result := checker.CheckFileAccess(filename)
+	if !result.HasIssues || len(result.Patterns) == 0 {
 		t.Error("应该检测到文件访问违规")
 	}
 }
@@ -152,12 +143,8 @@ func main() {
 	}
 	defer os.Remove(filename)
 
-	violations, err := checker.CheckNetworkAccess(filename)
-	if err != nil {
-		t.Errorf("网络访问检查失败: %v", err)
-	}
-
-	if len(violations) == 0 {
+	result := checker.CheckNetworkAccess(filename)
+	if !result.HasIssues || len(result.Patterns) == 0 {
 		t.Error("应该检测到网络访问违规")
 	}
 }
@@ -184,12 +171,8 @@ func main() {
 	}
 	defer os.Remove(filename)
 
-	issues, err := checker.CheckInputValidation(filename)
-	if err != nil {
-		t.Errorf("输入验证检查失败: %v", err)
-	}
-
-	if len(issues) == 0 {
+	result := checker.CheckInputValidation(filename)
+	if !result.HasIssues || len(result.Patterns) == 0 {
 		t.Error("应该检测到未验证的输入")
 	}
 }
@@ -214,12 +197,8 @@ func main() {
 	}
 	defer os.Remove(filename)
 
-	issues, err := checker.CheckRandomGeneration(filename)
-	if err != nil {
-		t.Errorf("随机数生成检查失败: %v", err)
-	}
-
-	if len(issues) == 0 {
+	result := checker.CheckRandomGeneration(filename)
+	if !result.HasIssues || len(result.Patterns) == 0 {
 		t.Error("应该检测到不安全的随机数生成")
 	}
 }
@@ -241,12 +220,8 @@ func main() {
 	}
 	defer os.Remove(filename)
 
-	issues, err := checker.CheckSensitiveData(filename)
-	if err != nil {
-		t.Errorf("敏感数据检查失败: %v", err)
-	}
-
-	if len(issues) == 0 {
+	result := checker.CheckSensitiveData(filename)
+	if !result.HasIssues || len(result.Patterns) == 0 {
 		t.Error("应该检测到敏感数据泄露风险")
 	}
 }
@@ -271,12 +246,8 @@ func main() {
 	}
 	defer os.Remove(filename)
 
-	violations, err := checker.CheckSandboxEscape(filename)
-	if err != nil {
-		t.Errorf("沙箱逃逸检查失败: %v", err)
-	}
-
-	if len(violations) == 0 {
+	result := checker.CheckSandboxEscape(filename)
+	if !result.HasIssues || len(result.Patterns) == 0 {
 		t.Error("应该检测到沙箱逃逸风险")
 	}
 }
@@ -306,10 +277,7 @@ func main() {
 	}
 	defer os.Remove(filename)
 
-	results, err := checker.PerformFullCheck(filename)
-	if err != nil {
-		t.Errorf("完整检查失败: %v", err)
-	}
+	results := checker.PerformFullCheck(filename)
 
 	expectedChecks := []string{
 		"memory_usage",
@@ -327,4 +295,55 @@ func main() {
 			t.Errorf("缺少检查结果: %s", check)
 		}
 	}
-} 
\ No newline at end of file
+}
+
+func TestPerformFullCheckOtherLanguages(t *testing.T) {
+	checker := NewSecurityChecker()
+	tests := []struct {
+		ext     string
+		content string
+	}{
+		{".py", `import subprocess
+
+password = "secret123"
+with open("config.json") as f:
+    config = f.read()
+name = input("Name: ")
+subprocess.call(name, shell=True)
+`},
+		{".js", `const fs = require('fs');
+const { exec } = require('child_process');
+
+const token = process.env.API_TOKEN;
+const config = fs.readFileSync('config.json');
+const name = process.argv[2];
+exec('ls ' + name);
+`},
+	}
+
+	for _, tt := range tests {
+		filename, err := createTestFileExt(tt.content, tt.ext)
+		if err != nil {
+			t.Fatalf("创建测试文件失败: %v", err)
+		}
+		defer os.Remove(filename)
+
+		results := checker.PerformFullCheck(filename)
+		for check, result := range results {
+			if strings.Contains(result.Details, "parse error") {
+				t.Errorf("%s文件的%s检查不应解析Go语法: %s", tt.ext, check, result.Details)
+			}
+		}
+
+		for _, check := range []string{"file_access", "sensitive_data", "input_validation", "sandbox_escape"} {
+			if !results[check].HasIssues {
+				t.Errorf("%s文件应该有%s检查结果", tt.ext, check)
+			}
+		}
+		for _, check := range []string{"memory_usage", "execution_time"} {
+			if results[check].HasIssues {
+				t.Errorf("%s文件不应有%s检查结果: %s", tt.ext, check, results[check].Details)
+			}
+		}
+	}
+}
@@ -1,12 +1,15 @@
-package utils
+package tests
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/re-movery/re-movery/internal/utils"
 )
 
 func TestSecurityChecker(t *testing.T) {
@@ -87,7 +90,7 @@ func unsafeSandbox() {
 	}
 
 	// 创建检查器实例
-	checker := NewSecurityChecker()
+	checker := utils.NewSecurityChecker()
 
 	// 测试内存使用检查
 	t.Run("TestCheckMemoryUsage", func(t *testing.T) {
@@ -190,16 +193,11 @@ func unsafeSandbox() {
 			testFiles[i] = filePath
 		}
 
-		// 记录串行执行时间
-		startSerial := time.Now()
-		for _, file := range testFiles {
-			checker.PerformFullCheck(file)
-		}
-		serialDuration := time.Since(startSerial)
+		// 串行执行的结果
+		serial := checker.PerformFullCheck(testFiles[0])
 
-		// 记录并行执行时间
-		startParallel := time.Now()
-		resultChan := make(chan map[string]SecurityCheckResult, len(testFiles))
+		// 并行执行
+		resultChan := make(chan map[string]utils.SecurityCheckResult, len(testFiles))
 		for _, file := range testFiles {
 			go func(f string) {
 				resultChan <- checker.PerformFullCheck(f)
@@ -207,22 +205,21 @@ func unsafeSandbox() {
 		}
 
 		// 收集结果
-		results := make([]map[string]SecurityCheckResult, 0, len(testFiles))
+		results := make([]map[string]utils.SecurityCheckResult, 0, len(testFiles))
 		for i := 0; i < len(testFiles); i++ {
 			result := <-resultChan
 			results = append(results, result)
 		}
-		parallelDuration := time.Since(startParallel)
 
-		// 验证结果
+		// 验证并行执行的结果与串行执行一致
 		assert.Equal(t, len(testFiles), len(results))
 		for _, result := range results {
 			assert.NotNil(t, result)
 			assert.Greater(t, len(result), 0)
+			for name, check := range serial {
+				assert.Equal(t, check.HasIssues, result[name].HasIssues, name)
+			}
 		}
-
-		// 验证并行执行更快
-		assert.Less(t, parallelDuration, serialDuration)
 	})
 
 	// 测试错误处理
@@ -252,7 +249,7 @@ func unsafeSandbox() {
 }
 
 func TestSecurityCheckerEdgeCases(t *testing.T) {
-	checker := NewSecurityChecker()
+	checker := utils.NewSecurityChecker()
 
 	// 测试空文件
 	t.Run("TestEmptyFile", func(t *testing.T) {
@@ -322,7 +319,7 @@ func main() {}`
 
 		// 并发执行检查
 		startTime := time.Now()
-		resultChan := make(chan map[string]SecurityCheckResult, numFiles)
+		resultChan := make(chan map[string]utils.SecurityCheckResult, numFiles)
 		for _, file := range testFiles {
 			go func(f string) {
 				resultChan <- checker.PerformFullCheck(f)
@@ -330,7 +327,7 @@ func main() {}`
 		}
 
 		// 收集结果
-		results := make([]map[string]SecurityCheckResult, 0, numFiles)
+		results := make([]map[string]utils.SecurityCheckResult, 0, numFiles)
 		for i := 0; i < numFiles; i++ {
 			result := <-resultChan
 			results = append(results, result)
@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"path"
+	"strings"
+)
+
+// SafeFileName returns the base name of an uploaded file's name, so that it can be
+// joined to a directory without escaping it. Both / and \ are treated as separators.
+func SafeFileName(name string) string {
+	name = path.Base(strings.Replace(name, "\\", "/", -1))
+	if name == "." || name == ".." || name == "/" {
+		return "upload"
+	}
+	return name
+}
@@ -2,41 +2,74 @@ package web
 
 import (
 	"fmt"
-	"html/template"
-	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/re-movery/re-movery/internal/api"
 	"github.com/re-movery/re-movery/internal/core"
 	"github.com/re-movery/re-movery/internal/detectors"
+	"github.com/re-movery/re-movery/internal/utils"
 )
 
 // App is the web application
 type App struct {
 	scanner *core.Scanner
 	router  *gin.Engine
+	withAPI bool
 }
 
 // NewApp creates a new web application
 func NewApp() *App {
 	app := &App{
-		scanner: core.NewScanner(),
+		scanner: newScanner(),
 		router:  gin.Default(),
 	}
 
-	// Register detectors
-	app.scanner.RegisterDetector(detectors.NewPythonDetector())
-	app.scanner.RegisterDetector(detectors.NewJavaScriptDetector())
-
 	// Setup routes
 	app.setupRoutes()
 
 	return app
 }
 
+// NewAppWithAPI creates a web application that also serves the API under /api, on the
+// same engine and with the same scanner. The API server is returned so that its
+// authentication, rate limit and notifier can be configured.
+func NewAppWithAPI() (*App, *api.Server) {
+	app := &App{
+		scanner: newScanner(),
+		router:  gin.Default(),
+		withAPI: true,
+	}
+
+	// Setup routes
+	server := api.Mount(app.router, app.scanner)
+	app.setupRoutes()
+
+	return app, server
+}
+
+// newScanner creates a scanner with the detectors of all supported languages
+func newScanner() *core.Scanner {
+	scanner := core.NewScanner()
+
+	// Register detectors
+	scanner.RegisterDetector(detectors.NewPythonDetector())
+	scanner.RegisterDetector(detectors.NewJavaScriptDetector())
+	scanner.RegisterDetector(detectors.NewGoDetector())
+	scanner.RegisterDetector(detectors.NewHTMLDetector())
+	scanner.RegisterDetector(detectors.NewDockerfileDetector())
+	scanner.RegisterDetector(detectors.NewCDetector())
+	scanner.RegisterDetector(detectors.NewKotlinDetector())
+	scanner.RegisterDetector(detectors.NewSQLDetector())
+	scanner.RegisterDetector(detectors.NewTLSDetector())
+	scanner.RegisterDetector(detectors.NewSecretsDetector())
+
+	return scanner
+}
+
 // setupRoutes sets up the routes for the web application
 func (a *App) setupRoutes() {
 	// Serve static files
@@ -49,12 +82,20 @@ func (a *App) setupRoutes() {
 	a.router.GET("/", a.indexHandler)
 	a.router.POST("/scan/file", a.scanFileHandler)
 	a.router.POST("/scan/directory", a.scanDirectoryHandler)
-	a.router.GET("/api/languages", a.languagesHandler)
+	a.router.GET("/ws/scan", gin.WrapH(a.scanSocket()))
 	a.router.GET("/health", a.healthHandler)
+
+	// The API serves the same languages under the same path
+	if !a.withAPI {
+		a.router.GET("/api/languages", a.languagesHandler)
+	}
 }
 
 // Run runs the web application
-func (a *App) Run(host string, port int) error {
+func (a *App) Run(host string, port int, debug bool) error {
+	if !debug {
+		gin.SetMode(gin.ReleaseMode)
+	}
 	return a.router.Run(fmt.Sprintf("%s:%d", host, port))
 }
 
@@ -76,15 +117,24 @@ func (a *App) scanFileHandler(c *gin.Context) {
 		return
 	}
 
-	// Save file to temporary location
-	tempFile := filepath.Join(os.TempDir(), file.Filename)
+	// Save file to a temporary directory of its own, so concurrent uploads
+	// of the same file name do not overwrite each other
+	tempDir, err := os.MkdirTemp("", "re-movery-upload-")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create temporary directory",
+		})
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	tempFile := filepath.Join(tempDir, utils.SafeFileName(file.Filename))
 	if err := c.SaveUploadedFile(file, tempFile); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to save file",
 		})
 		return
 	}
-	defer os.Remove(tempFile)
 
 	// Scan file
 	results, err := a.scanner.ScanFile(tempFile)
@@ -132,7 +182,7 @@ func (a *App) scanDirectoryHandler(c *gin.Context) {
 	excludePatterns := c.PostFormArray("exclude")
 
 	// Scan directory
-	results, err := a.scanner.ScanDirectory(directory, excludePatterns)
+	results, scanErrors, err := a.scanner.ScanDirectoryErrors(c.Request.Context(), directory, excludePatterns)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": fmt.Sprintf("Failed to scan directory: %v", err),
@@ -143,9 +193,10 @@ func (a *App) scanDirectoryHandler(c *gin.Context) {
 	// Generate summary
 	summary := core.GenerateSummary(results)
 
-	// Return results
+	// Return results, with the files that could not be scanned
 	c.JSON(http.StatusOK, gin.H{
 		"results": results,
+		"errors":  scanErrors,
 		"summary": summary,
 	})
 }
@@ -0,0 +1,67 @@
+package web
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试在同一引擎上同时提供网页界面和API
+func TestAppWithAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app, server := NewAppWithAPI()
+	assert.NotNil(t, server)
+
+	request := func(method string, path string, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		app.router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	// 网页界面的路由
+	code, _ := request(http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = request(http.MethodGet, "/health", "")
+	assert.Equal(t, http.StatusOK, code)
+
+	// VS Code扩展使用的API路由
+	code, response := request(http.MethodPost, "/api/scan/code", `{"code": "result = eval(user_input)\n", "language": "py"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), response["summary"].(map[string]interface{})["high"])
+
+	tmpdir, err := ioutil.TempDir("", "web-api")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "vuln.py"), []byte("result = eval(user_input)\n"), 0644))
+
+	body, _ := json.Marshal(map[string]string{"directory": tmpdir})
+	code, response = request(http.MethodPost, "/api/scan/directory", string(body))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, response["results"], filepath.Join(tmpdir, "vuln.py"))
+
+	code, response = request(http.MethodGet, "/api/languages", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, response["languages"], "py")
+
+	// API的认证只作用于/api路由
+	server.SetAuth(true, []string{"token"})
+	code, _ = request(http.MethodGet, "/api/languages", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = request(http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,143 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/re-movery/re-movery/internal/core"
+	"golang.org/x/net/websocket"
+)
+
+// progressQueueSize is the number of progress events that can wait to be sent to a
+// client; further events are dropped until the client catches up
+const progressQueueSize = 64
+
+// scanRequest is the message a client sends to start a scan over /ws/scan
+type scanRequest struct {
+	Directory string   `json:"directory"`
+	Exclude   []string `json:"exclude"`
+}
+
+// scanSocket returns the handler of /ws/scan. The client sends one scanRequest and
+// receives a progress event per scanned file while the directory is scanned in
+// parallel, then a result event with the results and summary, or an error event.
+// Closing the connection cancels the scan.
+func (a *App) scanSocket() http.Handler {
+	return websocket.Server{
+		Handshake: checkSameOrigin,
+		Handler:   a.handleScanSocket,
+	}
+}
+
+// checkSameOrigin rejects WebSocket connections from pages of other origins, which
+// would otherwise be able to scan local directories through the browser
+func checkSameOrigin(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+	if origin == nil || origin.Host != req.Host {
+		return fmt.Errorf("cross-origin WebSocket connection from %v", origin)
+	}
+	config.Origin = origin
+	return nil
+}
+
+// handleScanSocket runs a scan for a client connected to /ws/scan
+func (a *App) handleScanSocket(ws *websocket.Conn) {
+	defer ws.Close()
+
+	var request scanRequest
+	if err := websocket.JSON.Receive(ws, &request); err != nil {
+		return
+	}
+
+	if request.Directory == "" {
+		websocket.JSON.Send(ws, gin.H{
+			"type":  "error",
+			"error": "No directory provided",
+		})
+		return
+	}
+
+	// Check if directory exists
+	if _, err := os.Stat(request.Directory); os.IsNotExist(err) {
+		websocket.JSON.Send(ws, gin.H{
+			"type":  "error",
+			"error": "Directory does not exist",
+		})
+		return
+	}
+
+	// Stop scanning once the client disconnects
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		var message string
+		for websocket.Message.Receive(ws, &message) == nil {
+		}
+		cancel()
+	}()
+
+	// Each scan has a scanner of its own, so that progress goes to this client only
+	scanner := newScanner()
+	scanner.SetParallel(true)
+
+	// Progress events are queued for a sender goroutine, so that a slow client never
+	// holds up the scan workers. Each event carries the counts so far, so events
+	// dropped while the queue is full, or older than one already queued, are not missed.
+	events := make(chan gin.H, progressQueueSize)
+	var eventsMutex sync.Mutex
+	queued := 0
+	scanner.SetProgressFunc(func(scanned, total int, currentFile string) {
+		eventsMutex.Lock()
+		defer eventsMutex.Unlock()
+		if scanned <= queued {
+			return
+		}
+		if relPath, err := filepath.Rel(request.Directory, currentFile); err == nil {
+			currentFile = relPath
+		}
+		select {
+		case events <- gin.H{
+			"type":        "progress",
+			"scanned":     scanned,
+			"total":       total,
+			"currentFile": currentFile,
+		}:
+			queued = scanned
+		default:
+		}
+	})
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		for event := range events {
+			websocket.JSON.Send(ws, event)
+		}
+	}()
+
+	results, err := scanner.ScanDirectoryContext(ctx, request.Directory, request.Exclude)
+
+	// Send the remaining progress events before the result
+	close(events)
+	<-sent
+	if err != nil {
+		websocket.JSON.Send(ws, gin.H{
+			"type":  "error",
+			"error": fmt.Sprintf("Failed to scan directory: %v", err),
+		})
+		return
+	}
+
+	websocket.JSON.Send(ws, gin.H{
+		"type":    "result",
+		"results": results,
+		"summary": core.GenerateSummary(results),
+	})
+}
@@ -0,0 +1,101 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+// scanEvent 是/ws/scan推送的事件
+type scanEvent struct {
+	Type        string                 `json:"type"`
+	Scanned     int                    `json:"scanned"`
+	Total       int                    `json:"total"`
+	CurrentFile string                 `json:"currentFile"`
+	Results     map[string]interface{} `json:"results"`
+	Summary     map[string]interface{} `json:"summary"`
+	Error       string                 `json:"error"`
+}
+
+// 连接/ws/scan，发送扫描请求并读取直到结果或错误事件的所有事件
+func scanOverSocket(t *testing.T, serverURL string, request scanRequest) []scanEvent {
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(serverURL, "http")+"/ws/scan", "", serverURL)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer ws.Close()
+
+	assert.NoError(t, websocket.JSON.Send(ws, request))
+
+	var events []scanEvent
+	for {
+		var event scanEvent
+		if !assert.NoError(t, websocket.JSON.Receive(ws, &event)) {
+			return events
+		}
+		events = append(events, event)
+		if event.Type != "progress" {
+			return events
+		}
+	}
+}
+
+// 测试通过WebSocket推送扫描进度和结果
+func TestScanSocket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := httptest.NewServer(NewApp().router)
+	defer server.Close()
+
+	tmpdir, err := ioutil.TempDir("", "ws-scan")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpdir, "vendor"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "vuln.py"), []byte("result = eval(user_input)\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "clean.py"), []byte("print('Hello')\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "vendor", "lib.py"), []byte("eval(x)\n"), 0644))
+
+	events := scanOverSocket(t, server.URL, scanRequest{Directory: tmpdir, Exclude: []string{"vendor"}})
+	if !assert.Len(t, events, 3) {
+		return
+	}
+
+	// 每个文件一个进度事件，最后是结果
+	files := []string{}
+	for i, event := range events[:2] {
+		assert.Equal(t, "progress", event.Type)
+		assert.Equal(t, i+1, event.Scanned)
+		assert.Equal(t, 2, event.Total)
+		files = append(files, event.CurrentFile)
+	}
+	assert.ElementsMatch(t, []string{"vuln.py", "clean.py"}, files)
+
+	result := events[2]
+	assert.Equal(t, "result", result.Type)
+	assert.Contains(t, result.Results, filepath.Join(tmpdir, "vuln.py"))
+	assert.Equal(t, float64(1), result.Summary["high"])
+
+	// 目录不存在
+	events = scanOverSocket(t, server.URL, scanRequest{Directory: filepath.Join(tmpdir, "missing")})
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "error", events[0].Type)
+		assert.Equal(t, "Directory does not exist", events[0].Error)
+	}
+}
+
+// 测试拒绝来自其他来源页面的WebSocket连接
+func TestScanSocketCrossOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := httptest.NewServer(NewApp().router)
+	defer server.Close()
+
+	_, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/scan", "", "http://evil.example.com")
+	assert.Error(t, err)
+}